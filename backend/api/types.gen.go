@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 const (
@@ -19,6 +20,12 @@ const (
 	Business BusinessUserRole = "business"
 )
 
+// Defines values for CourierRouteStopType.
+const (
+	Dropoff CourierRouteStopType = "dropoff"
+	Pickup  CourierRouteStopType = "pickup"
+)
+
 // Defines values for CourierUserRole.
 const (
 	Courier CourierUserRole = "courier"
@@ -28,92 +35,615 @@ const (
 const (
 	DeliveryStatusAccepted  DeliveryStatus = "accepted"
 	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusExpired   DeliveryStatus = "expired"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
 	DeliveryStatusPickedUp  DeliveryStatus = "picked_up"
 	DeliveryStatusPosted    DeliveryStatus = "posted"
 )
 
+// Defines values for DeliveryAutoActionKind.
+const (
+	DeliveryAutoActionKindExpired  DeliveryAutoActionKind = "expired"
+	DeliveryAutoActionKindReminded DeliveryAutoActionKind = "reminded"
+	DeliveryAutoActionKindRepriced DeliveryAutoActionKind = "repriced"
+)
+
 // Defines values for DeliveryPatchStatus.
 const (
 	DeliveryPatchStatusAccepted  DeliveryPatchStatus = "accepted"
 	DeliveryPatchStatusDelivered DeliveryPatchStatus = "delivered"
+	DeliveryPatchStatusFailed    DeliveryPatchStatus = "failed"
 	DeliveryPatchStatusPickedUp  DeliveryPatchStatus = "picked_up"
 )
 
+// Defines values for DeliverySize.
+const (
+	Large  DeliverySize = "large"
+	Medium DeliverySize = "medium"
+	Small  DeliverySize = "small"
+	Xlarge DeliverySize = "xlarge"
+)
+
+// Defines values for EarningsReportGroupBy.
+const (
+	EarningsReportGroupByDay   EarningsReportGroupBy = "day"
+	EarningsReportGroupByMonth EarningsReportGroupBy = "month"
+	EarningsReportGroupByWeek  EarningsReportGroupBy = "week"
+)
+
+// Defines values for NotificationEvent.
+const (
+	NotificationEventDeliveryAccepted      NotificationEvent = "delivery.accepted"
+	NotificationEventDeliveryDelivered     NotificationEvent = "delivery.delivered"
+	NotificationEventDeliveryExpired       NotificationEvent = "delivery.expired"
+	NotificationEventDeliveryFailed        NotificationEvent = "delivery.failed"
+	NotificationEventDeliveryParcelMissing NotificationEvent = "delivery.parcel_missing"
+	NotificationEventDeliveryPickedUp      NotificationEvent = "delivery.picked_up"
+	NotificationEventDeliveryRepriced      NotificationEvent = "delivery.repriced"
+	NotificationEventDeliveryUnaccepted    NotificationEvent = "delivery.unaccepted"
+	NotificationEventJobPosted             NotificationEvent = "job.posted"
+)
+
+// Defines values for ParcelStatus.
+const (
+	ParcelStatusDelivered ParcelStatus = "delivered"
+	ParcelStatusMissing   ParcelStatus = "missing"
+	ParcelStatusPending   ParcelStatus = "pending"
+	ParcelStatusPickedUp  ParcelStatus = "picked_up"
+)
+
+// Defines values for ParcelScanEvent.
+const (
+	ParcelScanEventDelivered ParcelScanEvent = "delivered"
+	ParcelScanEventMissing   ParcelScanEvent = "missing"
+	ParcelScanEventPickedUp  ParcelScanEvent = "picked_up"
+)
+
+// Defines values for PayoutStatus.
+const (
+	PayoutStatusApproved PayoutStatus = "approved"
+	PayoutStatusPaid     PayoutStatus = "paid"
+	PayoutStatusPending  PayoutStatus = "pending"
+	PayoutStatusRejected PayoutStatus = "rejected"
+)
+
+// Defines values for TrackingStatus.
+const (
+	TrackingStatusAccepted  TrackingStatus = "accepted"
+	TrackingStatusDelivered TrackingStatus = "delivered"
+	TrackingStatusExpired   TrackingStatus = "expired"
+	TrackingStatusFailed    TrackingStatus = "failed"
+	TrackingStatusPickedUp  TrackingStatus = "picked_up"
+	TrackingStatusPosted    TrackingStatus = "posted"
+)
+
+// Defines values for VehicleType.
+const (
+	Bicycle   VehicleType = "bicycle"
+	Car       VehicleType = "car"
+	CargoBike VehicleType = "cargo_bike"
+	Scooter   VehicleType = "scooter"
+	Van       VehicleType = "van"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "dead"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+)
+
+// Defines values for WebhookEvent.
+const (
+	WebhookEventDeliveryAccepted  WebhookEvent = "delivery.accepted"
+	WebhookEventDeliveryDelivered WebhookEvent = "delivery.delivered"
+	WebhookEventDeliveryPickedUp  WebhookEvent = "delivery.picked_up"
+)
+
+// Defines values for ZonePolygonType.
+const (
+	Polygon ZonePolygonType = "Polygon"
+)
+
+// Defines values for GetMyInvoiceParamsFormat.
+const (
+	GetMyInvoiceParamsFormatJson GetMyInvoiceParamsFormat = "json"
+	GetMyInvoiceParamsFormatPdf  GetMyInvoiceParamsFormat = "pdf"
+)
+
+// Defines values for GetMyEarningsParamsGroupBy.
+const (
+	GetMyEarningsParamsGroupByDay   GetMyEarningsParamsGroupBy = "day"
+	GetMyEarningsParamsGroupByMonth GetMyEarningsParamsGroupBy = "month"
+	GetMyEarningsParamsGroupByWeek  GetMyEarningsParamsGroupBy = "week"
+)
+
+// Defines values for GetMyStatementParamsFormat.
+const (
+	GetMyStatementParamsFormatCsv GetMyStatementParamsFormat = "csv"
+	GetMyStatementParamsFormatPdf GetMyStatementParamsFormat = "pdf"
+)
+
 // Defines values for ListDeliveriesParamsStatus.
 const (
 	Accepted  ListDeliveriesParamsStatus = "accepted"
 	Delivered ListDeliveriesParamsStatus = "delivered"
+	Expired   ListDeliveriesParamsStatus = "expired"
+	Failed    ListDeliveriesParamsStatus = "failed"
 	PickedUp  ListDeliveriesParamsStatus = "picked_up"
 	Posted    ListDeliveriesParamsStatus = "posted"
 )
 
+// Defines values for ListDeliveriesParamsSort.
+const (
+	CreatedAt ListDeliveriesParamsSort = "createdAt"
+	Distance  ListDeliveriesParamsSort = "distance"
+	Urgency   ListDeliveriesParamsSort = "urgency"
+)
+
+// Defines values for ListPayoutsParamsStatus.
+const (
+	Approved ListPayoutsParamsStatus = "approved"
+	Paid     ListPayoutsParamsStatus = "paid"
+	Pending  ListPayoutsParamsStatus = "pending"
+	Rejected ListPayoutsParamsStatus = "rejected"
+)
+
+// Defines values for ExportPayoutBatchParamsFormat.
+const (
+	Csv  ExportPayoutBatchParamsFormat = "csv"
+	Sepa ExportPayoutBatchParamsFormat = "sepa"
+)
+
+// BundleAccept defines model for BundleAccept.
+type BundleAccept struct {
+	DeliveryIds []string `firestore:"deliveryIds"`
+}
+
+// BusinessRepricing While enabled, a posted delivery nobody accepted for EXPIRY_AFTER has its payment
+// raised by step, again after each further EXPIRY_AFTER, as long as it stays at or
+// below maxPayment. After that the business is reminded, then the delivery expires.
+type BusinessRepricing struct {
+	Enabled    bool     `firestore:"enabled"`
+	MaxPayment *float64 `firestore:"maxPayment,omitempty"`
+	Step       *float64 `firestore:"step,omitempty"`
+}
+
 // BusinessUser defines model for BusinessUser.
 type BusinessUser struct {
-	BusinessAddress string           `firestore:"businessAddress"`
-	BusinessName    string           `firestore:"businessName"`
-	Email           string           `firestore:"email"`
-	Id              string           `firestore:"id"`
-	Location        GeoPoint         `firestore:"location"`
-	PlaceId         *string          `firestore:"placeId,omitempty"`
-	Role            BusinessUserRole `firestore:"role"`
+	BusinessAddress string   `firestore:"businessAddress"`
+	BusinessName    string   `firestore:"businessName"`
+	Email           string   `firestore:"email"`
+	Id              string   `firestore:"id"`
+	Location        GeoPoint `firestore:"location"`
+	PlaceId         *string  `firestore:"placeId,omitempty"`
+
+	// RatingAverage Average score of all ratings received (0 when unrated)
+	RatingAverage float64 `firestore:"ratingAverage"`
+	RatingCount   int     `firestore:"ratingCount"`
+
+	// Repricing Consent to raise the payment of deliveries no courier accepts
+	Repricing *BusinessRepricing `firestore:"repricing,omitempty"`
+	Role      BusinessUserRole   `firestore:"role"`
 }
 
 // BusinessUserRole defines model for BusinessUser.Role.
 type BusinessUserRole string
 
+// CourierActiveLimit defines model for CourierActiveLimit.
+type CourierActiveLimit struct {
+	// MaxActiveDeliveries Accepted and picked-up deliveries at once; null falls back to MAX_ACTIVE_DELIVERIES
+	MaxActiveDeliveries *int `firestore:"maxActiveDeliveries"`
+}
+
+// CourierLoad defines model for CourierLoad.
+type CourierLoad struct {
+	Jobs     int     `firestore:"jobs"`
+	VolumeL  float64 `firestore:"volumeL"`
+	WeightKg float64 `firestore:"weightKg"`
+}
+
+// CourierRoute defines model for CourierRoute.
+type CourierRoute struct {
+	DistanceKm      float64 `firestore:"distanceKm"`
+	DurationMinutes float64 `firestore:"durationMinutes"`
+
+	// Polyline Route geometry, encoded polyline (precision 5)
+	Polyline string `firestore:"polyline"`
+
+	// Provider Router that measured the route (haversine or osrm)
+	Provider string `firestore:"provider"`
+
+	// Start The courier's last known position; null when it never reported one
+	Start *GeoPoint          `firestore:"start"`
+	Stops []CourierRouteStop `firestore:"stops"`
+}
+
+// CourierRouteStop defines model for CourierRouteStop.
+type CourierRouteStop struct {
+	Address    string `firestore:"address"`
+	DeliveryId string `firestore:"deliveryId"`
+	Item       string `firestore:"item"`
+
+	// LegDistanceKm From the previous stop (or the start) to this one
+	LegDistanceKm      float64              `firestore:"legDistanceKm"`
+	LegDurationMinutes float64              `firestore:"legDurationMinutes"`
+	Location           GeoPoint             `firestore:"location"`
+	Type               CourierRouteStopType `firestore:"type"`
+}
+
+// CourierRouteStopType defines model for CourierRouteStop.Type.
+type CourierRouteStopType string
+
 // CourierUser defines model for CourierUser.
 type CourierUser struct {
-	CourierName string          `firestore:"courierName"`
-	Email       string          `firestore:"email"`
-	Id          string          `firestore:"id"`
-	Role        CourierUserRole `firestore:"role"`
-	Balance     float64         `firestore:"balance"`
+	// ActiveLoad What the courier carries on their accepted and picked-up deliveries
+	ActiveLoad  *CourierLoad `firestore:"activeLoad,omitempty"`
+	Balance     float64      `firestore:"balance"`
+	CourierName string       `firestore:"courierName"`
+	Email       string       `firestore:"email"`
+	Id          string       `firestore:"id"`
+
+	// MaxActiveDeliveries Admin override of MAX_ACTIVE_DELIVERIES for this courier; the vehicle's maxJobs
+	// still applies on top
+	MaxActiveDeliveries *int `firestore:"maxActiveDeliveries,omitempty"`
+
+	// RatingAverage Average score of all ratings received (0 when unrated)
+	RatingAverage float64 `firestore:"ratingAverage"`
+	RatingCount   int     `firestore:"ratingCount"`
+
+	// ReservedBalance Part of balance held by pending/approved payouts
+	ReservedBalance float64         `firestore:"reservedBalance"`
+	Role            CourierUserRole `firestore:"role"`
+
+	// TipsTotal Sum of all tips received, already included in balance
+	TipsTotal float64 `firestore:"tipsTotal"`
+
+	// Vehicle The courier's vehicle and what it carries at once. Capacities left out take the
+	// vehicle type's defaults, and can only be set lower than those.
+	Vehicle *CourierVehicle `firestore:"vehicle,omitempty"`
+
+	// ZoneIds Service zones the courier works in; none means everywhere
+	ZoneIds *[]string `firestore:"zoneIds,omitempty"`
 }
 
 // CourierUserRole defines model for CourierUser.Role.
 type CourierUserRole string
 
+// CourierVehicle The courier's vehicle and what it carries at once. Capacities left out take the
+// vehicle type's defaults, and can only be set lower than those.
+type CourierVehicle struct {
+	// MaxJobs Deliveries carried at the same time
+	MaxJobs     *int        `firestore:"maxJobs,omitempty"`
+	MaxVolumeL  *float64    `firestore:"maxVolumeL,omitempty"`
+	MaxWeightKg *float64    `firestore:"maxWeightKg,omitempty"`
+	Type        VehicleType `firestore:"type"`
+}
+
+// CourierZones defines model for CourierZones.
+type CourierZones struct {
+	ZoneIds []string `firestore:"zoneIds"`
+}
+
 // Delivery defines model for Delivery.
 type Delivery struct {
-	AssignedTo          *string        `firestore:"assignedTo"`
-	BusinessAddress     string         `firestore:"businessAddress"`
-	BusinessId          *string        `firestore:"businessId,omitempty"`
-	BusinessLocation    GeoPoint       `firestore:"businessLocation"`
-	BusinessName        string         `firestore:"businessName"`
-	CreatedAt           *time.Time     `firestore:"createdAt,omitempty"`
-	CreatedBy           *string        `firestore:"createdBy,omitempty"`
-	DeliveredBy         *string        `firestore:"deliveredBy"`
-	DestinationAddress  string         `firestore:"destinationAddress"`
-	DestinationLocation GeoPoint       `firestore:"destinationLocation"`
-	Id                  *string        `firestore:"id,omitempty"`
-	Item                string         `firestore:"item"`
-	Payment             float64        `firestore:"payment"`
-	Status              DeliveryStatus `firestore:"status"`
-}
-
-// DeliveryStatus defines model for Delivery.Status.
+	AcceptedAt *time.Time `firestore:"acceptedAt"`
+	AssignedTo *string    `firestore:"assignedTo"`
+
+	// AutoActions What was done automatically while no courier accepted it, oldest first
+	AutoActions      *[]DeliveryAutoAction `firestore:"autoActions,omitempty"`
+	BusinessAddress  string                `firestore:"businessAddress"`
+	BusinessId       *string               `firestore:"businessId,omitempty"`
+	BusinessLocation GeoPoint              `firestore:"businessLocation"`
+	BusinessName     string                `firestore:"businessName"`
+
+	// BusinessRating The courier's rating of the business
+	BusinessRating *Rating `firestore:"businessRating"`
+
+	// CourierRating The business's rating of the courier
+	CourierRating *Rating    `firestore:"courierRating"`
+	CreatedAt     *time.Time `firestore:"createdAt,omitempty"`
+	CreatedBy     *string    `firestore:"createdBy,omitempty"`
+
+	// DeliverBefore Drop-off deadline
+	DeliverBefore       *time.Time `firestore:"deliverBefore"`
+	DeliveredAt         *time.Time `firestore:"deliveredAt"`
+	DeliveredBy         *string    `firestore:"deliveredBy"`
+	DestinationAddress  string     `firestore:"destinationAddress"`
+	DestinationLocation GeoPoint   `firestore:"destinationLocation"`
+
+	// Eta Estimated pickup and drop-off while a courier is on it
+	Eta       *DeliveryEta `firestore:"eta"`
+	ExpiredAt *time.Time   `firestore:"expiredAt"`
+	FailedAt  *time.Time   `firestore:"failedAt"`
+	Id        *string      `firestore:"id,omitempty"`
+	Item      string       `firestore:"item"`
+
+	// Late Set on delivery; true when delivered after deliverBefore
+	Late *bool `firestore:"late,omitempty"`
+
+	// Parcels The parcels making up the delivery, when the business listed them
+	Parcels    *[]Parcel  `firestore:"parcels,omitempty"`
+	Payment    float64    `firestore:"payment"`
+	PickedUpAt *time.Time `firestore:"pickedUpAt"`
+
+	// PickupAfter Parcel is ready from then on
+	PickupAfter    *time.Time `firestore:"pickupAfter"`
+	RecipientEmail *string    `firestore:"recipientEmail,omitempty"`
+
+	// RecipientName Who receives the parcel at the destination
+	RecipientName *string `firestore:"recipientName,omitempty"`
+
+	// RecipientPhone E.164 number the courier can call on arrival
+	RecipientPhone *string `firestore:"recipientPhone,omitempty"`
+
+	// ReleaseAt When couriers start seeing a scheduled delivery (pickupAfter minus the lead time)
+	ReleaseAt *time.Time `firestore:"releaseAt"`
+
+	// ReleasedAt When a scheduled delivery was announced to couriers, at or shortly after releaseAt
+	ReleasedAt *time.Time `firestore:"releasedAt"`
+
+	// Size Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+	// (60 L), xlarge needs a car (200 L). Omitted means small.
+	Size *DeliverySize `firestore:"size,omitempty"`
+
+	// Status expired means no courier accepted it in time; it can't be accepted any more.
+	// failed means every parcel was reported missing, at pickup or on the way, so the
+	// courier gave it up unpaid.
+	Status DeliveryStatus `firestore:"status"`
+
+	// Tip Tip added by the business after delivery, paid on top of payment
+	Tip      float64    `firestore:"tip"`
+	TippedAt *time.Time `firestore:"tippedAt"`
+
+	// TrackingToken Unguessable token of the public tracking page (GET /track/{token})
+	TrackingToken *string `firestore:"trackingToken,omitempty"`
+
+	// WeightKg Parcel weight in kg
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+
+	// ZoneId Service zone containing the destination, when zones are configured
+	ZoneId *string `firestore:"zoneId,omitempty"`
+}
+
+// DeliveryStatus expired means no courier accepted it in time; it can't be accepted any more.
+// failed means every parcel was reported missing, at pickup or on the way, so the
+// courier gave it up unpaid.
 type DeliveryStatus string
 
+// DeliveryAutoAction One step taken on a posted delivery nobody accepted: repriced (payment raised by
+// the business's repricing step), reminded (the business was told) or expired.
+type DeliveryAutoAction struct {
+	At   time.Time              `firestore:"at"`
+	Kind DeliveryAutoActionKind `firestore:"kind"`
+
+	// Payment Payment after the action
+	Payment float64 `firestore:"payment"`
+}
+
+// DeliveryAutoActionKind defines model for DeliveryAutoAction.Kind.
+type DeliveryAutoActionKind string
+
+// DeliveryBundle defines model for DeliveryBundle.
+type DeliveryBundle struct {
+	Deliveries      []Delivery `firestore:"deliveries"`
+	DistanceKm      float64    `firestore:"distanceKm"`
+	DurationMinutes float64    `firestore:"durationMinutes"`
+
+	// Payment Sum of the deliveries' payments
+	Payment float64 `firestore:"payment"`
+
+	// SavedMinutes Time saved over riding the deliveries one after the other
+	SavedMinutes float64 `firestore:"savedMinutes"`
+
+	// Stops Suggested order of the bundle's pickups and drop-offs
+	Stops []CourierRouteStop `firestore:"stops"`
+}
+
 // DeliveryCreate defines model for DeliveryCreate.
 type DeliveryCreate struct {
-	BusinessAddress     string   `firestore:"businessAddress"`
-	BusinessLocation    GeoPoint `firestore:"businessLocation"`
-	BusinessName        string   `firestore:"businessName"`
-	DestinationAddress  string   `firestore:"destinationAddress"`
-	DestinationLocation GeoPoint `firestore:"destinationLocation"`
-	Item                string   `firestore:"item"`
-	Payment             float64  `firestore:"payment"`
+	BusinessAddress  string   `firestore:"businessAddress"`
+	BusinessLocation GeoPoint `firestore:"businessLocation"`
+	BusinessName     string   `firestore:"businessName"`
+
+	// DeliverBefore Drop-off deadline; must be in the future and after pickupAfter
+	DeliverBefore       *time.Time `firestore:"deliverBefore,omitempty"`
+	DestinationAddress  string     `firestore:"destinationAddress"`
+	DestinationLocation GeoPoint   `firestore:"destinationLocation"`
+	Item                string     `firestore:"item"`
+
+	// Parcels Parcels to scan one by one at pickup and drop-off; labels must be unique.
+	// Their weights add up to weightKg when that is left out.
+	Parcels *[]ParcelCreate `firestore:"parcels,omitempty"`
+	Payment float64         `firestore:"payment"`
+
+	// PickupAfter Schedule the pickup; couriers see the delivery from a lead time before
+	PickupAfter    *time.Time `firestore:"pickupAfter,omitempty"`
+	RecipientEmail *string    `firestore:"recipientEmail,omitempty"`
+	RecipientName  *string    `firestore:"recipientName,omitempty"`
+
+	// RecipientPhone E.164, e.g. +4915112345678
+	RecipientPhone *string `firestore:"recipientPhone,omitempty"`
+
+	// Size Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+	// (60 L), xlarge needs a car (200 L). Omitted means small.
+	Size *DeliverySize `firestore:"size,omitempty"`
+
+	// WeightKg Parcel weight in kg
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+}
+
+// DeliveryEta defines model for DeliveryEta.
+type DeliveryEta struct {
+	DropoffAt time.Time `firestore:"dropoffAt"`
+
+	// PickupAt Null once the parcel is picked up
+	PickupAt  *time.Time `firestore:"pickupAt"`
+	UpdatedAt time.Time  `firestore:"updatedAt"`
 }
 
-// DeliveryPatch defines model for DeliveryPatch.
+// DeliveryPatch Couriers send status; failed gives up an accepted or picked up delivery whose parcels
+// were all reported missing. The owning business may instead change item, destination
+// and payment while the delivery is posted and no courier accepted it.
 type DeliveryPatch struct {
-	AssignedTo *string              `firestore:"assignedTo"`
-	Status     *DeliveryPatchStatus `firestore:"status,omitempty"`
+	AssignedTo          *string              `firestore:"assignedTo"`
+	DestinationAddress  *string              `firestore:"destinationAddress,omitempty"`
+	DestinationLocation *GeoPoint            `firestore:"destinationLocation,omitempty"`
+	Item                *string              `firestore:"item,omitempty"`
+	Payment             *float64             `firestore:"payment,omitempty"`
+	Status              *DeliveryPatchStatus `firestore:"status,omitempty"`
 }
 
 // DeliveryPatchStatus defines model for DeliveryPatch.Status.
 type DeliveryPatchStatus string
 
+// DeliveryQuote defines model for DeliveryQuote.
+type DeliveryQuote struct {
+	// DistanceKm Travel distance pickup → destination
+	DistanceKm      float64 `firestore:"distanceKm"`
+	DurationMinutes float64 `firestore:"durationMinutes"`
+
+	// Payment Suggested payment for the courier
+	Payment float64 `firestore:"payment"`
+
+	// Polyline Route geometry, encoded polyline (precision 5)
+	Polyline string `firestore:"polyline"`
+
+	// Provider Router that measured the route (haversine or osrm)
+	Provider string `firestore:"provider"`
+
+	// ZoneId Service zone whose pricing applied
+	ZoneId *string `firestore:"zoneId,omitempty"`
+}
+
+// DeliveryQuoteRequest defines model for DeliveryQuoteRequest.
+type DeliveryQuoteRequest struct {
+	BusinessLocation    GeoPoint `firestore:"businessLocation"`
+	DestinationLocation GeoPoint `firestore:"destinationLocation"`
+}
+
+// DeliverySize Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+// (60 L), xlarge needs a car (200 L). Omitted means small.
+type DeliverySize string
+
+// DeliveryTemplate A delivery the business sends on a schedule. Each occurrence is posted as a
+// scheduled delivery (pickupAfter = the occurrence) from the business's address.
+type DeliveryTemplate struct {
+	BusinessId string    `firestore:"businessId"`
+	CreatedAt  time.Time `firestore:"createdAt"`
+
+	// DeliverWithinMinutes Each delivery's deliverBefore, in minutes after its pickup time
+	DeliverWithinMinutes *int     `firestore:"deliverWithinMinutes,omitempty"`
+	DestinationAddress   string   `firestore:"destinationAddress"`
+	DestinationLocation  GeoPoint `firestore:"destinationLocation"`
+	Id                   string   `firestore:"id"`
+	Item                 string   `firestore:"item"`
+	LastDeliveryId       *string  `firestore:"lastDeliveryId"`
+
+	// LastError Why the last occurrence couldn't be posted, if it couldn't
+	LastError *string    `firestore:"lastError"`
+	LastRunAt *time.Time `firestore:"lastRunAt"`
+	Name      string     `firestore:"name"`
+
+	// NextRunAt Pickup time of the next delivery to be created
+	NextRunAt      *time.Time `firestore:"nextRunAt"`
+	Paused         bool       `firestore:"paused"`
+	Payment        float64    `firestore:"payment"`
+	RecipientEmail *string    `firestore:"recipientEmail,omitempty"`
+	RecipientName  *string    `firestore:"recipientName,omitempty"`
+
+	// RecipientPhone E.164, e.g. +4915112345678
+	RecipientPhone *string `firestore:"recipientPhone,omitempty"`
+
+	// Recurrence Cron expression "minute hour day-of-month month day-of-week" in the template's
+	// time zone, e.g. "30 7 * * 1-5" for weekdays at 07:30. Fields take *, lists,
+	// ranges and steps; day-of-week 0 and 7 are Sunday.
+	Recurrence string `firestore:"recurrence"`
+
+	// Size Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+	// (60 L), xlarge needs a car (200 L). Omitted means small.
+	Size *DeliverySize `firestore:"size,omitempty"`
+
+	// Timezone IANA time zone of the recurrence (default UTC)
+	Timezone  string    `firestore:"timezone"`
+	UpdatedAt time.Time `firestore:"updatedAt"`
+
+	// WeightKg Parcel weight in kg
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+}
+
+// DeliveryTemplateCreate defines model for DeliveryTemplateCreate.
+type DeliveryTemplateCreate struct {
+	// DeliverWithinMinutes Each delivery's deliverBefore, in minutes after its pickup time
+	DeliverWithinMinutes *int     `firestore:"deliverWithinMinutes,omitempty"`
+	DestinationAddress   string   `firestore:"destinationAddress"`
+	DestinationLocation  GeoPoint `firestore:"destinationLocation"`
+	Item                 string   `firestore:"item"`
+	Name                 string   `firestore:"name"`
+	Payment              float64  `firestore:"payment"`
+	RecipientEmail       *string  `firestore:"recipientEmail,omitempty"`
+	RecipientName        *string  `firestore:"recipientName,omitempty"`
+
+	// RecipientPhone E.164, e.g. +4915112345678
+	RecipientPhone *string `firestore:"recipientPhone,omitempty"`
+
+	// Recurrence Cron expression "minute hour day-of-month month day-of-week" in the template's
+	// time zone, e.g. "30 7 * * 1-5" for weekdays at 07:30. Fields take *, lists,
+	// ranges and steps; day-of-week 0 and 7 are Sunday.
+	Recurrence string `firestore:"recurrence"`
+
+	// Size Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+	// (60 L), xlarge needs a car (200 L). Omitted means small.
+	Size *DeliverySize `firestore:"size,omitempty"`
+
+	// Timezone IANA time zone of the recurrence (default UTC)
+	Timezone *string `firestore:"timezone,omitempty"`
+
+	// WeightKg Parcel weight in kg
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+}
+
+// DeliveryTemplateOccurrence defines model for DeliveryTemplateOccurrence.
+type DeliveryTemplateOccurrence struct {
+	DeliverBefore *time.Time `firestore:"deliverBefore,omitempty"`
+	PickupAfter   time.Time  `firestore:"pickupAfter"`
+}
+
+// EarningsPeriod defines model for EarningsPeriod.
+type EarningsPeriod struct {
+	Adjustments float64 `firestore:"adjustments"`
+
+	// Deliveries Number of delivered jobs
+	Deliveries       int     `firestore:"deliveries"`
+	DeliveryEarnings float64 `firestore:"deliveryEarnings"`
+
+	// End Exclusive
+	End time.Time `firestore:"end"`
+
+	// Net deliveryEarnings + tips + adjustments - payouts
+	Net float64 `firestore:"net"`
+
+	// Payouts Paid out, as a positive amount
+	Payouts float64   `firestore:"payouts"`
+	Start   time.Time `firestore:"start"`
+	Tips    float64   `firestore:"tips"`
+}
+
+// EarningsReport defines model for EarningsReport.
+type EarningsReport struct {
+	From    time.Time             `firestore:"from"`
+	GroupBy EarningsReportGroupBy `firestore:"groupBy"`
+	Periods []EarningsPeriod      `firestore:"periods"`
+
+	// To Exclusive
+	To     time.Time      `firestore:"to"`
+	Totals EarningsPeriod `firestore:"totals"`
+}
+
+// EarningsReportGroupBy defines model for EarningsReport.GroupBy.
+type EarningsReportGroupBy string
+
 // Error defines model for Error.
 type Error struct {
 	Message *string `firestore:"message,omitempty"`
@@ -125,11 +655,357 @@ type GeoPoint struct {
 	Lng float64 `firestore:"lng"`
 }
 
+// Invoice defines model for Invoice.
+type Invoice struct {
+	BusinessId   string     `firestore:"businessId"`
+	BusinessName string     `firestore:"businessName"`
+	CreatedAt    *time.Time `firestore:"createdAt,omitempty"`
+	Currency     string     `firestore:"currency"`
+
+	// FeeRate Platform fee as a fraction of payment
+	FeeRate float64       `firestore:"feeRate"`
+	Fees    float64       `firestore:"fees"`
+	Id      *string       `firestore:"id,omitempty"`
+	Lines   []InvoiceLine `firestore:"lines"`
+
+	// Number Sequential per year, e.g. INV-2026-000042
+	Number string `firestore:"number"`
+
+	// PeriodEnd Exclusive
+	PeriodEnd   time.Time `firestore:"periodEnd"`
+	PeriodStart time.Time `firestore:"periodStart"`
+
+	// Subtotal Sum of delivery payments
+	Subtotal float64 `firestore:"subtotal"`
+	Tax      float64 `firestore:"tax"`
+
+	// TaxRate Tax rate applied to payment + fee
+	TaxRate float64 `firestore:"taxRate"`
+
+	// Tips Tips, passed on to couriers untaxed
+	Tips  float64 `firestore:"tips"`
+	Total float64 `firestore:"total"`
+}
+
+// InvoiceLine defines model for InvoiceLine.
+type InvoiceLine struct {
+	BusinessAddress    string    `firestore:"businessAddress"`
+	DeliveredAt        time.Time `firestore:"deliveredAt"`
+	DeliveryId         string    `firestore:"deliveryId"`
+	DestinationAddress string    `firestore:"destinationAddress"`
+	Fee                float64   `firestore:"fee"`
+	Item               string    `firestore:"item"`
+	Payment            float64   `firestore:"payment"`
+	Tax                float64   `firestore:"tax"`
+	Tip                float64   `firestore:"tip"`
+	Total              float64   `firestore:"total"`
+}
+
+// NotificationEvent defines model for NotificationEvent.
+type NotificationEvent string
+
+// NotificationPreferences defines model for NotificationPreferences.
+type NotificationPreferences struct {
+	// Email Send notifications to the account email
+	Email       bool                `firestore:"email"`
+	MutedEvents []NotificationEvent `firestore:"mutedEvents"`
+
+	// Phone E.164 number, e.g. +4915112345678
+	Phone *string `firestore:"phone"`
+
+	// Sms Send notifications as SMS to phone
+	Sms bool `firestore:"sms"`
+}
+
 // OneOfUser defines model for OneOfUser.
 type OneOfUser struct {
 	union json.RawMessage
 }
 
+// Parcel defines model for Parcel.
+type Parcel struct {
+	Description string `firestore:"description"`
+
+	// Label Barcode or label id, unique within the delivery
+	Label string `firestore:"label"`
+
+	// Scans Every scan of the parcel, oldest first
+	Scans []ParcelScan `firestore:"scans"`
+
+	// Status Result of the parcel's latest scan; pending until the first one
+	Status   ParcelStatus `firestore:"status"`
+	WeightKg *float64     `firestore:"weightKg,omitempty"`
+}
+
+// ParcelStatus Result of the parcel's latest scan; pending until the first one
+type ParcelStatus string
+
+// ParcelCreate defines model for ParcelCreate.
+type ParcelCreate struct {
+	Description string `firestore:"description"`
+
+	// Label Barcode or label id
+	Label    string   `firestore:"label"`
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+}
+
+// ParcelScan defines model for ParcelScan.
+type ParcelScan struct {
+	At        time.Time       `firestore:"at"`
+	CourierId string          `firestore:"courierId"`
+	Event     ParcelScanEvent `firestore:"event"`
+	Note      *string         `firestore:"note,omitempty"`
+}
+
+// ParcelScanCreate defines model for ParcelScanCreate.
+type ParcelScanCreate struct {
+	Event ParcelScanEvent `firestore:"event"`
+
+	// Note E.g. what the business said about a missing parcel
+	Note *string `firestore:"note,omitempty"`
+}
+
+// ParcelScanEvent defines model for ParcelScanEvent.
+type ParcelScanEvent string
+
+// Payout defines model for Payout.
+type Payout struct {
+	AccountHolder string       `firestore:"accountHolder"`
+	Amount        float64      `firestore:"amount"`
+	BatchId       *string      `firestore:"batchId"`
+	CourierId     string       `firestore:"courierId"`
+	CourierName   string       `firestore:"courierName"`
+	CreatedAt     *time.Time   `firestore:"createdAt,omitempty"`
+	Iban          string       `firestore:"iban"`
+	Id            *string      `firestore:"id,omitempty"`
+	Reason        *string      `firestore:"reason"`
+	ReviewedAt    *time.Time   `firestore:"reviewedAt"`
+	ReviewedBy    *string      `firestore:"reviewedBy"`
+	Status        PayoutStatus `firestore:"status"`
+}
+
+// PayoutStatus defines model for Payout.Status.
+type PayoutStatus string
+
+// PayoutBatch defines model for PayoutBatch.
+type PayoutBatch struct {
+	Count     int        `firestore:"count"`
+	CreatedAt *time.Time `firestore:"createdAt,omitempty"`
+	CreatedBy string     `firestore:"createdBy"`
+	Id        *string    `firestore:"id,omitempty"`
+	PayoutIds []string   `firestore:"payoutIds"`
+	Total     float64    `firestore:"total"`
+}
+
+// PayoutCreate defines model for PayoutCreate.
+type PayoutCreate struct {
+	AccountHolder string  `firestore:"accountHolder"`
+	Amount        float64 `firestore:"amount"`
+	Iban          string  `firestore:"iban"`
+}
+
+// PayoutReject defines model for PayoutReject.
+type PayoutReject struct {
+	Reason *string `firestore:"reason,omitempty"`
+}
+
+// PushSubscription defines model for PushSubscription.
+type PushSubscription struct {
+	CreatedAt *time.Time           `firestore:"createdAt,omitempty"`
+	Endpoint  string               `firestore:"endpoint"`
+	Id        *string              `firestore:"id,omitempty"`
+	Keys      PushSubscriptionKeys `firestore:"keys"`
+
+	// RadiusKm Push about new deliveries picked up this close to the courier's last known position
+	RadiusKm float64 `firestore:"radiusKm"`
+}
+
+// PushSubscriptionCreate defines model for PushSubscriptionCreate.
+type PushSubscriptionCreate struct {
+	Endpoint string               `firestore:"endpoint"`
+	Keys     PushSubscriptionKeys `firestore:"keys"`
+
+	// RadiusKm Defaults to PUSH_DEFAULT_RADIUS_KM
+	RadiusKm *float64 `firestore:"radiusKm,omitempty"`
+}
+
+// PushSubscriptionKeys defines model for PushSubscriptionKeys.
+type PushSubscriptionKeys struct {
+	// Auth Browser auth secret, base64url
+	Auth string `firestore:"auth"`
+
+	// P256dh Browser P-256 public key, base64url
+	P256dh string `firestore:"p256dh"`
+}
+
+// Rating defines model for Rating.
+type Rating struct {
+	Comment   *string   `firestore:"comment"`
+	CreatedAt time.Time `firestore:"createdAt"`
+	RatedBy   string    `firestore:"ratedBy"`
+	Score     int       `firestore:"score"`
+}
+
+// RatingCreate defines model for RatingCreate.
+type RatingCreate struct {
+	Comment *string `firestore:"comment,omitempty"`
+	Score   int     `firestore:"score"`
+}
+
+// TipCreate defines model for TipCreate.
+type TipCreate struct {
+	Amount float64 `firestore:"amount"`
+}
+
+// Tracking What the public tracking page may show; no addresses or contacts
+type Tracking struct {
+	BusinessName string `firestore:"businessName"`
+
+	// CourierPosition Courier position rounded to about 500 m, only while accepted or picked up
+	CourierPosition   *GeoPoint  `firestore:"courierPosition"`
+	CourierPositionAt *time.Time `firestore:"courierPositionAt"`
+	CreatedAt         time.Time  `firestore:"createdAt"`
+	DeliveredAt       *time.Time `firestore:"deliveredAt"`
+
+	// EstimatedArrival Expected arrival while the delivery is on its way
+	EstimatedArrival *time.Time     `firestore:"estimatedArrival"`
+	Item             string         `firestore:"item"`
+	Status           TrackingStatus `firestore:"status"`
+}
+
+// TrackingStatus defines model for Tracking.Status.
+type TrackingStatus string
+
+// VapidPublicKey defines model for VapidPublicKey.
+type VapidPublicKey struct {
+	// PublicKey Uncompressed P-256 public key, base64url
+	PublicKey string `firestore:"publicKey"`
+}
+
+// VehicleType defines model for VehicleType.
+type VehicleType string
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	BusinessId string         `firestore:"businessId"`
+	CreatedAt  *time.Time     `firestore:"createdAt,omitempty"`
+	Events     []WebhookEvent `firestore:"events"`
+	Id         *string        `firestore:"id,omitempty"`
+
+	// Secret HMAC-SHA256 key, only returned by createWebhook; X-Webhook-Signature is
+	// t=<unix>,v1=<hex hmac of "<unix>.<body>">. The body is
+	// {"id","type","createdAt","delivery"}, delivery using the camelCase field names
+	// of the Delivery schema.
+	Secret *string `firestore:"secret,omitempty"`
+	Url    string  `firestore:"url"`
+}
+
+// WebhookCreate defines model for WebhookCreate.
+type WebhookCreate struct {
+	Events []WebhookEvent `firestore:"events"`
+
+	// Secret Generated when omitted
+	Secret *string `firestore:"secret,omitempty"`
+	Url    string  `firestore:"url"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempts       int        `firestore:"attempts"`
+	BusinessId     string     `firestore:"businessId"`
+	CreatedAt      *time.Time `firestore:"createdAt,omitempty"`
+	DeliveredAt    *time.Time `firestore:"deliveredAt"`
+	DeliveryId     string     `firestore:"deliveryId"`
+	Event          string     `firestore:"event"`
+	Id             *string    `firestore:"id,omitempty"`
+	LastError      *string    `firestore:"lastError"`
+	LastStatusCode *int       `firestore:"lastStatusCode"`
+	NextAttemptAt  *time.Time `firestore:"nextAttemptAt"`
+
+	// Payload Exact JSON body sent on every attempt
+	Payload   string                `firestore:"payload"`
+	Status    WebhookDeliveryStatus `firestore:"status"`
+	WebhookId string                `firestore:"webhookId"`
+}
+
+// WebhookDeliveryStatus defines model for WebhookDelivery.Status.
+type WebhookDeliveryStatus string
+
+// WebhookEvent defines model for WebhookEvent.
+type WebhookEvent string
+
+// Zone defines model for Zone.
+type Zone struct {
+	// Active Inactive zones give no coverage
+	Active    bool      `firestore:"active"`
+	CreatedAt time.Time `firestore:"createdAt"`
+
+	// Hours When deliveries may be posted; none means always
+	Hours []ZoneHours `firestore:"hours"`
+	Id    string      `firestore:"id"`
+
+	// MinPayment Lowest payment a delivery may offer
+	MinPayment *float64 `firestore:"minPayment,omitempty"`
+	Name       string   `firestore:"name"`
+
+	// Polygon GeoJSON Polygon (RFC 7946): an outer ring then optional holes, each a closed
+	// list of [longitude, latitude] positions.
+	Polygon ZonePolygon `firestore:"polygon"`
+
+	// Pricing Quote rates in the zone; any left out use the PRICING_* defaults
+	Pricing *ZonePricing `firestore:"pricing,omitempty"`
+
+	// Timezone IANA time zone of the operating hours (default UTC)
+	Timezone  string    `firestore:"timezone"`
+	UpdatedAt time.Time `firestore:"updatedAt"`
+}
+
+// ZoneCreate defines model for ZoneCreate.
+type ZoneCreate struct {
+	// Active Defaults to true
+	Active     *bool        `firestore:"active,omitempty"`
+	Hours      *[]ZoneHours `firestore:"hours,omitempty"`
+	MinPayment *float64     `firestore:"minPayment,omitempty"`
+	Name       string       `firestore:"name"`
+
+	// Polygon GeoJSON Polygon (RFC 7946): an outer ring then optional holes, each a closed
+	// list of [longitude, latitude] positions.
+	Polygon ZonePolygon `firestore:"polygon"`
+
+	// Pricing Quote rates in the zone; any left out use the PRICING_* defaults
+	Pricing  *ZonePricing `firestore:"pricing,omitempty"`
+	Timezone *string      `firestore:"timezone,omitempty"`
+}
+
+// ZoneHours Opening window on the given ISO weekdays (1 = Monday). A close at or before the
+// open runs past midnight into the next day.
+type ZoneHours struct {
+	// Close HH:MM, 24:00 for end of day
+	Close string `firestore:"close"`
+	Days  []int  `firestore:"days"`
+
+	// Open HH:MM
+	Open string `firestore:"open"`
+}
+
+// ZonePolygon GeoJSON Polygon (RFC 7946): an outer ring then optional holes, each a closed
+// list of [longitude, latitude] positions.
+type ZonePolygon struct {
+	Coordinates [][][]float64   `firestore:"coordinates"`
+	Type        ZonePolygonType `firestore:"type"`
+}
+
+// ZonePolygonType defines model for ZonePolygon.Type.
+type ZonePolygonType string
+
+// ZonePricing Quote rates in the zone; any left out use the PRICING_* defaults
+type ZonePricing struct {
+	Base      *float64 `firestore:"base,omitempty"`
+	Min       *float64 `firestore:"min,omitempty"`
+	PerKm     *float64 `firestore:"perKm,omitempty"`
+	PerMinute *float64 `firestore:"perMinute,omitempty"`
+}
+
 // PageSize defines model for PageSize.
 type PageSize = int
 
@@ -142,6 +1018,46 @@ type NotFound = Error
 // Unauthorized defines model for Unauthorized.
 type Unauthorized = Error
 
+// GetMyInvoiceParams defines parameters for GetMyInvoice.
+type GetMyInvoiceParams struct {
+	// Format pdf (default) or json
+	Format *GetMyInvoiceParamsFormat `form:"format,omitempty" firestore:"format,omitempty"`
+}
+
+// GetMyInvoiceParamsFormat defines parameters for GetMyInvoice.
+type GetMyInvoiceParamsFormat string
+
+// ListLowRatedCouriersParams defines parameters for ListLowRatedCouriers.
+type ListLowRatedCouriersParams struct {
+	// Below Average rating threshold (default 3.5)
+	Below *float64 `form:"below,omitempty" firestore:"below,omitempty"`
+
+	// MinRatings Only couriers with at least this many ratings (default 3)
+	MinRatings *int `form:"minRatings,omitempty" firestore:"minRatings,omitempty"`
+}
+
+// GetMyEarningsParams defines parameters for GetMyEarnings.
+type GetMyEarningsParams struct {
+	// From First day included (UTC); defaults to 30 days before `to`
+	From *openapi_types.Date `form:"from,omitempty" firestore:"from,omitempty"`
+
+	// To Last day included (UTC); defaults to today
+	To      *openapi_types.Date         `form:"to,omitempty" firestore:"to,omitempty"`
+	GroupBy *GetMyEarningsParamsGroupBy `form:"groupBy,omitempty" firestore:"groupBy,omitempty"`
+}
+
+// GetMyEarningsParamsGroupBy defines parameters for GetMyEarnings.
+type GetMyEarningsParamsGroupBy string
+
+// GetMyStatementParams defines parameters for GetMyStatement.
+type GetMyStatementParams struct {
+	// Format pdf (default) or csv
+	Format *GetMyStatementParamsFormat `form:"format,omitempty" firestore:"format,omitempty"`
+}
+
+// GetMyStatementParamsFormat defines parameters for GetMyStatement.
+type GetMyStatementParamsFormat string
+
 // ListDeliveriesParams defines parameters for ListDeliveries.
 type ListDeliveriesParams struct {
 	Status *ListDeliveriesParamsStatus `form:"status,omitempty" firestore:"status,omitempty"`
@@ -149,20 +1065,135 @@ type ListDeliveriesParams struct {
 	Lng    *float64                    `form:"lng,omitempty" firestore:"lng,omitempty"`
 
 	// R Radius in kilometres from (lat,lng)
-	R         *float64   `form:"r,omitempty" firestore:"r,omitempty"`
-	PageSize  *PageSize  `form:"pageSize,omitempty" firestore:"pageSize,omitempty"`
-	PageToken *PageToken `form:"pageToken,omitempty" firestore:"pageToken,omitempty"`
+	R *float64 `form:"r,omitempty" firestore:"r,omitempty"`
+
+	// Sort createdAt (default) lists newest first; distance lists the pickups nearest to
+	// (lat,lng) by travel time first, within each page. distance needs lat and lng.
+	// urgency lists the earliest deliverBefore first, within each page; it is the
+	// default for couriers.
+	Sort      *ListDeliveriesParamsSort `form:"sort,omitempty" firestore:"sort,omitempty"`
+	PageSize  *PageSize                 `form:"pageSize,omitempty" firestore:"pageSize,omitempty"`
+	PageToken *PageToken                `form:"pageToken,omitempty" firestore:"pageToken,omitempty"`
 }
 
 // ListDeliveriesParamsStatus defines parameters for ListDeliveries.
 type ListDeliveriesParamsStatus string
 
+// ListDeliveriesParamsSort defines parameters for ListDeliveries.
+type ListDeliveriesParamsSort string
+
+// ListDeliveryBundlesParams defines parameters for ListDeliveryBundles.
+type ListDeliveryBundlesParams struct {
+	// Lat Courier position; bundles are planned from here when given with lng
+	Lat *float64 `form:"lat,omitempty" firestore:"lat,omitempty"`
+	Lng *float64 `form:"lng,omitempty" firestore:"lng,omitempty"`
+
+	// R Only pickups within this many kilometres of (lat,lng)
+	R *float64 `form:"r,omitempty" firestore:"r,omitempty"`
+}
+
+// StreamDeliveriesParams defines parameters for StreamDeliveries.
+type StreamDeliveriesParams struct {
+	Lat *float64 `form:"lat,omitempty" firestore:"lat,omitempty"`
+	Lng *float64 `form:"lng,omitempty" firestore:"lng,omitempty"`
+
+	// R Radius in km around lat/lng (couriers)
+	R *float64 `form:"r,omitempty" firestore:"r,omitempty"`
+
+	// LastEventID Id of the last event received; events after it are replayed first, going back
+	// an hour at most
+	LastEventID *string `firestore:"Last-Event-ID,omitempty"`
+}
+
+// ListDeliveryTemplateOccurrencesParams defines parameters for ListDeliveryTemplateOccurrences.
+type ListDeliveryTemplateOccurrencesParams struct {
+	// Count How many occurrences to list (default 10, at most 100)
+	Count *int `form:"count,omitempty" firestore:"count,omitempty"`
+}
+
+// GenerateInvoicesParams defines parameters for GenerateInvoices.
+type GenerateInvoicesParams struct {
+	// Month Invoiced month as YYYY-MM; defaults to the previous month
+	Month *string `form:"month,omitempty" firestore:"month,omitempty"`
+}
+
+// ListPayoutsParams defines parameters for ListPayouts.
+type ListPayoutsParams struct {
+	Status *ListPayoutsParamsStatus `form:"status,omitempty" firestore:"status,omitempty"`
+}
+
+// ListPayoutsParamsStatus defines parameters for ListPayouts.
+type ListPayoutsParamsStatus string
+
+// ExportPayoutBatchParams defines parameters for ExportPayoutBatch.
+type ExportPayoutBatchParams struct {
+	// Format csv (default) or sepa (pain.001 credit transfer XML)
+	Format *ExportPayoutBatchParamsFormat `form:"format,omitempty" firestore:"format,omitempty"`
+}
+
+// ExportPayoutBatchParamsFormat defines parameters for ExportPayoutBatch.
+type ExportPayoutBatchParamsFormat string
+
+// UpdateMyRepricingJSONRequestBody defines body for UpdateMyRepricing for application/json ContentType.
+type UpdateMyRepricingJSONRequestBody = BusinessRepricing
+
+// RequestPayoutJSONRequestBody defines body for RequestPayout for application/json ContentType.
+type RequestPayoutJSONRequestBody = PayoutCreate
+
+// CreatePushSubscriptionJSONRequestBody defines body for CreatePushSubscription for application/json ContentType.
+type CreatePushSubscriptionJSONRequestBody = PushSubscriptionCreate
+
+// UpdateMyVehicleJSONRequestBody defines body for UpdateMyVehicle for application/json ContentType.
+type UpdateMyVehicleJSONRequestBody = CourierVehicle
+
+// SetCourierActiveLimitJSONRequestBody defines body for SetCourierActiveLimit for application/json ContentType.
+type SetCourierActiveLimitJSONRequestBody = CourierActiveLimit
+
+// SetCourierZonesJSONRequestBody defines body for SetCourierZones for application/json ContentType.
+type SetCourierZonesJSONRequestBody = CourierZones
+
 // CreateDeliveryJSONRequestBody defines body for CreateDelivery for application/json ContentType.
 type CreateDeliveryJSONRequestBody = DeliveryCreate
 
+// AcceptDeliveryBundleJSONRequestBody defines body for AcceptDeliveryBundle for application/json ContentType.
+type AcceptDeliveryBundleJSONRequestBody = BundleAccept
+
+// QuoteDeliveryJSONRequestBody defines body for QuoteDelivery for application/json ContentType.
+type QuoteDeliveryJSONRequestBody = DeliveryQuoteRequest
+
 // UpdateDeliveryJSONRequestBody defines body for UpdateDelivery for application/json ContentType.
 type UpdateDeliveryJSONRequestBody = DeliveryPatch
 
+// ScanParcelJSONRequestBody defines body for ScanParcel for application/json ContentType.
+type ScanParcelJSONRequestBody = ParcelScanCreate
+
+// RateDeliveryJSONRequestBody defines body for RateDelivery for application/json ContentType.
+type RateDeliveryJSONRequestBody = RatingCreate
+
+// TipDeliveryJSONRequestBody defines body for TipDelivery for application/json ContentType.
+type TipDeliveryJSONRequestBody = TipCreate
+
+// CreateDeliveryTemplateJSONRequestBody defines body for CreateDeliveryTemplate for application/json ContentType.
+type CreateDeliveryTemplateJSONRequestBody = DeliveryTemplateCreate
+
+// UpdateDeliveryTemplateJSONRequestBody defines body for UpdateDeliveryTemplate for application/json ContentType.
+type UpdateDeliveryTemplateJSONRequestBody = DeliveryTemplateCreate
+
+// UpdateMyNotificationPreferencesJSONRequestBody defines body for UpdateMyNotificationPreferences for application/json ContentType.
+type UpdateMyNotificationPreferencesJSONRequestBody = NotificationPreferences
+
+// RejectPayoutJSONRequestBody defines body for RejectPayout for application/json ContentType.
+type RejectPayoutJSONRequestBody = PayoutReject
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = WebhookCreate
+
+// CreateZoneJSONRequestBody defines body for CreateZone for application/json ContentType.
+type CreateZoneJSONRequestBody = ZoneCreate
+
+// UpdateZoneJSONRequestBody defines body for UpdateZone for application/json ContentType.
+type UpdateZoneJSONRequestBody = ZoneCreate
+
 // AsBusinessUser returns the union data inside the OneOfUser as a BusinessUser
 func (t OneOfUser) AsBusinessUser() (BusinessUser, error) {
 	var body BusinessUser
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"os"
+	"time"
     "github.com/gin-contrib/cors"
 
 	firebase "firebase.google.com/go/v4"
@@ -11,6 +12,7 @@ import (
 	"google.golang.org/api/option"
     "github.com/joho/godotenv"
 	"github.com/Evap1/courier-system/backend/internal/auth"          // the new package
+	"github.com/Evap1/courier-system/backend/internal/config"
 	"github.com/Evap1/courier-system/backend/internal/db"
 	"github.com/Evap1/courier-system/backend/internal/service"
 	httptransport "github.com/Evap1/courier-system/backend/internal/transport/http"
@@ -50,8 +52,79 @@ func main() {
 
 	//  domain + handler 
 	userSvc := service.NewUserService(fs)
-	deliverySvc := service.NewDeliveryService(fs)
-	handler := httptransport.NewHandler(deliverySvc, userSvc) // implements ServerInterface
+	routing, err := service.NewRouter()
+	if err != nil {
+		log.Fatalf("router: %v", err)
+	}
+	zoneSvc := service.NewZoneService(fs)
+	deliverySvc := service.NewDeliveryService(fs, routing, zoneSvc)
+	payoutSvc := service.NewPayoutService(fs)
+	earningsSvc := service.NewEarningsService(fs)
+	invoiceSvc := service.NewInvoiceService(fs, deliverySvc)
+	templateSvc := service.NewTemplateService(fs, deliverySvc, userSvc)
+	expirySvc := service.NewExpiryService(fs)
+	releaseSvc := service.NewReleaseService(fs)
+	locationHub := service.NewLocationHub(fs)
+	webhookSvc := service.NewWebhookService(fs)
+	emailNotifier, smsNotifier, err := service.NewNotifiers()
+	if err != nil {
+		log.Fatalf("notifiers: %v", err)
+	}
+	notificationSvc, err := service.NewNotificationService(fs, emailNotifier, smsNotifier)
+	if err != nil {
+		log.Fatalf("notification templates: %v", err)
+	}
+	pushSvc, err := service.NewPushService(fs)
+	if err != nil {
+		log.Fatalf("web push: %v", err)
+	}
+	etaSvc, err := service.NewEtaService(fs, locationHub, routing)
+	if err != nil {
+		log.Fatalf("eta: %v", err)
+	}
+	outboxRelay := service.NewOutboxRelay(fs)
+	outboxRelay.Register(webhookSvc)
+	outboxRelay.Register(notificationSvc)
+	outboxRelay.Register(etaSvc)
+	if pushSvc.Enabled() {
+		outboxRelay.Register(pushSvc)
+	}
+	eventPub, err := service.NewEventPublisher()
+	if err != nil {
+		log.Fatalf("event bus: %v", err)
+	}
+	if eventPub != nil {
+		defer eventPub.Close()
+		outboxRelay.Register(service.NewEventBusSink(eventPub))
+	}
+	handler := httptransport.NewHandler(deliverySvc, userSvc, payoutSvc, earningsSvc, invoiceSvc, locationHub, webhookSvc, notificationSvc, pushSvc, zoneSvc, templateSvc) // implements ServerInterface
+
+	// monthly business invoices, generated in the background
+	go invoiceSvc.RunInvoiceScheduler(ctx, config.Duration("INVOICE_CHECK_INTERVAL", 6*time.Hour))
+
+	// recurring delivery templates, posted as scheduled deliveries ahead of each occurrence
+	go templateSvc.RunTemplateScheduler(ctx, config.Duration("TEMPLATE_CHECK_INTERVAL", time.Minute))
+
+	// posted deliveries nobody accepts: repriced, businesses reminded, then expired
+	go expirySvc.Run(ctx, config.Duration("EXPIRY_CHECK_INTERVAL", time.Minute))
+
+	// scheduled deliveries announced to couriers once they may see them
+	go releaseSvc.Run(ctx, config.Duration("RELEASE_CHECK_INTERVAL", time.Minute))
+
+	// courier positions for the WebSocket maps
+	go locationHub.Run(ctx)
+
+	// pickup and drop-off ETAs, kept current from courier positions
+	go etaSvc.Run(ctx)
+
+	// business webhooks for delivery lifecycle events
+	go webhookSvc.Run(ctx)
+
+	// email and SMS notifications queued by the notification sink
+	go notificationSvc.Run(ctx)
+
+	// delivery events written by the state machine, relayed to the sinks registered above
+	go outboxRelay.Run(ctx)
 
 	//  HTTP router using gin
 	router := gin.Default()
@@ -59,13 +132,13 @@ func main() {
     // allow frontend requests (CORS)
     router.Use(cors.New(cors.Config{
         AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:3000"},
-        AllowMethods:     []string{"GET", "POST", "PATCH", "OPTIONS"},
-        AllowHeaders:     []string{"Authorization", "Content-Type"},
-        ExposeHeaders:    []string{"X-Next-Page-Token"},
+        AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+        AllowHeaders:     []string{"Authorization", "Content-Type", "Last-Event-ID"},
+        ExposeHeaders:    []string{"X-Next-Page-Token", "Content-Disposition"},
         AllowCredentials: true,
     }))
 
-	router.Use(auth.Middleware(authClient))          // protect everything
+	router.Use(auth.Middleware(authClient, "/track/:token")) // protect everything but public tracking
 	httptransport.RegisterHandlers(router, handler)  // all routes from openapi.gen.go 
 
 	// startup the server
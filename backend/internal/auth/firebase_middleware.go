@@ -15,11 +15,27 @@ import (
 
 // Middleware verifies the Bearer JWT coming from the frontend,
 // aborts with 401 on failure, and puts the Firebase UID in Gin context.
-func Middleware(ac *fbauth.Client) gin.HandlerFunc {
+// Browsers can't set headers on a WebSocket handshake, so upgrade requests
+// may offer the token as a subprotocol instead (see WebSocketProtocol); unlike a
+// query parameter it doesn't end up in access logs.
+// publicRoutes are gin route patterns (e.g. "/track/:token") served without a token.
+func Middleware(ac *fbauth.Client, publicRoutes ...string) gin.HandlerFunc {
+	public := make(map[string]bool, len(publicRoutes))
+	for _, r := range publicRoutes {
+		public[r] = true
+	}
 	return func(c *gin.Context) {
 		const prefix = "Bearer "
 
+		if public[c.FullPath()] {
+			c.Next()
+			return
+		}
+
 		h := c.GetHeader("Authorization")
+		if !strings.HasPrefix(h, prefix) && isWebSocketUpgrade(c.Request) {
+			if tok := protocolToken(c.Request); tok != "" { h = prefix + tok }
+		}
 		if !strings.HasPrefix(h, prefix) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized,
 				gin.H{"msg": "missing bearer token"})
@@ -39,3 +55,24 @@ func Middleware(ac *fbauth.Client) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// WebSocketProtocol is the subprotocol a browser offers, followed by its ID token, to
+// authenticate a WebSocket handshake: new WebSocket(url, ["access_token", idToken]).
+// The server must select it in its reply for the browser to accept the connection.
+const WebSocketProtocol = "access_token"
+
+// protocolToken returns the ID token offered after WebSocketProtocol in
+// Sec-WebSocket-Protocol; "" if there is none.
+func protocolToken(r *http.Request) string {
+	offered := strings.Split(r.Header.Get("Sec-WebSocket-Protocol"), ",")
+	for i := 0; i+1 < len(offered); i++ {
+		if strings.TrimSpace(offered[i]) == WebSocketProtocol { return strings.TrimSpace(offered[i+1]) }
+	}
+	return ""
+}
+
+// isWebSocketUpgrade reports whether r is a WebSocket opening handshake.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
@@ -1,5 +1,44 @@
 package config                             
 
-import "os"                                   
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
-var saPath = os.Getenv("FIREBASE_SA")
\ No newline at end of file
+var saPath = os.Getenv("FIREBASE_SA")
+
+// Float reads a numeric env var, falling back to def when unset or malformed.
+func Float(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Int reads an integer env var, falling back to def when unset or malformed.
+func Int(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Duration reads a Go duration env var (e.g. "72h", "90s"), falling back to def.
+func Duration(name string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// String reads an env var, falling back to def when unset.
+func String(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
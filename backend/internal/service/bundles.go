@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"google.golang.org/api/iterator"
+)
+
+// bundleRules decide which posted deliveries are suggested together: pickups close to
+// each other, and destinations either close too or in the same direction.
+type bundleRules struct {
+	pickupRadiusKm  float64 // env BUNDLE_PICKUP_RADIUS_KM, pickups at most this far apart
+	dropoffRadiusKm float64 // env BUNDLE_DROPOFF_RADIUS_KM, destinations this close always match
+	maxBearingDeg   float64 // env BUNDLE_MAX_BEARING_DEG, otherwise headings may differ this much
+	maxSize         int     // env BUNDLE_MAX_SIZE, deliveries per bundle
+	scanLimit       int     // env BUNDLE_SCAN_LIMIT, newest posted deliveries considered
+}
+
+func loadBundleRules() bundleRules {
+	return bundleRules{
+		pickupRadiusKm:  config.Float("BUNDLE_PICKUP_RADIUS_KM", 0.5),
+		dropoffRadiusKm: config.Float("BUNDLE_DROPOFF_RADIUS_KM", 2),
+		maxBearingDeg:   config.Float("BUNDLE_MAX_BEARING_DEG", 45),
+		maxSize:         config.Int("BUNDLE_MAX_SIZE", 3),
+		scanLimit:       config.Int("BUNDLE_SCAN_LIMIT", 200),
+	}
+}
+
+// maxBundleSuggestions bounds how many bundles get routed per request.
+const maxBundleSuggestions = 20
+
+var ErrInvalidBundle = errors.New("a bundle needs between 2 and BUNDLE_MAX_SIZE distinct deliveries")
+
+var ErrBundleUnavailable = errors.New("a delivery of the bundle is no longer available")
+
+// GET /deliveries/bundles
+// SuggestBundles groups the posted deliveries visible under filter into bundles worth
+// riding together. A bundle starts from the oldest ungrouped delivery and takes in the
+// next ones compatible with all its members; its stops are planned like a courier route,
+// from the filter's centre when given, and it is kept only when it beats riding the same
+// deliveries one after the other.
+func (s *DeliveryService) SuggestBundles(ctx context.Context, filter ListFilter) ([]*api.DeliveryBundle, error) {
+	iter := s.firestore.Collection("deliveries").
+		Where("status", "==", StatusPosted).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(s.bundling.scanLimit).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var posted []api.Delivery
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var d api.Delivery
+		if err := doc.DataTo(&d); err != nil { continue }
+		id := doc.Ref.ID
+		d.Id = &id
+		if d.AssignedTo != nil || !filter.Allows(&d) { continue }
+		posted = append(posted, d)
+	}
+	// oldest first: they have waited longest for a courier
+	for i, j := 0, len(posted)-1; i < j; i, j = i+1, j-1 {
+		posted[i], posted[j] = posted[j], posted[i]
+	}
+
+	var start *api.GeoPoint
+	if filter.CenterLat != nil && filter.CenterLng != nil {
+		start = &api.GeoPoint{Lat: *filter.CenterLat, Lng: *filter.CenterLng}
+	}
+	bundles := []*api.DeliveryBundle{}
+	for _, group := range s.groupBundles(posted, filter.Room) {
+		b, err := s.planBundle(ctx, group, start)
+		if err != nil { return nil, err }
+		if b != nil { bundles = append(bundles, b) }
+	}
+	sort.SliceStable(bundles, func(i, j int) bool { return bundles[i].SavedMinutes > bundles[j].SavedMinutes })
+	return bundles, nil
+}
+
+// groupBundles clusters deliveries greedily, in order, into groups of at least two;
+// with room given, a group never holds more than the courier can still carry.
+func (s *DeliveryService) groupBundles(list []api.Delivery, room *Capacity) [][]api.Delivery {
+	used := make([]bool, len(list))
+	var groups [][]api.Delivery
+	for i := range list {
+		if used[i] { continue }
+		group := []api.Delivery{list[i]}
+		members := []int{i}
+		load := deliveryLoad(&list[i])
+		for j := i + 1; j < len(list) && len(group) < s.bundling.maxSize; j++ {
+			if used[j] { continue }
+			fits := room == nil || room.Fits(load.Add(deliveryLoad(&list[j])))
+			for _, d := range group {
+				if !s.bundling.compatible(&d, &list[j]) { fits = false; break }
+			}
+			if fits {
+				group = append(group, list[j])
+				members = append(members, j)
+				load = load.Add(deliveryLoad(&list[j]))
+			}
+		}
+		if len(group) < 2 { continue }
+		for _, m := range members {
+			used[m] = true
+		}
+		groups = append(groups, group)
+		if len(groups) == maxBundleSuggestions { break }
+	}
+	return groups
+}
+
+// compatible reports whether two deliveries ride well together.
+func (r bundleRules) compatible(a, b *api.Delivery) bool {
+	ap, bp := a.BusinessLocation, b.BusinessLocation
+	if geoDistanceKm(ap.Lat, ap.Lng, bp.Lat, bp.Lng) > r.pickupRadiusKm { return false }
+	ad, bd := a.DestinationLocation, b.DestinationLocation
+	if geoDistanceKm(ad.Lat, ad.Lng, bd.Lat, bd.Lng) <= r.dropoffRadiusKm { return true }
+	diff := math.Abs(bearingDeg(ap, ad) - bearingDeg(bp, bd))
+	return math.Min(diff, 360-diff) <= r.maxBearingDeg
+}
+
+// bearingDeg is the initial compass heading from a to b, 0-360°.
+func bearingDeg(a, b api.GeoPoint) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// planBundle orders the stops of a group and compares the ride with doing its deliveries
+// one after the other (in their best order); nil when bundling saves nothing.
+func (s *DeliveryService) planBundle(ctx context.Context, group []api.Delivery, start *api.GeoPoint) (*api.DeliveryBundle, error) {
+	stops := deliveryStops(group)
+	var points []api.GeoPoint
+	if start != nil { points = append(points, *start) }
+	for _, st := range stops {
+		points = append(points, st.stop.Location)
+	}
+	table, err := s.router.Table(ctx, points, points)
+	if err != nil { return nil, err }
+
+	order := planOrder(stops, table, start != nil)
+	planned, km, dur, ok := tableStops(stops, order, table, start != nil)
+	if !ok { return nil, nil } // no road between some of the stops
+
+	// deliveryStops puts each delivery's pickup right before its drop-off, so riding them
+	// one by one is any order of those pairs
+	single := unreachableLeg.Duration
+	permute(len(group), func(perm []int) {
+		seq := make([]int, 0, len(stops))
+		for _, d := range perm {
+			seq = append(seq, 2*d, 2*d+1)
+		}
+		_, _, t, ok := tableStops(stops, seq, table, start != nil)
+		if ok && t < single { single = t }
+	})
+	if single == unreachableLeg.Duration || dur >= single { return nil, nil }
+
+	b := &api.DeliveryBundle{
+		Deliveries:      group,
+		Stops:           planned,
+		DistanceKm:      math.Round(km*100) / 100,
+		DurationMinutes: math.Round(dur.Minutes()*10) / 10,
+		SavedMinutes:    math.Round((single - dur).Minutes()*10) / 10,
+	}
+	for _, d := range group {
+		b.Payment += d.Payment
+	}
+	b.Payment = roundCents(b.Payment)
+	return b, nil
+}
+
+// permute calls fn with every ordering of 0..n-1 (fn must not keep the slice).
+func permute(n int, fn func([]int)) {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	var rec func(k int)
+	rec = func(k int) {
+		if k == n { fn(perm); return }
+		for i := k; i < n; i++ {
+			perm[k], perm[i] = perm[i], perm[k]
+			rec(k + 1)
+			perm[k], perm[i] = perm[i], perm[k]
+		}
+	}
+	rec(0)
+}
+
+// POST /deliveries/bundles/accept
+// AcceptBundle assigns all the given posted deliveries to the courier in one transaction:
+// if any of them was taken meanwhile, or they don't fit together, none is accepted.
+func (s *DeliveryService) AcceptBundle(ctx context.Context, deliveryIDs []string, courierUID string) ([]*api.Delivery, error) {
+	if len(deliveryIDs) < 2 || len(deliveryIDs) > s.bundling.maxSize { return nil, ErrInvalidBundle }
+	seen := map[string]bool{}
+	refs := make([]*firestore.DocumentRef, 0, len(deliveryIDs))
+	for _, id := range deliveryIDs {
+		if id == "" || seen[id] { return nil, ErrInvalidBundle }
+		seen[id] = true
+		refs = append(refs, s.firestore.Collection("deliveries").Doc(id))
+	}
+	courierRef := s.firestore.Collection("users").Doc(courierUID)
+
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snaps, err := tx.GetAll(append(refs, courierRef))
+		if err != nil { return err }
+		courierSnap := snaps[len(refs)]
+		snaps = snaps[:len(refs)]
+		for _, snap := range snaps {
+			if !snap.Exists() { return fmt.Errorf("%w: %s", ErrDeliveryNotFound, snap.Ref.ID) }
+			var d api.Delivery
+			if err := snap.DataTo(&d); err != nil { return err }
+			if isValidTransition(string(d.Status), StatusAccepted) != nil {
+				return fmt.Errorf("%w: %s", ErrBundleUnavailable, snap.Ref.ID)
+			}
+		}
+		if !courierSnap.Exists() { return fmt.Errorf("courier %s not found", courierUID) }
+		return s.acceptInTx(tx, snaps, courierSnap, time.Now().UTC())
+	})
+	if err != nil { return nil, err }
+
+	// re-read to return fresh docs
+	snaps, err := s.firestore.GetAll(ctx, refs)
+	if err != nil { return nil, err }
+	accepted := make([]*api.Delivery, 0, len(snaps))
+	for _, snap := range snaps {
+		var d api.Delivery
+		if err := snap.DataTo(&d); err != nil { return nil, err }
+		accepted = append(accepted, &d)
+	}
+	return accepted, nil
+}
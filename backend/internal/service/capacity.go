@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Capacity is an amount of carrying: what a vehicle holds, what a courier has left, or
+// what deliveries take up. Each delivery counts as one job.
+type Capacity struct {
+	Jobs     int
+	WeightKg float64
+	VolumeL  float64
+}
+
+// Fits reports whether load can be added without going over c.
+func (c Capacity) Fits(load Capacity) bool {
+	return load.Jobs <= c.Jobs && load.WeightKg <= c.WeightKg+1e-9 && load.VolumeL <= c.VolumeL+1e-9
+}
+
+// Add sums two loads.
+func (c Capacity) Add(load Capacity) Capacity {
+	return Capacity{Jobs: c.Jobs + load.Jobs, WeightKg: c.WeightKg + load.WeightKg, VolumeL: c.VolumeL + load.VolumeL}
+}
+
+// sizeVolumeL is the volume a parcel of each size takes up.
+var sizeVolumeL = map[api.DeliverySize]float64{
+	api.Small:  5,
+	api.Medium: 20,
+	api.Large:  60,
+	api.Xlarge: 200,
+}
+
+// vehicleCapacity is what each vehicle type carries at once, unless the courier set less.
+var vehicleCapacity = map[api.VehicleType]Capacity{
+	api.Bicycle:   {Jobs: 2, WeightKg: 10, VolumeL: 30},
+	api.Scooter:   {Jobs: 3, WeightKg: 20, VolumeL: 60},
+	api.CargoBike: {Jobs: 4, WeightKg: 80, VolumeL: 250},
+	api.Car:       {Jobs: 6, WeightKg: 200, VolumeL: 500},
+	api.Van:       {Jobs: 10, WeightKg: 800, VolumeL: 3000},
+}
+
+// largestVehicle bounds parcels: nothing heavier or bulkier can be delivered at all.
+var largestVehicle = vehicleCapacity[api.Van]
+
+var ErrInvalidParcel = errors.New("size must be small, medium, large or xlarge and weightKg between 0 and what a van carries")
+
+var ErrInvalidVehicle = errors.New("unknown vehicle type, or a capacity that isn't between 1 and the type's default")
+
+var ErrOverCapacity = errors.New("delivery doesn't fit in what the courier can still carry")
+
+var ErrActiveLimitReached = errors.New("courier has reached their limit of active deliveries")
+
+var ErrInvalidActiveLimit = errors.New("maxActiveDeliveries can't be negative")
+
+var ErrCourierNotFound = errors.New("courier not found")
+
+var ErrNoVehicle = errors.New("no vehicle set; only the active delivery limit applies")
+
+// validateParcel checks the optional size and weight of a new delivery.
+func validateParcel(req *api.DeliveryCreate) error {
+	if req.Size != nil {
+		if _, ok := sizeVolumeL[*req.Size]; !ok { return ErrInvalidParcel }
+	}
+	if req.WeightKg != nil && (*req.WeightKg < 0 || *req.WeightKg > largestVehicle.WeightKg) { return ErrInvalidParcel }
+	return nil
+}
+
+// deliveryLoad is what carrying d takes up; deliveries without a size count as small,
+// without a weight as weightless.
+func deliveryLoad(d *api.Delivery) Capacity {
+	load := Capacity{Jobs: 1, VolumeL: sizeVolumeL[api.Small]}
+	if d.Size != nil { load.VolumeL = sizeVolumeL[*d.Size] }
+	if d.WeightKg != nil { load.WeightKg = *d.WeightKg }
+	return load
+}
+
+// vehicleOrDefault fills the capacities left out of v with its type's defaults.
+func vehicleOrDefault(v *api.CourierVehicle) api.CourierVehicle {
+	out := *v
+	def := vehicleCapacity[out.Type]
+	if out.MaxJobs == nil { out.MaxJobs = &def.Jobs }
+	if out.MaxWeightKg == nil { out.MaxWeightKg = &def.WeightKg }
+	if out.MaxVolumeL == nil { out.MaxVolumeL = &def.VolumeL }
+	return out
+}
+
+// activeLimit is how many accepted and picked-up deliveries the courier may have at once:
+// their admin-set override, or MAX_ACTIVE_DELIVERIES.
+func (s *DeliveryService) activeLimit(c *api.CourierUser) int {
+	if c.MaxActiveDeliveries != nil { return *c.MaxActiveDeliveries }
+	return s.maxActive
+}
+
+// RemainingCapacity is what the courier can still take on next to their active deliveries;
+// the number of jobs is bounded by both the vehicle and the active delivery limit.
+// Couriers who never set a vehicle are only bounded by the limit: they took any parcel
+// before vehicles existed, and guessing a vehicle for them would suddenly cap them.
+func (s *DeliveryService) RemainingCapacity(c *api.CourierUser) Capacity {
+	room := Capacity{Jobs: s.activeLimit(c), WeightKg: math.Inf(1), VolumeL: math.Inf(1)}
+	if c.Vehicle != nil {
+		v := vehicleOrDefault(c.Vehicle)
+		room = Capacity{Jobs: min(*v.MaxJobs, s.activeLimit(c)), WeightKg: *v.MaxWeightKg, VolumeL: *v.MaxVolumeL}
+	}
+	if c.ActiveLoad != nil {
+		room.Jobs -= c.ActiveLoad.Jobs
+		room.WeightKg -= c.ActiveLoad.WeightKg
+		room.VolumeL -= c.ActiveLoad.VolumeL
+	}
+	return room
+}
+
+// activeLoadInTx is the courier's active load, read as part of tx. Couriers who accepted
+// deliveries before loads were tracked have none stored: theirs is added up from their
+// accepted and picked-up deliveries, and the caller's write stores it from then on.
+func (s *DeliveryService) activeLoadInTx(tx *firestore.Transaction, courierUID string, courier *api.CourierUser) (api.CourierLoad, error) {
+	if courier.ActiveLoad != nil { return *courier.ActiveLoad, nil }
+	snaps, err := tx.Documents(s.firestore.Collection("deliveries").
+		Where("assignedTo", "==", courierUID).
+		Where("status", "in", []string{StatusAccepted, StatusPickedUp})).GetAll()
+	if err != nil { return api.CourierLoad{}, err }
+
+	var load Capacity
+	for _, snap := range snaps {
+		var d api.Delivery
+		err := snap.DataTo(&d)
+		if err != nil { return api.CourierLoad{}, err }
+		load = load.Add(deliveryLoad(&d))
+	}
+	return api.CourierLoad{Jobs: load.Jobs, WeightKg: load.WeightKg, VolumeL: load.VolumeL}, nil
+}
+
+// releaseLoad takes a finished delivery off the courier's active load; never below zero,
+// so rounding or a load changed by an edit can't leave negative room.
+func releaseLoad(active api.CourierLoad, load Capacity) api.CourierLoad {
+	out := active
+	out.Jobs = max(0, out.Jobs-load.Jobs)
+	out.WeightKg = math.Max(0, out.WeightKg-load.WeightKg)
+	out.VolumeL = math.Max(0, out.VolumeL-load.VolumeL)
+	return out
+}
+
+// GET /couriers/me/vehicle
+func (u *UserService) GetVehicle(ctx context.Context, courierUID string) (*api.CourierVehicle, error) {
+	courier, err := u.GetCourierInfo(ctx, courierUID)
+	if err != nil { return nil, err }
+	if courier.Vehicle == nil { return nil, ErrNoVehicle }
+	v := vehicleOrDefault(courier.Vehicle)
+	return &v, nil
+}
+
+// PUT /couriers/me/vehicle
+// SetVehicle stores the courier's vehicle with its defaults filled in. Lowering a capacity
+// below the current load is allowed: it only stops new accepts until deliveries complete.
+func (u *UserService) SetVehicle(ctx context.Context, courierUID string, req *api.CourierVehicle) (*api.CourierVehicle, error) {
+	def, ok := vehicleCapacity[req.Type]
+	if !ok { return nil, ErrInvalidVehicle }
+	if req.MaxJobs != nil && (*req.MaxJobs < 1 || *req.MaxJobs > def.Jobs) { return nil, ErrInvalidVehicle }
+	if req.MaxWeightKg != nil && (*req.MaxWeightKg <= 0 || *req.MaxWeightKg > def.WeightKg) { return nil, ErrInvalidVehicle }
+	if req.MaxVolumeL != nil && (*req.MaxVolumeL <= 0 || *req.MaxVolumeL > def.VolumeL) { return nil, ErrInvalidVehicle }
+
+	// fails on a missing or non-courier user rather than creating one
+	if _, err := u.GetCourierInfo(ctx, courierUID); err != nil { return nil, err }
+	v := vehicleOrDefault(req)
+	_, err := u.firestore.Collection("users").Doc(courierUID).Update(ctx, []firestore.Update{{Path: "vehicle", Value: v}})
+	if err != nil { return nil, err }
+	return &v, nil
+}
+
+// PUT /couriers/{id}/active-limit
+// SetActiveLimit overrides MAX_ACTIVE_DELIVERIES for one courier; nil clears the override.
+// Deliveries above a lowered limit stay with the courier, they just can't accept more.
+func (u *UserService) SetActiveLimit(ctx context.Context, courierUID string, limit *int) (*api.CourierUser, error) {
+	if limit != nil && *limit < 0 { return nil, ErrInvalidActiveLimit }
+	role, err := u.GetUserRole(ctx, courierUID)
+	if status.Code(err) == codes.NotFound || (err == nil && role != "courier") { return nil, ErrCourierNotFound }
+	if err != nil { return nil, err }
+	courier, err := u.GetCourierInfo(ctx, courierUID)
+	if err != nil { return nil, err }
+
+	var value any = firestore.Delete
+	if limit != nil { value = *limit }
+	_, err = u.firestore.Collection("users").Doc(courierUID).Update(ctx, []firestore.Update{{Path: "maxActiveDeliveries", Value: value}})
+	if err != nil { return nil, err }
+	courier.MaxActiveDeliveries = limit
+	return courier, nil
+}
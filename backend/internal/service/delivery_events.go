@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/google/uuid"
+)
+
+// Delivery event types, one per transition a client has to react to.
+const (
+	EventDeliveryCreated  = "delivery.created"
+	// a scheduled delivery opened to couriers at its releaseAt
+	EventDeliveryReleased = "delivery.released"
+	EventDeliveryAccepted = "delivery.accepted"
+	EventDeliveryStatus   = "delivery.status"
+	EventDeliveryEdited   = "delivery.edited"
+	// steps taken on a posted delivery nobody accepts; expiring is a delivery.status
+	EventDeliveryRepriced   = "delivery.repriced"
+	EventDeliveryUnaccepted = "delivery.unaccepted"
+	// parcel scans; a parcel reported missing has its own type so the business is told
+	EventDeliveryParcelScanned = "delivery.parcel_scanned"
+	EventDeliveryParcelMissing = "delivery.parcel_missing"
+)
+
+// DeliveryEvent is one change of a delivery, stored in /deliveryEvents/{id}.
+// Ids start with the zero-padded UnixNano of CreatedAt, so they sort in time order
+// and double as the SSE event id clients send back in Last-Event-ID.
+// Before holds the delivery as it was (nil on create), so a viewer who could see
+// the old state (e.g. other couriers seeing a posted job get taken) is told too.
+type DeliveryEvent struct {
+	Id         string        `firestore:"id"`
+	Type       string        `firestore:"type"`
+	DeliveryId string        `firestore:"deliveryId"`
+	Delivery   api.Delivery  `firestore:"delivery"`
+	Before     *api.Delivery `firestore:"before"`
+	CreatedAt  time.Time     `firestore:"createdAt"`
+}
+
+func newDeliveryEventID(at time.Time) string {
+	return fmt.Sprintf("%020d-%s", at.UnixNano(), uuid.NewString()[:8])
+}
+
+// eventIDTime recovers the creation time from an event id; false if it isn't one of ours.
+func eventIDTime(id string) (time.Time, bool) {
+	nanos, _, ok := strings.Cut(id, "-")
+	if !ok { return time.Time{}, false }
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil { return time.Time{}, false }
+	return time.Unix(0, n).UTC(), true
+}
+
+// addDeliveryEvent records a delivery change inside the caller's transaction,
+// so an event exists exactly when the change it describes was committed.
+// The same event is queued in the outbox for the relay's sinks.
+func addDeliveryEvent(tx *firestore.Transaction, fs *firestore.Client, eventType, deliveryID string, before *api.Delivery, after api.Delivery) error {
+	now := time.Now().UTC()
+	after.Id = &deliveryID
+	if before != nil {
+		b := *before
+		b.Id = &deliveryID
+		before = &b
+	}
+	e := DeliveryEvent{
+		Id:         newDeliveryEventID(now),
+		Type:       eventType,
+		DeliveryId: deliveryID,
+		Delivery:   after,
+		Before:     before,
+		CreatedAt:  now,
+	}
+	err := tx.Create(fs.Collection("deliveryEvents").Doc(e.Id), e)
+	if err != nil { return err }
+	return addOutboxEntry(tx, fs, e)
+}
+
+// streamWindow is how long one Firestore listener serves a stream before it is replaced by
+// one starting at the last event seen, so the listened result set stays small.
+const streamWindow = 5 * time.Minute
+
+// streamMaxReplay bounds how far back a reconnecting client may resume. Last-Event-ID comes
+// from the client, and an old or forged one would otherwise replay the whole event history.
+const streamMaxReplay = time.Hour
+
+// GET /deliveries/stream
+// StreamDeliveryEvents calls send for every delivery event the filter may see, oldest first,
+// until ctx is cancelled or send fails. With lastEventID it first replays everything after
+// that event, so a reconnecting client resumes without gaps; otherwise it starts from now.
+// The replay goes back streamMaxReplay at most (see replayFrom).
+// The listener runs on Firestore, so events from every backend instance are delivered.
+func (s *DeliveryService) StreamDeliveryEvents(ctx context.Context, filter ListFilter, lastEventID string, send func(*DeliveryEvent) error) error {
+	since, lastEventID := replayFrom(lastEventID, time.Now().UTC())
+
+	for {
+		err := s.listenWindow(ctx, filter, &since, &lastEventID, send)
+		if ctx.Err() != nil { return nil } // client went away
+		if err != nil { return err }
+	}
+}
+
+// replayFrom is where a stream resuming after lastEventID starts at now, and the id of the
+// last event it already saw ("" when none is to be skipped). The replay reaches back at most
+// streamMaxReplay; an unknown id or one from the future starts from now.
+func replayFrom(lastEventID string, now time.Time) (time.Time, string) {
+	oldest := now.Add(-streamMaxReplay)
+	t, ok := eventIDTime(lastEventID)
+	switch {
+	case !ok || t.After(now):
+		return now, ""
+	case t.Before(oldest):
+		return oldest, ""
+	}
+	return t, lastEventID
+}
+
+// listenWindow listens for events from since on for at most streamWindow, moving since and
+// lastEventID past every event it sees (sent or filtered out); nil when the window ran out.
+func (s *DeliveryService) listenWindow(ctx context.Context, filter ListFilter, since *time.Time, lastEventID *string, send func(*DeliveryEvent) error) error {
+	wctx, cancel := context.WithTimeout(ctx, streamWindow)
+	defer cancel()
+
+	snaps := s.firestore.Collection("deliveryEvents").
+		Where("createdAt", ">=", *since).
+		OrderBy("createdAt", firestore.Asc).
+		Snapshots(wctx)
+	defer snaps.Stop()
+
+	for {
+		qs, err := snaps.Next()
+		if err != nil {
+			if wctx.Err() != nil { return nil } // window over, or client gone
+			return err
+		}
+
+		var batch []*DeliveryEvent
+		for _, ch := range qs.Changes {
+			if ch.Kind != firestore.DocumentAdded { continue }
+			var e DeliveryEvent
+			if err := ch.Doc.DataTo(&e); err != nil { continue }
+			if e.Id <= *lastEventID { continue } // already seen
+			batch = append(batch, &e)
+		}
+		sort.Slice(batch, func(i, j int) bool { return batch[i].Id < batch[j].Id })
+
+		for _, e := range batch {
+			*lastEventID, *since = e.Id, e.CreatedAt
+			if !filter.Allows(&e.Delivery) && (e.Before == nil || !filter.Allows(e.Before)) { continue }
+			if err := send(e); err != nil { return err }
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestReplayFrom(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	idAt := func(at time.Time) string { return fmt.Sprintf("%020d-abcdef12", at.UnixNano()) }
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantSince   time.Time
+		wantLast    string
+	}{
+		{"no id starts now", "", now, ""},
+		{"unknown id starts now", "abc", now, ""},
+		{"recent id resumes after it", idAt(now.Add(-10 * time.Minute)), now.Add(-10 * time.Minute), idAt(now.Add(-10 * time.Minute))},
+		{"id at the limit resumes after it", idAt(now.Add(-streamMaxReplay)), now.Add(-streamMaxReplay), idAt(now.Add(-streamMaxReplay))},
+		{"old id is clamped", idAt(now.Add(-48 * time.Hour)), now.Add(-streamMaxReplay), ""},
+		{"forged id from the epoch is clamped", "0-x", now.Add(-streamMaxReplay), ""},
+		{"id from the future starts now", idAt(now.Add(time.Hour)), now, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since, last := replayFrom(tt.lastEventID, now)
+			if !since.Equal(tt.wantSince) { t.Errorf("since = %v, want %v", since, tt.wantSince) }
+			if last != tt.wantLast { t.Errorf("lastEventID = %q, want %q", last, tt.wantLast) }
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+// DeliveryJSON is a delivery as it leaves the system in webhook bodies and bus envelopes.
+// The generated api types only carry firestore tags, so plain encoding/json would write
+// Go field names (Id, BusinessName); DeliveryJSON writes the spec's camelCase names instead.
+// Decoding needs no help: encoding/json matches keys to fields case-insensitively.
+type DeliveryJSON api.Delivery
+
+// MarshalJSON encodes the delivery under its firestore field names, which are the spec's.
+func (d DeliveryJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(byFirestoreName(reflect.ValueOf(api.Delivery(d))))
+}
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// byFirestoreName turns v into maps keyed by firestore tag names, walking nested structs,
+// pointers, slices and maps. Values that marshal themselves (time.Time) are kept as they are;
+// omitempty is honoured the way encoding/json does.
+func byFirestoreName(v reflect.Value) any {
+	if !v.IsValid() { return nil }
+	if v.Type().Implements(jsonMarshaler) { return v.Interface() }
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() { return nil }
+		return byFirestoreName(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() { return nil }
+		fallthrough
+	case reflect.Array:
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = byFirestoreName(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() { return nil }
+		out := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			out[iter.Key().String()] = byFirestoreName(iter.Value())
+		}
+		return out
+	case reflect.Struct:
+		out := map[string]any{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() { continue }
+			name, opts, _ := strings.Cut(f.Tag.Get("firestore"), ",")
+			if name == "-" { continue }
+			if name == "" { name = f.Name }
+			fv := v.Field(i)
+			if strings.Contains(opts, "omitempty") && emptyJSON(fv) { continue }
+			out[name] = byFirestoreName(fv)
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// emptyJSON reports whether encoding/json's omitempty would drop v.
+func emptyJSON(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"time"
 	"errors"
+	"sort"
+	"strings"
     "cloud.google.com/go/firestore"	 
 	"github.com/google/uuid"
+	"github.com/Evap1/courier-system/backend/internal/config"
 	"github.com/Evap1/courier-system/backend/internal/db"
 	"github.com/Evap1/courier-system/backend/api"
 	"google.golang.org/api/iterator"
-	// "fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"fmt"
 )
 
 // api.Delivery defined by the yaml in backend/internal/transport/http/openapi.gen.go
@@ -18,19 +23,57 @@ import (
 // a pointer holding one Firestore client. The pointer itself never changes; the client is thread-safe and reused for every request.
 type DeliveryService struct {
 	firestore *db.FirestoreClient
+	router    Router        // road distances for quotes and distance ranking
+	zones     *ZoneService  // coverage and per-zone rules
+	tipWindow time.Duration // how long after delivery a business may still tip (env TIP_WINDOW)
+	pricing   pricingRates  // suggested payments (env PRICING_*)
+	bundling  bundleRules   // which posted deliveries ride well together (env BUNDLE_*)
+	maxActive int           // accepted + picked-up deliveries per courier, unless overridden (env MAX_ACTIVE_DELIVERIES)
+	scheduleLead time.Duration // how long before pickupAfter couriers see a scheduled delivery (env SCHEDULE_LEAD_TIME)
 }
 
-// NewDeliveryService wires Firestore into the domain layer.
+// NewDeliveryService wires Firestore, the router and the service zones into the domain layer.
 // called once from main.go at statup
-func NewDeliveryService(fs *db.FirestoreClient) *DeliveryService {
-	return &DeliveryService{firestore: fs}
+func NewDeliveryService(fs *db.FirestoreClient, router Router, zones *ZoneService) *DeliveryService {
+	return &DeliveryService{
+		firestore: fs,
+		router:    router,
+		zones:     zones,
+		tipWindow: config.Duration("TIP_WINDOW", 72*time.Hour),
+		pricing:   loadPricingRates(),
+		bundling:  loadBundleRules(),
+		maxActive: config.Int("MAX_ACTIVE_DELIVERIES", 5),
+		scheduleLead: config.Duration("SCHEDULE_LEAD_TIME", 30*time.Minute),
+	}
 }
 
 // POST /DELIVERIES
 // CreateDelivery validates input, fills server-side fields, and persists it.
 func (s *DeliveryService) CreateDelivery(ctx context.Context, req *api.DeliveryCreate, creatorUID string) (*api.Delivery, error) {
-
+	if err := validateRecipient(req); err != nil { return nil, err }
+	if err := validateParcels(req); err != nil { return nil, err }
+	// a delivery listing its parcels weighs what they weigh together, unless stated
+	if req.WeightKg == nil { req.WeightKg = parcelsWeight(req.Parcels) }
+	if err := validateParcel(req); err != nil { return nil, err }
 	now := time.Now().UTC()
+	if err := validateWindow(req, now); err != nil { return nil, err }
+	token, err := newTrackingToken()
+	if err != nil { return nil, err }
+
+	// the zone around the destination decides whether and on what terms it can be posted
+	z, err := s.zones.locate(ctx, req.DestinationLocation)
+	if err != nil { return nil, err }
+	var zoneID *string
+	if z != nil {
+		// a scheduled delivery needs the zone open when it's picked up, not when it's posted
+		pickupAt := now
+		if req.PickupAfter != nil && req.PickupAfter.After(now) { pickupAt = *req.PickupAfter }
+		if !z.openAt(pickupAt) { return nil, fmt.Errorf("%w (%s)", ErrZoneClosed, z.Name) }
+		if z.MinPayment != nil && req.Payment < *z.MinPayment {
+			return nil, fmt.Errorf("%w (%.2f in %s)", ErrBelowZoneMinimum, *z.MinPayment, z.Name)
+		}
+		zoneID = &z.Id
+	}
     id  := uuid.NewString()
 
 	// due to import cycles, for us it's the same object although it's of a different type
@@ -47,9 +90,26 @@ func (s *DeliveryService) CreateDelivery(ctx context.Context, req *api.DeliveryC
 		Status:               api.DeliveryStatusPosted,
 		CreatedAt:            &now,
 		Payment:			  req.Payment,
+		RecipientName:        req.RecipientName,
+		RecipientPhone:       req.RecipientPhone,
+		RecipientEmail:       req.RecipientEmail,
+		TrackingToken:        &token,
+		Size:                 req.Size,
+		WeightKg:             req.WeightKg,
+		ZoneId:               zoneID,
+		PickupAfter:          req.PickupAfter,
+		DeliverBefore:        req.DeliverBefore,
+		ReleaseAt:            s.releaseTime(req.PickupAfter, now),
+		Parcels:              newParcels(req.Parcels),
 	}
 
-	_, err := s.firestore.Collection("deliveries").Doc(id).Set(ctx, delivery)
+	// the delivery and its created event are written together
+	docRef := s.firestore.Collection("deliveries").Doc(id)
+	err = s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		err := tx.Set(docRef, delivery)
+		if err != nil { return err }
+		return addDeliveryEvent(tx, s.firestore.Client, EventDeliveryCreated, id, nil, *delivery)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +127,12 @@ type ListFilter struct {
 	PageToken    string // firestore cursor; empty for first page
 	Role		 string
 	BusinessName string
+	BusinessID   string // deliveries of this business only; unlike the name, unique and fixed
 	CourierID	 string
+	SortByDistance bool // nearest pickup to (CenterLat, CenterLng) first, within the page
+	SortByUrgency  bool // earliest deliverBefore first, within the page
+	Room         *Capacity // courier: what they can still carry; posted deliveries beyond it are hidden
+	ZoneIds      []string  // courier: zones they work in; posted deliveries elsewhere are hidden
 }
 
 // ListDeliveries returns deliveries based on the given filter (role, status, geo, pagination).
@@ -86,6 +151,9 @@ func (s *DeliveryService) ListDeliveries(ctx context.Context, filter ListFilter)
 	if filter.BusinessName != "" {
 		q = q.Where("businessName", "==", filter.BusinessName)
 	}
+	if filter.BusinessID != "" {
+		q = q.Where("businessId", "==", filter.BusinessID)
+	}
 
 	if filter.Status != nil {
 		q = q.Where("status", "==", *filter.Status)
@@ -114,46 +182,7 @@ func (s *DeliveryService) ListDeliveries(ctx context.Context, filter ListFilter)
 		id := doc.Ref.ID
 		d.Id = &id
 		//fmt.Println("the id is: ", *d.Id)
-		// geo-filter on the app server (firestore can’t do distance natively)
-		if filter.CenterLat != nil && filter.RadiusKm != nil {
-			//fmt.Println("Entered condition for geo-filtering")
-			dist := geoDistanceKm(
-				*filter.CenterLat, *filter.CenterLng,
-				d.BusinessLocation.Lat, d.BusinessLocation.Lng)
-			//fmt.Println(dist)
-
-			// courier - see posted or its own active/picked_up/delivered deliveries
-			// by T/F table, choosing the rows with false assigment
-			if dist > *filter.RadiusKm{
-				//fmt.Println("Entered condition for dist")
-				if filter.Role == "courier"{
-					if ((d.AssignedTo != nil && *d.AssignedTo != filter.CourierID) || d.AssignedTo == nil){
-						//fmt.Println("emtered if condition in courier role");
-						continue
-					}
-				} else { continue }
-			}
-		}
-		// if courier but dont apply filtering, make sure only assigned to
-		if filter.Role == "courier"{
-			// courier without status - show only posted and assigned to. if i'm here dist is ok or not filtered.
-			if filter.Status == nil{
-				if d.Status == "posted" && d.AssignedTo == nil {
-					// ok
-				} else if d.Status != "posted" && d.AssignedTo != nil && *d.AssignedTo == filter.CourierID{
-					// ok
-				} else{
-					continue
-				}
-			// filter status != nil -> its posted or not posted.
-			// posted? show only in the limits. by here the limits are correct or unfiltered.
-			} else if *filter.Status == "posted"{
-				// ok
-			// not posted? show only deliveries assigned to me.
-			} else if d.AssignedTo != nil && *d.AssignedTo == filter.CourierID{
-				// ok
-			} else {continue}
-		}
+		if !filter.Allows(&d) { continue }
 
 		result = append(result, &d)
 	}
@@ -166,11 +195,102 @@ func (s *DeliveryService) ListDeliveries(ctx context.Context, filter ListFilter)
 			nextPageToken = *last.Id
 		}
 	}
+	// ranked after the cursor is taken, so paging still follows createdAt
+	if filter.SortByDistance && filter.CenterLat != nil && filter.CenterLng != nil {
+		origin := api.GeoPoint{Lat: *filter.CenterLat, Lng: *filter.CenterLng}
+		if err := s.rankByTravelTime(ctx, origin, result); err != nil { return nil, "", err }
+	} else if filter.SortByUrgency {
+		sortByUrgency(result)
+	}
 	return result, nextPageToken, nil
 }
 
+// rankByTravelTime orders deliveries by how long it takes to reach their pickup from origin.
+func (s *DeliveryService) rankByTravelTime(ctx context.Context, origin api.GeoPoint, list []*api.Delivery) error {
+	if len(list) < 2 { return nil }
+	pickups := make([]api.GeoPoint, len(list))
+	for i, d := range list {
+		pickups[i] = d.BusinessLocation
+	}
+	table, err := s.router.Table(ctx, []api.GeoPoint{origin}, pickups)
+	if err != nil { return err }
+
+	legs := make(map[*api.Delivery]RouteLeg, len(list))
+	for i, d := range list {
+		legs[d] = table[0][i]
+	}
+	sort.SliceStable(list, func(i, j int) bool { return legs[list[i]].Duration < legs[list[j]].Duration })
+	return nil
+}
+
+// Allows reports whether one delivery is visible under the filter, applying the same
+// business/status/geo/courier rules as ListDeliveries. Used for single deliveries
+// that don't come from the list query, e.g. streamed delivery events.
+func (filter ListFilter) Allows(d *api.Delivery) bool {
+	// the constraints ListDeliveries pushes down to the Firestore query
+	if filter.BusinessName != "" && d.BusinessName != filter.BusinessName { return false }
+	if filter.BusinessID != "" && (d.BusinessId == nil || *d.BusinessId != filter.BusinessID) { return false }
+	if filter.Status != nil && string(d.Status) != *filter.Status { return false }
+
+	// geo-filter on the app server (firestore can’t do distance natively)
+	if filter.CenterLat != nil && filter.RadiusKm != nil {
+		//fmt.Println("Entered condition for geo-filtering")
+		dist := geoDistanceKm(
+			*filter.CenterLat, *filter.CenterLng,
+			d.BusinessLocation.Lat, d.BusinessLocation.Lng)
+		//fmt.Println(dist)
+
+		// courier - see posted or its own active/picked_up/delivered deliveries
+		// by T/F table, choosing the rows with false assigment
+		if dist > *filter.RadiusKm{
+			//fmt.Println("Entered condition for dist")
+			if filter.Role == "courier"{
+				if ((d.AssignedTo != nil && *d.AssignedTo != filter.CourierID) || d.AssignedTo == nil){
+					//fmt.Println("emtered if condition in courier role");
+					return false
+				}
+			} else { return false }
+		}
+	}
+	// if courier but dont apply filtering, make sure only assigned to
+	if filter.Role == "courier"{
+		// courier without status - show only posted and assigned to. if i'm here dist is ok or not filtered.
+		if filter.Status == nil{
+			if d.Status == "posted" && d.AssignedTo == nil {
+				// ok
+			} else if d.Status != "posted" && d.AssignedTo != nil && *d.AssignedTo == filter.CourierID{
+				// ok
+			} else{
+				return false
+			}
+		// filter status != nil -> its posted or not posted.
+		// posted? show only in the limits. by here the limits are correct or unfiltered.
+		} else if *filter.Status == "posted"{
+			// ok
+		// not posted? show only deliveries assigned to me.
+		} else if d.AssignedTo != nil && *d.AssignedTo == filter.CourierID{
+			// ok
+		} else {return false}
+	}
+	// scheduled deliveries stay hidden from couriers until their lead time
+	if filter.Role == "courier" && d.Status == StatusPosted && d.AssignedTo == nil && !released(d, time.Now()) {
+		return false
+	}
+	if len(filter.ZoneIds) > 0 && d.Status == StatusPosted && d.AssignedTo == nil && !inZones(d.ZoneId, filter.ZoneIds) {
+		return false
+	}
+	// a bicycle courier isn't offered a fridge
+	if filter.Room != nil && d.Status == StatusPosted && d.AssignedTo == nil && !filter.Room.Fits(deliveryLoad(d)) {
+		return false
+	}
+
+	return true
+}
+
 var ErrAlreadyAssigned = errors.New("delivery already assigned")
 
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
 var ErrInvalidUpdate = errors.New("this delivery assigned to different courier")
 
 // POST / deliveries/id/accept
@@ -182,6 +302,7 @@ var ErrInvalidUpdate = errors.New("this delivery assigned to different courier")
 func (s *DeliveryService) AcceptDelivery(ctx context.Context, deliveryID, courierUID string,) (*api.Delivery, error) {
 
     docRef := s.firestore.Collection("deliveries").Doc(deliveryID) //creates a reference to /deliveries/{id} once
+	courierRef := s.firestore.Collection("users").Doc(courierUID)
     //var accepted *api.Delivery // 	will hold updated doc after commit.
 	var snap *firestore.DocumentSnapshot
 
@@ -190,14 +311,7 @@ func (s *DeliveryService) AcceptDelivery(ctx context.Context, deliveryID, courie
     err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
         innerSnap, err := tx.Get(docRef)
         if err != nil { return err }
-
-        var d api.Delivery
-
-		err = innerSnap.DataTo(&d) // fill delivery srtuct fields
-		if err != nil { return err }
-
-		// state machine status 
-		err = isValidTransition(string(d.Status), StatusAccepted)
+		courierSnap, err := tx.Get(courierRef)
 		if err != nil { return err }
 
 		// helper to avoid race condition, should be ok without it thanks to firebase transaction parallellism
@@ -205,11 +319,8 @@ func (s *DeliveryService) AcceptDelivery(ctx context.Context, deliveryID, courie
 		// 	return ErrAlreadyAssigned
 		// }
 
-        d.AssignedTo = &courierUID
-        d.Status     = api.DeliveryStatusAccepted
 		snap = innerSnap
-		// commit changes to DB
-		return tx.Set(docRef, d)
+		return s.acceptInTx(tx, []*firestore.DocumentSnapshot{innerSnap}, courierSnap, time.Now().UTC())
 		// if err != nil { return err }
 
 		// if reached here, the commit is successfull, the delivery is accepted
@@ -231,6 +342,57 @@ func (s *DeliveryService) AcceptDelivery(ctx context.Context, deliveryID, courie
 	return &d, nil
 }
 
+// acceptInTx assigns the deliveries read in snaps to the courier read in courierSnap,
+// adds them to the courier's active load and records the accepted events, as part of tx.
+// Every other read of tx must be done before: only the active load backfill reads here,
+// ahead of the writes. Reading the courier doc in
+// tx makes two accepts racing for the courier's last free slot or capacity conflict.
+// Shared by single and bundle accepts so both apply the same rules.
+func (s *DeliveryService) acceptInTx(tx *firestore.Transaction, snaps []*firestore.DocumentSnapshot, courierSnap *firestore.DocumentSnapshot, now time.Time) error {
+	var courier api.CourierUser
+	err := courierSnap.DataTo(&courier)
+	if err != nil { return err }
+	courierUID := courierSnap.Ref.ID
+
+	list := make([]api.Delivery, len(snaps))
+	var load Capacity
+	for i, snap := range snaps {
+		err := snap.DataTo(&list[i]) // fill delivery srtuct fields
+		if err != nil { return err }
+
+		// state machine status
+		err = isValidTransition(string(list[i].Status), StatusAccepted)
+		if err != nil { return err }
+		if !released(&list[i], now) { return ErrNotReleased }
+		if courier.ZoneIds != nil && len(*courier.ZoneIds) > 0 && !inZones(list[i].ZoneId, *courier.ZoneIds) {
+			return ErrOutsideCourierZones
+		}
+		load = load.Add(deliveryLoad(&list[i]))
+	}
+	active, err := s.activeLoadInTx(tx, courierUID, &courier)
+	if err != nil { return err }
+	courier.ActiveLoad = &active
+	if active.Jobs+load.Jobs > s.activeLimit(&courier) { return ErrActiveLimitReached }
+	if !s.RemainingCapacity(&courier).Fits(load) { return ErrOverCapacity }
+
+	for i, snap := range snaps {
+		d := list[i]
+		before := d
+		d.AssignedTo = &courierUID
+		d.Status = api.DeliveryStatusAccepted
+		d.AcceptedAt = &now
+		// commit changes to DB
+		err = tx.Set(snap.Ref, d)
+		if err != nil { return err }
+		err = addDeliveryEvent(tx, s.firestore.Client, EventDeliveryAccepted, snap.Ref.ID, &before, d)
+		if err != nil { return err }
+	}
+
+	active.Jobs += load.Jobs
+	active.WeightKg += load.WeightKg
+	active.VolumeL += load.VolumeL
+	return tx.Update(courierSnap.Ref, []firestore.Update{{Path: "activeLoad", Value: active}})
+}
 
 // PATCH /deliveries/{id}
 // UpdateDeliveryStatus transitions a delivery by the assigned courier only. 
@@ -257,13 +419,31 @@ func (s *DeliveryService) UpdateDeliveryStatus(ctx context.Context, deliveryID s
 		// allow only the assigen courier to update
 		if d.AssignedTo == nil ||  *d.AssignedTo != courierUID { return ErrInvalidUpdate }
 
+		// with parcels listed, each one has to be accounted for before moving on
+		err = parcelsReady(&d, newStatus)
+		if err != nil { return err }
+
+		before := d
 		d.Status = api.DeliveryStatus(newStatus)
 		
+		if newStatus == StatusPickedUp {
+			pickedUpAt := time.Now().UTC()
+			if d.PickupAfter != nil && pickedUpAt.Before(*d.PickupAfter) { return ErrBeforePickupWindow }
+			d.PickedUpAt = &pickedUpAt
+		}
+
 		// dispatch the courier from the delivery
 		// see if nil is ok or emprty string is better
 		if newStatus == StatusDelivered { 
 			d.AssignedTo = nil;
+			d.Eta = nil;
 			d.DeliveredBy = &courierUID;
+			deliveredAt := time.Now().UTC()
+			d.DeliveredAt = &deliveredAt
+			if d.DeliverBefore != nil {
+				late := deliveredAt.After(*d.DeliverBefore)
+				d.Late = &late
+			}
 
 			// update the courier’s balance
 			courierDoc := s.firestore.Collection("users").Doc(courierUID)
@@ -275,13 +455,51 @@ func (s *DeliveryService) UpdateDeliveryStatus(ctx context.Context, deliveryID s
 			if err != nil { return err }
 
 			newBalance := courier.Balance + d.Payment
+			active, err := s.activeLoadInTx(tx, courierUID, &courier)
+			if err != nil { return err }
+
+			// the parcel is off the vehicle, making room for the next one
+			err = tx.Update(courierDoc, []firestore.Update{
+				{Path: "balance", Value: newBalance},
+				{Path: "activeLoad", Value: releaseLoad(active, deliveryLoad(&before))},
+			})
+			if err != nil { return err }
+
+			// keep the ledger complete so statements can list every balance change
+			err = addLedgerEntry(tx, s.firestore.Client, LedgerEntry{
+				CourierId:  courierUID,
+				Kind:       LedgerDelivery,
+				Amount:     d.Payment,
+				DeliveryId: &deliveryID,
+				Note:       "delivery " + d.Item,
+				CreatedAt:  deliveredAt,
+			})
+			if err != nil { return err }
+		}
+
+		// nothing was handed over: the courier is freed without pay
+		if newStatus == StatusFailed {
+			d.AssignedTo = nil
+			d.Eta = nil
+			failedAt := time.Now().UTC()
+			d.FailedAt = &failedAt
 
-			err = tx.Update(courierDoc, []firestore.Update{{Path: "balance", Value: newBalance},})
+			courierDoc := s.firestore.Collection("users").Doc(courierUID)
+			courierSnap, err := tx.Get(courierDoc)
+			if err != nil { return err }
+			var courier api.CourierUser
+			err = courierSnap.DataTo(&courier)
+			if err != nil { return err }
+			active, err := s.activeLoadInTx(tx, courierUID, &courier)
+			if err != nil { return err }
+			err = tx.Update(courierDoc, []firestore.Update{{Path: "activeLoad", Value: releaseLoad(active, deliveryLoad(&before))}})
 			if err != nil { return err }
 		}
 		snap = innerSnap
 		// commit changes to DB
-		return tx.Set(docRef, d)
+		err = tx.Set(docRef, d)
+		if err != nil { return err }
+		return addDeliveryEvent(tx, s.firestore.Client, EventDeliveryStatus, deliveryID, &before, d)
 	})
 	if err != nil { return nil, err }
 	// if reached here, the commit is successfull, the delivery is updated
@@ -296,3 +514,222 @@ func (s *DeliveryService) UpdateDeliveryStatus(ctx context.Context, deliveryID s
 	return &d, nil
 }
 
+
+var ErrNotDeliveryOwner = errors.New("delivery belongs to a different business")
+
+var ErrNotEditable = errors.New("only a posted delivery no courier accepted can be edited")
+
+var ErrInvalidEdit = errors.New("item and destinationAddress can't be empty and payment can't be negative")
+
+// DeliveryEdit is what the owning business may change on a posted delivery; nil fields stay.
+type DeliveryEdit struct {
+	Item                *string
+	DestinationAddress  *string
+	DestinationLocation *api.GeoPoint
+	Payment             *float64
+}
+
+// PATCH /deliveries/{id}
+// EditDelivery applies the owning business's changes to a delivery still waiting for a courier.
+// The status is checked in the same transaction as the write, so an edit racing an accept
+// fails with ErrNotEditable instead of changing a job the courier already took.
+// A new destination or payment is checked against the zone rules like a new delivery:
+// coverage and minimum payment, and for a new destination the zone's opening hours.
+func (s *DeliveryService) EditDelivery(ctx context.Context, deliveryID, businessUID string, edit DeliveryEdit) (*api.Delivery, error) {
+	if edit.Item != nil && strings.TrimSpace(*edit.Item) == "" { return nil, ErrInvalidEdit }
+	if edit.DestinationAddress != nil && strings.TrimSpace(*edit.DestinationAddress) == "" { return nil, ErrInvalidEdit }
+	if edit.Payment != nil && *edit.Payment < 0 { return nil, ErrInvalidEdit }
+
+	docRef := s.firestore.Collection("deliveries").Doc(deliveryID)
+	var d api.Delivery
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound { return ErrDeliveryNotFound }
+		if err != nil { return err }
+		d = api.Delivery{}
+		err = snap.DataTo(&d)
+		if err != nil { return err }
+
+		if d.BusinessId == nil || *d.BusinessId != businessUID { return ErrNotDeliveryOwner }
+		if d.Status != api.DeliveryStatusPosted || d.AssignedTo != nil { return ErrNotEditable }
+
+		before := d
+		if edit.Item != nil { d.Item = strings.TrimSpace(*edit.Item) }
+		if edit.DestinationAddress != nil { d.DestinationAddress = strings.TrimSpace(*edit.DestinationAddress) }
+		if edit.DestinationLocation != nil { d.DestinationLocation = *edit.DestinationLocation }
+		if edit.Payment != nil { d.Payment = roundCents(*edit.Payment) }
+
+		if edit.DestinationLocation != nil || edit.Payment != nil {
+			z, err := s.zones.locate(ctx, d.DestinationLocation)
+			if err != nil { return err }
+			d.ZoneId = nil
+			if z != nil {
+				// a new destination has to be in a zone open at pickup, as for a new delivery;
+				// a payment change alone doesn't re-check the hours it passed when posted
+				if edit.DestinationLocation != nil {
+					pickupAt := time.Now().UTC()
+					if d.PickupAfter != nil && d.PickupAfter.After(pickupAt) { pickupAt = *d.PickupAfter }
+					if !z.openAt(pickupAt) { return fmt.Errorf("%w (%s)", ErrZoneClosed, z.Name) }
+				}
+				if z.MinPayment != nil && d.Payment < *z.MinPayment {
+					return fmt.Errorf("%w (%.2f in %s)", ErrBelowZoneMinimum, *z.MinPayment, z.Name)
+				}
+				d.ZoneId = &z.Id
+			}
+		}
+
+		err = tx.Set(docRef, d)
+		if err != nil { return err }
+		return addDeliveryEvent(tx, s.firestore.Client, EventDeliveryEdited, deliveryID, &before, d)
+	})
+	if err != nil { return nil, err }
+	d.Id = &deliveryID
+	return &d, nil
+}
+
+var ErrNotDelivered = errors.New("delivery is not delivered yet")
+
+var ErrAlreadyTipped = errors.New("delivery already tipped")
+
+var ErrTipWindowClosed = errors.New("tip window for this delivery has closed")
+
+// POST /deliveries/{id}/tip
+// TipDelivery lets the owning business add a tip to a delivered delivery.
+// Allowed once per delivery and only within tipWindow after delivery.
+// The delivery, the DeliveredBy courier's balance/tipsTotal and a ledger entry
+// are written in one transaction, so a tip is never recorded without being paid.
+func (s *DeliveryService) TipDelivery(ctx context.Context, deliveryID, businessUID string, amount float64) (*api.Delivery, error) {
+	amount = roundCents(amount)
+	if amount <= 0 { return nil, ErrInvalidAmount }
+
+	docRef := s.firestore.Collection("deliveries").Doc(deliveryID)
+	var d api.Delivery
+
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(docRef)
+		if err != nil { return err }
+
+		err = snap.DataTo(&d)
+		if err != nil { return err }
+
+		if d.BusinessId == nil || *d.BusinessId != businessUID { return ErrNotDeliveryOwner }
+		if d.Status != api.DeliveryStatusDelivered || d.DeliveredBy == nil { return ErrNotDelivered }
+		if d.TippedAt != nil { return ErrAlreadyTipped }
+
+		// deliveries completed before deliveredAt existed fall back to their creation time
+		deliveredAt := d.DeliveredAt
+		if deliveredAt == nil { deliveredAt = d.CreatedAt }
+		now := time.Now().UTC()
+		if deliveredAt == nil || now.Sub(*deliveredAt) > s.tipWindow { return ErrTipWindowClosed }
+
+		courierRef := s.firestore.Collection("users").Doc(*d.DeliveredBy)
+		courierSnap, err := tx.Get(courierRef)
+		if err != nil { return err }
+
+		var courier api.CourierUser
+		err = courierSnap.DataTo(&courier)
+		if err != nil { return err }
+
+		err = tx.Update(courierRef, []firestore.Update{
+			{Path: "balance", Value: roundCents(courier.Balance + amount)},
+			{Path: "tipsTotal", Value: roundCents(courier.TipsTotal + amount)},
+		})
+		if err != nil { return err }
+
+		err = addLedgerEntry(tx, s.firestore.Client, LedgerEntry{
+			CourierId:  *d.DeliveredBy,
+			Kind:       LedgerTip,
+			Amount:     amount,
+			DeliveryId: &deliveryID,
+			Note:       "tip from " + d.BusinessName,
+			CreatedAt:  now,
+		})
+		if err != nil { return err }
+
+		d.Tip = amount
+		d.TippedAt = &now
+		return tx.Set(docRef, d)
+	})
+	if err != nil { return nil, err }
+
+	d.Id = &deliveryID
+	return &d, nil
+}
+
+var ErrInvalidScore = errors.New("score must be between 1 and 5")
+
+var ErrNotDeliveryParty = errors.New("only the delivery's business or courier can rate it")
+
+var ErrAlreadyRated = errors.New("delivery already rated")
+
+// POST /deliveries/{id}/rating
+// RateDelivery stores a 1–5 rating from one party of a delivered delivery about the other:
+// the owning business rates the courier (courierRating), the courier rates the business (businessRating).
+// Each side may rate once. The rating and the rated user's ratingAverage/ratingCount are written
+// in one transaction, so the aggregate always matches the stored ratings.
+func (s *DeliveryService) RateDelivery(ctx context.Context, deliveryID, raterUID string, score int, comment *string) (*api.Delivery, error) {
+	if score < 1 || score > 5 { return nil, ErrInvalidScore }
+	if comment != nil {
+		trimmed := strings.TrimSpace(*comment)
+		comment = &trimmed
+		if trimmed == "" { comment = nil }
+	}
+
+	docRef := s.firestore.Collection("deliveries").Doc(deliveryID)
+	var d api.Delivery
+
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(docRef)
+		if err != nil { return err }
+
+		err = snap.DataTo(&d)
+		if err != nil { return err }
+
+		if d.Status != api.DeliveryStatusDelivered || d.DeliveredBy == nil { return ErrNotDelivered }
+
+		// who rates whom is decided by the caller's relation to the delivery, not by role
+		var ratedUID string
+		var existing *api.Rating
+		switch {
+		case d.BusinessId != nil && *d.BusinessId == raterUID:
+			ratedUID, existing = *d.DeliveredBy, d.CourierRating
+		case *d.DeliveredBy == raterUID && d.BusinessId != nil:
+			ratedUID, existing = *d.BusinessId, d.BusinessRating
+		default:
+			return ErrNotDeliveryParty
+		}
+		if existing != nil { return ErrAlreadyRated }
+
+		ratedRef := s.firestore.Collection("users").Doc(ratedUID)
+		ratedSnap, err := tx.Get(ratedRef)
+		if err != nil { return err }
+
+		// both user shapes carry the same aggregate fields
+		var agg struct {
+			RatingAverage float64 `firestore:"ratingAverage"`
+			RatingCount   int     `firestore:"ratingCount"`
+		}
+		err = ratedSnap.DataTo(&agg)
+		if err != nil { return err }
+
+		count := agg.RatingCount + 1
+		average := (agg.RatingAverage*float64(agg.RatingCount) + float64(score)) / float64(count)
+		err = tx.Update(ratedRef, []firestore.Update{
+			{Path: "ratingAverage", Value: average},
+			{Path: "ratingCount", Value: count},
+		})
+		if err != nil { return err }
+
+		rating := &api.Rating{Score: score, Comment: comment, RatedBy: raterUID, CreatedAt: time.Now().UTC()}
+		if ratedUID == *d.DeliveredBy {
+			d.CourierRating = rating
+		} else {
+			d.BusinessRating = rating
+		}
+		return tx.Set(docRef, d)
+	})
+	if err != nil { return nil, err }
+
+	d.Id = &deliveryID
+	return &d, nil
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"google.golang.org/api/iterator"
+)
+
+// EarningsService builds courier earnings reports and monthly statements.
+// Delivered jobs come from /deliveries (deliveredBy), tips, adjustments and payouts
+// from the /ledger; nothing is stored, every report is computed on request.
+type EarningsService struct {
+	firestore *db.FirestoreClient
+}
+
+// NewEarningsService wires Firestore into the earnings domain.
+// called once from main.go at startup
+func NewEarningsService(fs *db.FirestoreClient) *EarningsService {
+	return &EarningsService{firestore: fs}
+}
+
+var ErrInvalidRange = errors.New("invalid date range")
+
+// maxEarningsRange bounds one report so a day-grouped request stays small.
+const maxEarningsRange = 2 * 366 * 24 * time.Hour
+
+// Statement formats.
+const (
+	StatementPDF = "pdf"
+	StatementCSV = "csv"
+)
+
+// earningsItem is one line of a statement: a delivered job or a ledger movement.
+type earningsItem struct {
+	At          time.Time
+	Kind        string // one of the Ledger* kinds
+	Description string
+	Amount      float64 // signed, payouts are negative
+}
+
+// collectItems returns everything that moved the courier's balance in [from, to), oldest first.
+func (s *EarningsService) collectItems(ctx context.Context, courierUID string, from, to time.Time) ([]earningsItem, error) {
+	var items []earningsItem
+
+	// delivered jobs; older deliveries have no deliveredAt, so range-filter here instead of in the query
+	iter := s.firestore.Collection("deliveries").Where("deliveredBy", "==", courierUID).Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var d api.Delivery
+		if err := doc.DataTo(&d); err != nil { continue }
+		at := d.DeliveredAt
+		if at == nil { at = d.CreatedAt }
+		if at == nil || at.Before(from) || !at.Before(to) { continue }
+
+		items = append(items, earningsItem{
+			At:          *at,
+			Kind:        LedgerDelivery,
+			Description: fmt.Sprintf("%s: %s -> %s", d.BusinessName, d.Item, d.DestinationAddress),
+			Amount:      d.Payment,
+		})
+	}
+
+	// tips, adjustments and payouts; delivery entries are skipped, the deliveries above are the source of truth
+	ledgerIter := s.firestore.Collection("ledger").
+		Where("courierId", "==", courierUID).
+		Where("createdAt", ">=", from).
+		Where("createdAt", "<", to).
+		Documents(ctx)
+	defer ledgerIter.Stop()
+	for {
+		doc, err := ledgerIter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var e LedgerEntry
+		if err := doc.DataTo(&e); err != nil { continue }
+		if e.Kind == LedgerDelivery { continue }
+
+		items = append(items, earningsItem{
+			At:          e.CreatedAt,
+			Kind:        e.Kind,
+			Description: e.Note,
+			Amount:      e.Amount,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].At.Before(items[j].At) })
+	return items, nil
+}
+
+// periodStart truncates t (UTC) to the start of its day, ISO week (Monday) or month.
+func periodStart(t time.Time, groupBy string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch groupBy {
+	case "week":
+		offset := (int(day.Weekday()) + 6) % 7 // Monday = 0
+		return day.AddDate(0, 0, -offset)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func nextPeriod(start time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// addToPeriod folds one item into a period's sums.
+func addToPeriod(p *api.EarningsPeriod, it earningsItem) {
+	switch it.Kind {
+	case LedgerDelivery:
+		p.Deliveries++
+		p.DeliveryEarnings += it.Amount
+	case LedgerTip:
+		p.Tips += it.Amount
+	case LedgerPayout:
+		p.Payouts -= it.Amount // stored negative, reported positive
+	default:
+		p.Adjustments += it.Amount
+	}
+}
+
+func finishPeriod(p *api.EarningsPeriod) {
+	p.DeliveryEarnings = roundCents(p.DeliveryEarnings)
+	p.Tips = roundCents(p.Tips)
+	p.Adjustments = roundCents(p.Adjustments)
+	p.Payouts = roundCents(p.Payouts)
+	p.Net = roundCents(p.DeliveryEarnings + p.Tips + p.Adjustments - p.Payouts)
+}
+
+// GET /couriers/me/earnings
+// GetEarnings aggregates the courier's earnings in [from, to) into day/week/month periods.
+// Empty periods are included so clients can chart the range directly.
+func (s *EarningsService) GetEarnings(ctx context.Context, courierUID string, from, to time.Time, groupBy string) (*api.EarningsReport, error) {
+	if !from.Before(to) || to.Sub(from) > maxEarningsRange {
+		return nil, ErrInvalidRange
+	}
+	items, err := s.collectItems(ctx, courierUID, from, to)
+	if err != nil { return nil, err }
+
+	report := &api.EarningsReport{
+		From:    from,
+		To:      to,
+		GroupBy: api.EarningsReportGroupBy(groupBy),
+		Periods: []api.EarningsPeriod{},
+		Totals:  api.EarningsPeriod{Start: from, End: to},
+	}
+
+	next := 0
+	for start := periodStart(from, groupBy); start.Before(to); start = nextPeriod(start, groupBy) {
+		p := api.EarningsPeriod{Start: start, End: nextPeriod(start, groupBy)}
+		for next < len(items) && items[next].At.Before(p.End) {
+			addToPeriod(&p, items[next])
+			addToPeriod(&report.Totals, items[next])
+			next++
+		}
+		finishPeriod(&p)
+		report.Periods = append(report.Periods, p)
+	}
+	finishPeriod(&report.Totals)
+	return report, nil
+}
+
+// GET /couriers/me/statements/{month}
+// Statement renders one calendar month (month = "YYYY-MM") as a PDF or CSV file.
+// Returns the file content, its content type and a suggested file name.
+func (s *EarningsService) Statement(ctx context.Context, courierUID, month, format string) ([]byte, string, string, error) {
+	start, err := time.Parse("2006-01", month)
+	if err != nil { return nil, "", "", ErrInvalidRange }
+	end := start.AddDate(0, 1, 0)
+
+	snap, err := s.firestore.Collection("users").Doc(courierUID).Get(ctx)
+	if err != nil { return nil, "", "", err }
+	var courier api.CourierUser
+	err = snap.DataTo(&courier)
+	if err != nil { return nil, "", "", err }
+
+	items, err := s.collectItems(ctx, courierUID, start, end)
+	if err != nil { return nil, "", "", err }
+
+	totals := api.EarningsPeriod{Start: start, End: end}
+	for _, it := range items {
+		addToPeriod(&totals, it)
+	}
+	finishPeriod(&totals)
+
+	fileName := "statement-" + month
+	switch format {
+	case "", StatementPDF:
+		return renderTextPDF(statementLines(courier.CourierName, month, items, totals)), "application/pdf", fileName + ".pdf", nil
+
+	case StatementCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write([]string{"date", "type", "description", "amount"})
+		for _, it := range items {
+			// descriptions carry business names, items and addresses, see csvCell
+			_ = w.Write([]string{it.At.Format(time.RFC3339), csvCell(it.Kind), csvCell(it.Description), fmt.Sprintf("%.2f", it.Amount)})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil { return nil, "", "", err }
+		return buf.Bytes(), "text/csv", fileName + ".csv", nil
+	}
+	return nil, "", "", ErrUnsupportedFormat
+}
+
+// statementLines lays out the PDF statement as fixed-width text.
+func statementLines(courierName, month string, items []earningsItem, totals api.EarningsPeriod) []string {
+	lines := []string{
+		"EARNINGS STATEMENT",
+		"",
+		"Courier: " + courierName,
+		"Period:  " + month,
+		"",
+		fmt.Sprintf("%-17s %-10s %-40s %10s", "Date", "Type", "Description", "Amount"),
+		fmt.Sprintf("%-17s %-10s %-40s %10s", "----", "----", "-----------", "------"),
+	}
+	for _, it := range items {
+		desc := it.Description
+		if len(desc) > 40 { desc = desc[:37] + "..." }
+		lines = append(lines, fmt.Sprintf("%-17s %-10s %-40s %10.2f", it.At.Format("2006-01-02 15:04"), it.Kind, desc, it.Amount))
+	}
+	if len(items) == 0 {
+		lines = append(lines, "No activity in this period.")
+	}
+	lines = append(lines,
+		"",
+		fmt.Sprintf("%-30s %10d", "Delivered jobs", totals.Deliveries),
+		fmt.Sprintf("%-30s %10.2f", "Delivery earnings", totals.DeliveryEarnings),
+		fmt.Sprintf("%-30s %10.2f", "Tips", totals.Tips),
+		fmt.Sprintf("%-30s %10.2f", "Adjustments", totals.Adjustments),
+		fmt.Sprintf("%-30s %10.2f", "Payouts", -totals.Payouts),
+		fmt.Sprintf("%-30s %10.2f", "Net", totals.Net),
+	)
+	return lines
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EtaService estimates when a courier reaches the pickup and the destination.
+//
+// Travel times come from historical speeds: every completed drop-off leg (pickedUpAt →
+// deliveredAt over the routed pickup-to-destination distance) is a sample for its zone —
+// a ETA_ZONE_DEG grid cell around the pickup — and hour of day. Estimates divide the
+// routed distance by the zone/hour average once it has enough samples, then by the
+// city-wide average for that hour, and otherwise take the router's own travel time.
+//
+// ETAs are stored on the delivery (eta), so every delivery response and the tracking page
+// carry them. They are recomputed when a courier accepts or picks up, and whenever the
+// location hub sees the courier move (at most once per ETA_REFRESH per courier).
+type EtaService struct {
+	firestore    *db.FirestoreClient
+	hub         *LocationHub
+	router      Router
+	zoneDeg     float64        // env ETA_ZONE_DEG, zone grid size in degrees
+	minSamples  int            // env ETA_MIN_SAMPLES, before a zone/hour average is trusted
+	pickupDwell time.Duration  // env ETA_PICKUP_DWELL, time spent at the business
+	refresh     time.Duration  // env ETA_REFRESH
+	tz          *time.Location // env ETA_TIMEZONE, for hour-of-day buckets
+
+	mu         sync.Mutex
+	lastRun    map[string]time.Time   // courier → last recompute from a location update
+	speedCache map[string]cachedSpeed // stats doc id → average
+}
+
+type cachedSpeed struct {
+	kmh     float64 // 0 when not enough samples
+	fetched time.Time
+}
+
+// speedCacheTTL bounds how stale a cached average may get; stats move slowly.
+const speedCacheTTL = 15 * time.Minute
+
+// NewEtaService wires Firestore, the location hub and the router into ETA estimation.
+// called once from main.go at startup; Run must be started for location-driven updates
+func NewEtaService(fs *db.FirestoreClient, hub *LocationHub, router Router) (*EtaService, error) {
+	tz, err := time.LoadLocation(config.String("ETA_TIMEZONE", "UTC"))
+	if err != nil { return nil, fmt.Errorf("ETA_TIMEZONE: %w", err) }
+	return &EtaService{
+		firestore:   fs,
+		hub:         hub,
+		router:      router,
+		zoneDeg:     config.Float("ETA_ZONE_DEG", 0.1),
+		minSamples:  config.Int("ETA_MIN_SAMPLES", 5),
+		pickupDwell: config.Duration("ETA_PICKUP_DWELL", 3*time.Minute),
+		refresh:     config.Duration("ETA_REFRESH", 30*time.Second),
+		tz:          tz,
+		lastRun:     map[string]time.Time{},
+		speedCache:  map[string]cachedSpeed{},
+	}, nil
+}
+
+// speedStats is /speedStats/{zone}_{hour} (or all_{hour}): running totals of drop-off legs.
+type speedStats struct {
+	Samples int     `firestore:"samples"`
+	Km      float64 `firestore:"km"`
+	Hours   float64 `firestore:"hours"`
+}
+
+// zoneKey names the grid cell containing p.
+func (s *EtaService) zoneKey(p api.GeoPoint) string {
+	return fmt.Sprintf("z%d_%d", int(math.Floor(p.Lat/s.zoneDeg)), int(math.Floor(p.Lng/s.zoneDeg)))
+}
+
+// historicalSpeed returns the average speed of past rides around p at the hour of at;
+// 0 when there is no history to go by.
+func (s *EtaService) historicalSpeed(ctx context.Context, p api.GeoPoint, at time.Time) float64 {
+	hour := at.In(s.tz).Hour()
+	for _, id := range []string{fmt.Sprintf("%s_%02d", s.zoneKey(p), hour), fmt.Sprintf("all_%02d", hour)} {
+		if kmh := s.averageSpeed(ctx, id); kmh > 0 { return kmh }
+	}
+	return 0
+}
+
+// averageSpeed reads one stats doc through the cache; 0 without enough samples or on error.
+func (s *EtaService) averageSpeed(ctx context.Context, id string) float64 {
+	s.mu.Lock()
+	c, ok := s.speedCache[id]
+	s.mu.Unlock()
+	if ok && time.Since(c.fetched) < speedCacheTTL { return c.kmh }
+
+	kmh := 0.0
+	doc, err := s.firestore.Collection("speedStats").Doc(id).Get(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return 0 // transient: don't cache
+	}
+	if err == nil {
+		var st speedStats
+		if doc.DataTo(&st) == nil && st.Samples >= s.minSamples && st.Hours > 0 {
+			kmh = st.Km / st.Hours
+		}
+	}
+	s.mu.Lock()
+	s.speedCache[id] = cachedSpeed{kmh: kmh, fetched: time.Now()}
+	s.mu.Unlock()
+	return kmh
+}
+
+// legDuration is the expected travel time from a to b when leaving at `at`.
+func (s *EtaService) legDuration(ctx context.Context, a, b api.GeoPoint, at time.Time) (time.Duration, error) {
+	route, err := s.router.Route(ctx, a, b)
+	if err != nil { return 0, err }
+	if kmh := s.historicalSpeed(ctx, a, at); kmh > 0 {
+		return time.Duration(route.DistanceKm / kmh * float64(time.Hour)), nil
+	}
+	return route.Duration, nil
+}
+
+// Estimate computes pickup and drop-off ETAs for a delivery the courier at loc is on;
+// nil when the delivery isn't accepted or picked up.
+func (s *EtaService) Estimate(ctx context.Context, d *api.Delivery, loc *CourierLocation, now time.Time) (*api.DeliveryEta, error) {
+	here := api.GeoPoint{Lat: loc.Lat, Lng: loc.Lng}
+	eta := &api.DeliveryEta{UpdatedAt: now}
+	switch d.Status {
+	case StatusAccepted:
+		toPickup, err := s.legDuration(ctx, here, d.BusinessLocation, now)
+		if err != nil { return nil, err }
+		pickup := now.Add(toPickup).Truncate(time.Minute)
+		// the parcel isn't ready before its pickup window opens
+		if d.PickupAfter != nil && pickup.Before(*d.PickupAfter) { pickup = d.PickupAfter.Truncate(time.Minute) }
+		leave := pickup.Add(s.pickupDwell)
+		toDropoff, err := s.legDuration(ctx, d.BusinessLocation, d.DestinationLocation, leave)
+		if err != nil { return nil, err }
+		eta.PickupAt = &pickup
+		eta.DropoffAt = leave.Add(toDropoff).Truncate(time.Minute)
+	case StatusPickedUp:
+		toDropoff, err := s.legDuration(ctx, here, d.DestinationLocation, now)
+		if err != nil { return nil, err }
+		eta.DropoffAt = now.Add(toDropoff).Truncate(time.Minute)
+	default:
+		return nil, nil
+	}
+	return eta, nil
+}
+
+// RefreshCourier recomputes the ETAs of every delivery the courier is on. The doc is only
+// written when an ETA moved, so a courier waiting at a red light costs no writes.
+func (s *EtaService) RefreshCourier(ctx context.Context, loc *CourierLocation) error {
+	iter := s.firestore.Collection("deliveries").
+		Where("assignedTo", "==", loc.CourierId).
+		Where("status", "in", []string{StatusAccepted, StatusPickedUp}).
+		Documents(ctx)
+	defer iter.Stop()
+
+	now := time.Now().UTC()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return err }
+
+		var d api.Delivery
+		if err := doc.DataTo(&d); err != nil { continue }
+		eta, err := s.Estimate(ctx, &d, loc, now)
+		if err != nil { return err }
+		if eta == nil || sameEta(d.Eta, eta) { continue }
+		// only if the delivery didn't change meanwhile (e.g. was just delivered)
+		_, err = doc.Ref.Update(ctx, []firestore.Update{{Path: "eta", Value: eta}}, firestore.LastUpdateTime(doc.UpdateTime))
+		if err != nil && status.Code(err) != codes.FailedPrecondition { return err }
+	}
+	return nil
+}
+
+// sameEta reports whether b would tell the user nothing new over a.
+func sameEta(a, b *api.DeliveryEta) bool {
+	if a == nil || b == nil { return a == b }
+	if (a.PickupAt == nil) != (b.PickupAt == nil) { return false }
+	if a.PickupAt != nil && !a.PickupAt.Equal(*b.PickupAt) { return false }
+	return a.DropoffAt.Equal(b.DropoffAt)
+}
+
+// Run recomputes ETAs from live courier positions until ctx is cancelled.
+func (s *EtaService) Run(ctx context.Context) {
+	sub := s.hub.Subscribe(true)
+	defer s.hub.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Notify:
+			for _, loc := range sub.Drain() {
+				if !s.due(loc.CourierId) { continue }
+				if err := s.RefreshCourier(ctx, &loc); err != nil && ctx.Err() == nil {
+					log.Printf("eta refresh for %s: %v", loc.CourierId, err)
+				}
+			}
+		}
+	}
+}
+
+// due rate-limits location-driven recomputes per courier.
+func (s *EtaService) due(courierUID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastRun[courierUID]) < s.refresh { return false }
+	s.lastRun[courierUID] = now
+	return true
+}
+
+// Name identifies ETA upkeep as an outbox sink.
+func (s *EtaService) Name() string { return "eta" }
+
+// Deliver is the outbox sink: accepting or picking up changes what is left to ride, so the
+// ETA is recomputed right away; a completed delivery becomes a speed sample.
+func (s *EtaService) Deliver(ctx context.Context, e *DeliveryEvent) error {
+	d := e.Delivery
+	switch {
+	case d.Status == StatusDelivered && e.Type == EventDeliveryStatus:
+		return s.recordSample(ctx, e.DeliveryId, &d)
+	case d.Status == StatusAccepted || d.Status == StatusPickedUp:
+		if d.AssignedTo == nil { return nil }
+		loc, err := lastCourierLocation(ctx, s.firestore, *d.AssignedTo)
+		if err != nil || loc == nil { return err }
+		s.mu.Lock()
+		s.lastRun[loc.CourierId] = time.Now()
+		s.mu.Unlock()
+		return s.RefreshCourier(ctx, loc)
+	}
+	return nil
+}
+
+// speedSampleBounds drops legs that can't be real rides (a courier marking delivered
+// hours later, or right after pickup) so they don't skew the averages.
+const (
+	minSampleKmh = 2.0
+	maxSampleKmh = 120.0
+)
+
+// recordSample adds a delivered drop-off leg to its zone/hour stats and the city-wide
+// hour stats, once per delivery (the marker doc makes redelivered events a no-op).
+func (s *EtaService) recordSample(ctx context.Context, deliveryID string, d *api.Delivery) error {
+	if d.PickedUpAt == nil || d.DeliveredAt == nil { return nil }
+	hours := d.DeliveredAt.Sub(*d.PickedUpAt).Hours()
+	route, err := s.router.Route(ctx, d.BusinessLocation, d.DestinationLocation)
+	if err != nil { return err }
+	km := route.DistanceKm
+	if hours <= 0 || km/hours < minSampleKmh || km/hours > maxSampleKmh { return nil }
+
+	hour := d.PickedUpAt.In(s.tz).Hour()
+	markerRef := s.firestore.Collection("speedSamples").Doc(deliveryID)
+	statRefs := []*firestore.DocumentRef{
+		s.firestore.Collection("speedStats").Doc(fmt.Sprintf("%s_%02d", s.zoneKey(d.BusinessLocation), hour)),
+		s.firestore.Collection("speedStats").Doc(fmt.Sprintf("all_%02d", hour)),
+	}
+	return s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		_, err := tx.Get(markerRef)
+		if err == nil { return nil } // already counted
+		if status.Code(err) != codes.NotFound { return err }
+
+		err = tx.Create(markerRef, map[string]any{"km": km, "hours": hours, "createdAt": time.Now().UTC()})
+		if err != nil { return err }
+		for _, ref := range statRefs {
+			err = tx.Set(ref, map[string]any{
+				"samples": firestore.Increment(1),
+				"km":      firestore.Increment(km),
+				"hours":   firestore.Increment(hours),
+			}, firestore.MergeAll)
+			if err != nil { return err }
+		}
+		return nil
+	})
+}
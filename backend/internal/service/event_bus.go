@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/internal/config"
+)
+
+// EventEnvelopeVersion is bumped whenever EventEnvelope changes incompatibly,
+// so consumers can tell which shape they are reading.
+const EventEnvelopeVersion = 1
+
+// EventEnvelope is what other services receive for every delivery state machine transition.
+// Id is stable across redeliveries of the same event, so consumers can deduplicate on it.
+// On the wire it is JSON with camelCase keys, the deliveries included (see DeliveryJSON).
+type EventEnvelope struct {
+	Version    int               `json:"version"`
+	Id         string            `json:"id"`
+	Type       string            `json:"type"` // delivery.created, delivery.released, delivery.accepted, delivery.status, delivery.edited, delivery.repriced, delivery.unaccepted, delivery.parcel_scanned, delivery.parcel_missing
+	Source     string            `json:"source"`
+	DeliveryId string            `json:"deliveryId"`
+	OccurredAt time.Time         `json:"occurredAt"`
+	Data       EventEnvelopeData `json:"data"`
+}
+
+// EventEnvelopeData carries the delivery after the transition and, when it existed, before it.
+type EventEnvelopeData struct {
+	Delivery DeliveryJSON  `json:"delivery"`
+	Previous *DeliveryJSON `json:"previous,omitempty"`
+}
+
+// NewEventEnvelope wraps a delivery event in the current envelope version.
+func NewEventEnvelope(e *DeliveryEvent) *EventEnvelope {
+	return &EventEnvelope{
+		Version:    EventEnvelopeVersion,
+		Id:         e.Id,
+		Type:       e.Type,
+		Source:     "courier-system",
+		DeliveryId: e.DeliveryId,
+		OccurredAt: e.CreatedAt,
+		Data:       EventEnvelopeData{Delivery: DeliveryJSON(e.Delivery), Previous: (*DeliveryJSON)(e.Before)},
+	}
+}
+
+// EventPublisher hands envelopes to a message bus. Publish returns once the bus has taken
+// the event, so a failure leaves it in the outbox for a retry.
+type EventPublisher interface {
+	Publish(ctx context.Context, env *EventEnvelope) error
+	Close() error
+}
+
+// NewEventPublisher builds the publisher selected by EVENT_BUS (nats, kafka or channel).
+// Returns nil when EVENT_BUS is unset: delivery events then stay internal.
+// called once from main.go at startup
+func NewEventPublisher() (EventPublisher, error) {
+	topic := config.String("EVENT_BUS_TOPIC", "courier.events")
+	switch bus := config.String("EVENT_BUS", ""); bus {
+	case "":
+		return nil, nil
+	case "nats":
+		return NewNATSPublisher(config.String("NATS_URL", "nats://localhost:4222"), topic)
+	case "kafka":
+		return NewKafkaPublisher(config.String("KAFKA_REST_URL", "http://localhost:8082"), topic)
+	case "channel":
+		return NewChannelBus(), nil
+	default:
+		return nil, fmt.Errorf("unknown EVENT_BUS %q (want nats, kafka or channel)", bus)
+	}
+}
+
+// EventBusSink is the outbox sink that publishes every delivery event on the bus.
+type EventBusSink struct {
+	publisher EventPublisher
+}
+
+// NewEventBusSink feeds the outbox into publisher.
+// called once from main.go when a bus is configured
+func NewEventBusSink(publisher EventPublisher) *EventBusSink {
+	return &EventBusSink{publisher: publisher}
+}
+
+// Name identifies the bus as an outbox sink.
+func (s *EventBusSink) Name() string { return "eventbus" }
+
+// Deliver publishes one delivery event.
+func (s *EventBusSink) Deliver(ctx context.Context, e *DeliveryEvent) error {
+	return s.publisher.Publish(ctx, NewEventEnvelope(e))
+}
+
+var ErrEventBusClosed = errors.New("event bus closed")
+
+// ChannelBus is an in-process EventPublisher: every subscriber gets every envelope
+// on its own channel. Meant for tests and for consumers living in the same binary.
+type ChannelBus struct {
+	mu     sync.RWMutex
+	subs   []chan *EventEnvelope
+	closed bool
+}
+
+// NewChannelBus returns an empty in-process bus.
+func NewChannelBus() *ChannelBus {
+	return &ChannelBus{}
+}
+
+// Subscribe returns a channel receiving every envelope published from now on.
+// Publish waits for slow subscribers once their buffer is full, so keep reading.
+func (b *ChannelBus) Subscribe(buffer int) <-chan *EventEnvelope {
+	ch := make(chan *EventEnvelope, buffer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	b.subs = append(b.subs, ch)
+	return ch
+}
+
+// Publish hands env to every subscriber, giving up when ctx is cancelled.
+func (b *ChannelBus) Publish(ctx context.Context, env *EventEnvelope) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed { return ErrEventBusClosed }
+
+	for _, ch := range b.subs {
+		select {
+		case ch <- env:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close closes every subscriber channel.
+func (b *ChannelBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed { return nil }
+	b.closed = true
+	for _, ch := range b.subs {
+		close(ch)
+	}
+	b.subs = nil
+	return nil
+}
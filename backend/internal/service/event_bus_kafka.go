@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// KafkaPublisher produces envelopes to one Kafka topic through a Kafka REST Proxy
+// (POST /topics/{topic}, v2 JSON embedded format). The delivery id is the record key,
+// so all events of a delivery land on the same partition, in order.
+type KafkaPublisher struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewKafkaPublisher targets topic on the REST proxy at restURL.
+func NewKafkaPublisher(restURL, topic string) (*KafkaPublisher, error) {
+	u, err := url.Parse(restURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("KAFKA_REST_URL must be an http(s) URL, got %q", restURL)
+	}
+	return &KafkaPublisher{
+		endpoint: strings.TrimRight(restURL, "/") + "/topics/" + url.PathEscape(topic),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+type kafkaRecord struct {
+	Key   string         `json:"key"`
+	Value *EventEnvelope `json:"value"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		Partition int     `json:"partition"`
+		Offset    int64   `json:"offset"`
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Publish produces env and returns once the proxy reports it written.
+func (p *KafkaPublisher) Publish(ctx context.Context, env *EventEnvelope) error {
+	body, err := json.Marshal(map[string][]kafkaRecord{"records": {{Key: env.DeliveryId, Value: env}}})
+	if err != nil { return err }
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil { return err }
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := p.client.Do(req)
+	if err != nil { return fmt.Errorf("kafka produce: %w", err) }
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kafka produce: %s: %s", resp.Status, bytes.TrimSpace(raw))
+	}
+
+	var out kafkaProduceResponse
+	if err := json.Unmarshal(raw, &out); err != nil { return fmt.Errorf("kafka produce: %w", err) }
+	for _, o := range out.Offsets {
+		if o.Error != nil { return fmt.Errorf("kafka produce: partition %d: %s", o.Partition, *o.Error) }
+	}
+	return nil
+}
+
+// Close releases idle proxy connections.
+func (p *KafkaPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const natsTimeout = 10 * time.Second
+
+// NATSPublisher publishes envelopes on <subject prefix>.<event type>
+// (e.g. courier.events.delivery.accepted), speaking the plain-text NATS core protocol.
+// Every publish is followed by a PING/PONG round trip, so Publish only returns nil
+// once the server has processed the message. The connection is opened lazily and
+// re-dialled after any failure.
+type NATSPublisher struct {
+	addr    string
+	connect []byte // CONNECT line, with credentials from the URL
+	prefix  string
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewNATSPublisher parses a nats://[user[:pass]@]host:port URL; a user without
+// a password is sent as an auth token.
+func NewNATSPublisher(rawURL, subjectPrefix string) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil { return nil, fmt.Errorf("NATS_URL: %w", err) }
+	if u.Scheme != "nats" || u.Host == "" {
+		return nil, fmt.Errorf("NATS_URL must look like nats://host:4222, got %q", rawURL)
+	}
+	addr := u.Host
+	if u.Port() == "" { addr = net.JoinHostPort(u.Hostname(), "4222") }
+
+	opts := map[string]any{"verbose": false, "pedantic": false, "name": "courier-system", "lang": "go", "version": "1"}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			opts["user"] = u.User.Username()
+			opts["pass"] = pass
+		} else {
+			opts["auth_token"] = u.User.Username()
+		}
+	}
+	connect, err := json.Marshal(opts)
+	if err != nil { return nil, err }
+
+	return &NATSPublisher{
+		addr:    addr,
+		connect: []byte("CONNECT " + string(connect) + "\r\n"),
+		prefix:  subjectPrefix,
+	}, nil
+}
+
+// Publish sends env and waits for the server to acknowledge it. A stale connection
+// (e.g. dropped by the server while idle) is replaced once before giving up.
+func (p *NATSPublisher) Publish(ctx context.Context, env *EventEnvelope) error {
+	body, err := json.Marshal(env)
+	if err != nil { return err }
+	msg := fmt.Appendf(nil, "PUB %s.%s %d\r\n%s\r\nPING\r\n", p.prefix, env.Type, len(body), body)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fresh := p.conn == nil
+	err = p.publish(ctx, msg)
+	if err != nil && !fresh && ctx.Err() == nil {
+		err = p.publish(ctx, msg)
+	}
+	return err
+}
+
+// publish writes one message on the current (or a new) connection and waits for PONG.
+func (p *NATSPublisher) publish(ctx context.Context, msg []byte) error {
+	if p.conn == nil {
+		if err := p.dial(ctx); err != nil { return err }
+	}
+	deadline := time.Now().Add(natsTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) { deadline = d }
+	_ = p.conn.SetDeadline(deadline)
+
+	_, err := p.conn.Write(msg)
+	if err == nil { err = p.awaitPong() }
+	if err != nil {
+		p.conn.Close()
+		p.conn, p.r = nil, nil
+		return fmt.Errorf("nats publish: %w", err)
+	}
+	return nil
+}
+
+// dial opens a connection: read the server's INFO, send CONNECT and wait for the first PONG,
+// which also surfaces authorization errors.
+func (p *NATSPublisher) dial(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: natsTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.addr)
+	if err != nil { return fmt.Errorf("nats dial: %w", err) }
+	_ = conn.SetDeadline(time.Now().Add(natsTimeout))
+	r := bufio.NewReader(conn)
+
+	info, err := r.ReadString('\n')
+	if err == nil && !strings.HasPrefix(info, "INFO ") {
+		err = fmt.Errorf("unexpected greeting %q", strings.TrimSpace(info))
+	}
+	if err == nil && strings.Contains(info, `"tls_required":true`) {
+		err = errors.New("server requires TLS, which this publisher doesn't speak")
+	}
+	if err == nil {
+		_, err = conn.Write(append(p.connect, "PING\r\n"...))
+	}
+	p.conn, p.r = conn, r
+	if err == nil { err = p.awaitPong() }
+	if err != nil {
+		conn.Close()
+		p.conn, p.r = nil, nil
+		return fmt.Errorf("nats connect: %w", err)
+	}
+	return nil
+}
+
+// awaitPong reads until the PONG answering our PING, answering the server's own PINGs on the way.
+func (p *NATSPublisher) awaitPong() error {
+	for {
+		line, err := p.r.ReadString('\n')
+		if err != nil { return err }
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := p.conn.Write([]byte("PONG\r\n")); err != nil { return err }
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New(strings.Trim(strings.TrimPrefix(line, "-ERR"), " '"))
+		}
+		// +OK and INFO updates need no answer
+	}
+}
+
+// Close drops the connection; a later Publish would dial again.
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil { return nil }
+	err := p.conn.Close()
+	p.conn, p.r = nil, nil
+	return err
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+func testEvent(before *api.Delivery) *DeliveryEvent {
+	id := "d1"
+	return &DeliveryEvent{
+		Id:         "00000000000000000001-abcdef12",
+		Type:       EventDeliveryAccepted,
+		DeliveryId: id,
+		Delivery:   api.Delivery{Id: &id, BusinessName: "Bakery", Status: api.DeliveryStatusAccepted},
+		Before:     before,
+		CreatedAt:  time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestEventEnvelopeJSON(t *testing.T) {
+	id := "d1"
+	posted := &api.Delivery{Id: &id, BusinessName: "Bakery", Status: api.DeliveryStatusPosted}
+
+	tests := []struct {
+		name         string
+		before       *api.Delivery
+		wantPrevious bool
+	}{
+		{"with the delivery before", posted, true},
+		{"a new delivery has no previous", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(NewEventEnvelope(testEvent(tt.before)))
+			if err != nil { t.Fatal(err) }
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil { t.Fatal(err) }
+
+			for key, want := range map[string]any{
+				"version": float64(EventEnvelopeVersion), "id": "00000000000000000001-abcdef12", "type": EventDeliveryAccepted,
+				"source": "courier-system", "deliveryId": "d1", "occurredAt": "2025-06-02T09:30:00Z",
+			} {
+				if got[key] != want { t.Errorf("%s = %#v, want %#v", key, got[key], want) }
+			}
+			data, _ := got["data"].(map[string]any)
+			delivery, _ := data["delivery"].(map[string]any)
+			if delivery["businessName"] != "Bakery" || delivery["status"] != "accepted" { t.Errorf("data.delivery = %v", delivery) }
+			previous, ok := data["previous"].(map[string]any)
+			if ok != tt.wantPrevious { t.Fatalf("data.previous = %v, want present %v", data["previous"], tt.wantPrevious) }
+			if ok && previous["status"] != "posted" { t.Errorf("data.previous.status = %v, want posted", previous["status"]) }
+
+			// a Go consumer decodes the envelope back
+			var env EventEnvelope
+			if err := json.Unmarshal(body, &env); err != nil { t.Fatal(err) }
+			if env.DeliveryId != "d1" || env.Data.Delivery.BusinessName != "Bakery" || (env.Data.Previous != nil) != tt.wantPrevious {
+				t.Errorf("decoded %+v", env)
+			}
+		})
+	}
+}
+
+func TestChannelBus(t *testing.T) {
+	ctx := context.Background()
+	bus := NewChannelBus()
+	first, second := bus.Subscribe(1), bus.Subscribe(1)
+
+	sink := NewEventBusSink(bus)
+	if err := sink.Deliver(ctx, testEvent(nil)); err != nil { t.Fatal(err) }
+	for i, ch := range []<-chan *EventEnvelope{first, second} {
+		env := <-ch
+		if env.Type != EventDeliveryAccepted || env.DeliveryId != "d1" || env.Version != EventEnvelopeVersion {
+			t.Errorf("subscriber %d got %+v", i, env)
+		}
+	}
+
+	// a full subscriber holds Publish until ctx gives up
+	if err := bus.Publish(ctx, NewEventEnvelope(testEvent(nil))); err != nil { t.Fatal(err) }
+	<-second
+	cancelled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := bus.Publish(cancelled, NewEventEnvelope(testEvent(nil))); err != context.DeadlineExceeded {
+		t.Errorf("Publish to a full subscriber = %v, want DeadlineExceeded", err)
+	}
+
+	if err := bus.Close(); err != nil { t.Fatal(err) }
+	if err := bus.Close(); err != nil { t.Errorf("second Close = %v", err) }
+	<-first
+	if _, open := <-first; open { t.Error("subscriber channel still open after Close") }
+	if _, open := <-bus.Subscribe(1); open { t.Error("Subscribe after Close returned an open channel") }
+	if err := bus.Publish(ctx, NewEventEnvelope(testEvent(nil))); err != ErrEventBusClosed {
+		t.Errorf("Publish after Close = %v, want ErrEventBusClosed", err)
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"google.golang.org/api/iterator"
+)
+
+// ExpiryService deals with posted deliveries no courier accepts. Each time one has sat for
+// `after` since it became visible or since the last step, it takes the next step: raise the
+// payment by the business's repricing step while that stays within their maxPayment, then
+// remind the business once, then expire the delivery. Every step is appended to the
+// delivery's autoActions and emitted as a delivery event in the same transaction, so the
+// notification sink tells the business.
+type ExpiryService struct {
+	firestore *db.FirestoreClient
+	after     time.Duration // time without acceptance before each step (env EXPIRY_AFTER)
+}
+
+// NewExpiryService wires Firestore into the expiry job.
+// called once from main.go at startup
+func NewExpiryService(fs *db.FirestoreClient) *ExpiryService {
+	return &ExpiryService{
+		firestore: fs,
+		after:     config.Duration("EXPIRY_AFTER", 30*time.Minute),
+	}
+}
+
+var ErrInvalidRepricing = errors.New("repricing needs a positive step and maxPayment when enabled")
+
+// idleSince is when d last changed for couriers: posted, released to them, or its last step.
+func idleSince(d *api.Delivery) time.Time {
+	var since time.Time
+	if d.CreatedAt != nil { since = *d.CreatedAt }
+	if d.ReleaseAt != nil && d.ReleaseAt.After(since) { since = *d.ReleaseAt }
+	if d.AutoActions != nil && len(*d.AutoActions) > 0 {
+		last := (*d.AutoActions)[len(*d.AutoActions)-1].At
+		if last.After(since) { since = last }
+	}
+	return since
+}
+
+// due reports whether d is a posted delivery that waited `after` for a courier.
+func (s *ExpiryService) due(d *api.Delivery, now time.Time) bool {
+	return d.Status == StatusPosted && d.AssignedTo == nil && !now.Before(idleSince(d).Add(s.after))
+}
+
+func hasAutoAction(d *api.Delivery, kind api.DeliveryAutoActionKind) bool {
+	if d.AutoActions == nil { return false }
+	for _, a := range *d.AutoActions {
+		if a.Kind == kind { return true }
+	}
+	return false
+}
+
+// nextStep decides what to do with d, given the business's repricing consent.
+func nextStep(d *api.Delivery, repricing *api.BusinessRepricing) api.DeliveryAutoActionKind {
+	if repricing != nil && repricing.Enabled && repricing.Step != nil && repricing.MaxPayment != nil &&
+		d.Payment+*repricing.Step <= *repricing.MaxPayment+1e-9 {
+		return api.DeliveryAutoActionKindRepriced
+	}
+	if !hasAutoAction(d, api.DeliveryAutoActionKindReminded) { return api.DeliveryAutoActionKindReminded }
+	return api.DeliveryAutoActionKindExpired
+}
+
+// Sweep takes the next step on every posted delivery that is due and returns how many it
+// changed. One delivery failing doesn't hold up the others.
+func (s *ExpiryService) Sweep(ctx context.Context, now time.Time) (int, error) {
+	iter := s.firestore.Collection("deliveries").Where("status", "==", StatusPosted).Documents(ctx)
+	defer iter.Stop()
+
+	changed := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return changed, err }
+
+		var d api.Delivery
+		if err := doc.DataTo(&d); err != nil || !s.due(&d, now) { continue }
+		ok, err := s.step(ctx, doc.Ref, now)
+		if err != nil {
+			log.Printf("expiry of delivery %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		if ok { changed++ }
+	}
+	return changed, nil
+}
+
+// step re-checks the delivery in a transaction, so one a courier accepted meanwhile is
+// left alone, and applies the next step; false when there was nothing to do.
+func (s *ExpiryService) step(ctx context.Context, ref *firestore.DocumentRef, now time.Time) (bool, error) {
+	changed := false
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		changed = false
+		snap, err := tx.Get(ref)
+		if err != nil { return err }
+		var d api.Delivery
+		err = snap.DataTo(&d)
+		if err != nil { return err }
+		if !s.due(&d, now) { return nil }
+
+		var repricing *api.BusinessRepricing
+		if d.BusinessId != nil {
+			bizSnap, err := tx.Get(s.firestore.Collection("users").Doc(*d.BusinessId))
+			if err != nil { return err }
+			var business api.BusinessUser
+			if err := bizSnap.DataTo(&business); err != nil { return err }
+			repricing = business.Repricing
+		}
+
+		before := d
+		eventType := EventDeliveryUnaccepted
+		kind := nextStep(&d, repricing)
+		switch kind {
+		case api.DeliveryAutoActionKindRepriced:
+			d.Payment = roundCents(d.Payment + *repricing.Step)
+			eventType = EventDeliveryRepriced
+		case api.DeliveryAutoActionKindExpired:
+			if err := isValidTransition(string(d.Status), StatusExpired); err != nil { return err }
+			d.Status = api.DeliveryStatusExpired
+			d.ExpiredAt = &now
+			eventType = EventDeliveryStatus
+		}
+		var actions []api.DeliveryAutoAction
+		if d.AutoActions != nil { actions = append(actions, *d.AutoActions...) }
+		actions = append(actions, api.DeliveryAutoAction{Kind: kind, At: now, Payment: d.Payment})
+		d.AutoActions = &actions
+
+		err = tx.Set(ref, d)
+		if err != nil { return err }
+		changed = true
+		return addDeliveryEvent(tx, s.firestore.Client, eventType, ref.ID, &before, d)
+	})
+	return changed, err
+}
+
+// Run sweeps now and then every `every`, until ctx is cancelled.
+func (s *ExpiryService) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		changed, err := s.Sweep(ctx, time.Now().UTC())
+		if err != nil {
+			log.Printf("expiry: %v", err)
+		} else if changed > 0 {
+			log.Printf("expiry: %d unaccepted deliveries repriced, reminded or expired", changed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GET /businesses/me/repricing
+func (u *UserService) GetRepricing(ctx context.Context, businessUID string) (*api.BusinessRepricing, error) {
+	business, err := u.GetBusinessInfo(ctx, businessUID)
+	if err != nil { return nil, err }
+	if business.Repricing == nil { return &api.BusinessRepricing{}, nil }
+	return business.Repricing, nil
+}
+
+// PUT /businesses/me/repricing
+// SetRepricing stores the business's consent to raising unaccepted deliveries' payments.
+// It applies to their posted deliveries from the next step on.
+func (u *UserService) SetRepricing(ctx context.Context, businessUID string, req *api.BusinessRepricing) (*api.BusinessRepricing, error) {
+	if req.Step != nil && *req.Step <= 0 { return nil, ErrInvalidRepricing }
+	if req.MaxPayment != nil && *req.MaxPayment <= 0 { return nil, ErrInvalidRepricing }
+	if req.Enabled && (req.Step == nil || req.MaxPayment == nil) { return nil, ErrInvalidRepricing }
+
+	// fails on a missing or non-business user rather than creating one
+	if _, err := u.GetBusinessInfo(ctx, businessUID); err != nil { return nil, err }
+	_, err := u.firestore.Collection("users").Doc(businessUID).Update(ctx, []firestore.Update{{Path: "repricing", Value: req}})
+	if err != nil { return nil, err }
+	return req, nil
+}
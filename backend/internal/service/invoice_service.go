@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InvoiceService issues one invoice per business per calendar month for its delivered deliveries.
+// Invoices live in /invoices with a deterministic doc ID ({businessId}_{YYYY-MM}), so a month
+// can be generated any number of times (scheduler, admin re-run) and is only ever invoiced once.
+// Numbers come from a per-year counter in /counters, taken inside the same transaction.
+type InvoiceService struct {
+	firestore  *db.FirestoreClient
+	deliveries *DeliveryService
+	feeRate    float64 // platform fee as a fraction of payment (env INVOICE_FEE_RATE)
+	taxRate    float64 // applied to payment + fee (env INVOICE_TAX_RATE)
+}
+
+// NewInvoiceService wires Firestore and the delivery domain into invoicing.
+// called once from main.go at startup
+func NewInvoiceService(fs *db.FirestoreClient, deliveries *DeliveryService) *InvoiceService {
+	return &InvoiceService{
+		firestore:  fs,
+		deliveries: deliveries,
+		feeRate:    config.Float("INVOICE_FEE_RATE", 0.10),
+		taxRate:    config.Float("INVOICE_TAX_RATE", 0.17),
+	}
+}
+
+var ErrInvoiceNotFound = errors.New("invoice not found")
+
+var ErrMonthNotSettled = errors.New("month can't be invoiced before the tip window after it has closed")
+
+// Invoice download formats.
+const (
+	InvoicePDF  = "pdf"
+	InvoiceJSON = "json"
+)
+
+func invoiceDocID(businessID string, month time.Time) string {
+	return businessID + "_" + month.Format("2006-01")
+}
+
+// POST /invoices/generate
+// GenerateInvoices invoices the month starting at `month` (UTC) for every business.
+// Businesses without delivered deliveries in that month, and months already invoiced, are skipped.
+// Returns only the invoices created by this run. A month is only invoiced once the tip window
+// after its last day has passed, since a month once invoiced is skipped and a later tip lost.
+func (s *InvoiceService) GenerateInvoices(ctx context.Context, month time.Time) ([]*api.Invoice, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	now := time.Now().UTC()
+	if end.After(now) {
+		return nil, ErrInvalidRange // the month isn't over yet
+	}
+	if end.Add(s.deliveries.tipWindow).After(now) { return nil, ErrMonthNotSettled }
+
+	iter := s.firestore.Collection("users").Where("role", "==", "business").Documents(ctx)
+	defer iter.Stop()
+
+	created := []*api.Invoice{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var business api.BusinessUser
+		if err := doc.DataTo(&business); err != nil { continue }
+		business.Id = doc.Ref.ID
+
+		inv, err := s.invoiceBusiness(ctx, &business, start, end)
+		if err != nil { return created, err }
+		if inv != nil {
+			created = append(created, inv)
+		}
+	}
+	return created, nil
+}
+
+// invoiceBusiness creates the business's invoice for [start, end); nil if there is nothing to invoice.
+func (s *InvoiceService) invoiceBusiness(ctx context.Context, business *api.BusinessUser, start, end time.Time) (*api.Invoice, error) {
+	invoiceRef := s.firestore.Collection("invoices").Doc(invoiceDocID(business.Id, start))
+
+	// cheap check first so re-runs don't list every business's deliveries again
+	_, err := invoiceRef.Get(ctx)
+	if err == nil { return nil, nil }
+	if status.Code(err) != codes.NotFound { return nil, err }
+
+	// same data a business sees in ListDeliveries, narrowed to what was delivered this month
+	delivered := string(api.DeliveryStatusDelivered)
+	list, _, err := s.deliveries.ListDeliveries(ctx, ListFilter{
+		Role:       "business",
+		BusinessID: business.Id,
+		Status:     &delivered,
+	})
+	if err != nil { return nil, err }
+
+	inv := api.Invoice{
+		BusinessId:   business.Id,
+		BusinessName: business.BusinessName,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		Currency:     payoutCurrency,
+		FeeRate:      s.feeRate,
+		TaxRate:      s.taxRate,
+		Lines:        []api.InvoiceLine{},
+	}
+	// ListDeliveries is newest first; invoices read oldest first
+	for i := len(list) - 1; i >= 0; i-- {
+		d := list[i]
+		// deliveries completed before deliveredAt existed fall back to their creation time
+		at := d.DeliveredAt
+		if at == nil { at = d.CreatedAt }
+		if at == nil || at.Before(start) || !at.Before(end) { continue }
+
+		fee := roundCents(d.Payment * s.feeRate)
+		tax := roundCents((d.Payment + fee) * s.taxRate)
+		inv.Lines = append(inv.Lines, api.InvoiceLine{
+			DeliveryId:         *d.Id,
+			Item:               d.Item,
+			BusinessAddress:    d.BusinessAddress,
+			DestinationAddress: d.DestinationAddress,
+			DeliveredAt:        *at,
+			Payment:            d.Payment,
+			Fee:                fee,
+			Tax:                tax,
+			Tip:                d.Tip,
+			Total:              roundCents(d.Payment + fee + tax + d.Tip),
+		})
+		inv.Subtotal += d.Payment
+		inv.Fees += fee
+		inv.Tax += tax
+		inv.Tips += d.Tip
+	}
+	if len(inv.Lines) == 0 { return nil, nil }
+
+	inv.Subtotal = roundCents(inv.Subtotal)
+	inv.Fees = roundCents(inv.Fees)
+	inv.Tax = roundCents(inv.Tax)
+	inv.Tips = roundCents(inv.Tips)
+	inv.Total = roundCents(inv.Subtotal + inv.Fees + inv.Tax + inv.Tips)
+
+	created := false
+	err = s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		created = false
+		now := time.Now().UTC()
+		counterRef := s.firestore.Collection("counters").Doc(fmt.Sprintf("invoices-%d", now.Year()))
+
+		// another run may have invoiced this month since the check above
+		_, err := tx.Get(invoiceRef)
+		if err == nil { return nil }
+		if status.Code(err) != codes.NotFound { return err }
+
+		next := int64(1)
+		counterSnap, err := tx.Get(counterRef)
+		if err != nil && status.Code(err) != codes.NotFound { return err }
+		if err == nil {
+			if n, ok := counterSnap.Data()["next"].(int64); ok { next = n }
+		}
+
+		id := invoiceRef.ID
+		inv.Id = &id
+		inv.Number = fmt.Sprintf("INV-%d-%06d", now.Year(), next)
+		inv.CreatedAt = &now
+
+		err = tx.Set(counterRef, map[string]interface{}{"next": next + 1})
+		if err != nil { return err }
+		created = true
+		return tx.Create(invoiceRef, inv)
+	})
+	if err != nil || !created { return nil, err }
+	return &inv, nil
+}
+
+// GET /businesses/me/invoices
+// ListInvoices returns a business's invoices, newest first.
+func (s *InvoiceService) ListInvoices(ctx context.Context, businessUID string) ([]*api.Invoice, error) {
+	iter := s.firestore.Collection("invoices").
+		Where("businessId", "==", businessUID).
+		OrderBy("periodStart", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	result := []*api.Invoice{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var inv api.Invoice
+		if err := doc.DataTo(&inv); err != nil { continue }
+		id := doc.Ref.ID
+		inv.Id = &id
+		result = append(result, &inv)
+	}
+	return result, nil
+}
+
+// GET /businesses/me/invoices/{id}
+// RenderInvoice returns one of the business's invoices as a PDF or JSON file.
+// Someone else's invoice is reported as not found.
+// Returns the file content, its content type and a suggested file name.
+func (s *InvoiceService) RenderInvoice(ctx context.Context, invoiceID, businessUID, format string) ([]byte, string, string, error) {
+	snap, err := s.firestore.Collection("invoices").Doc(invoiceID).Get(ctx)
+	if status.Code(err) == codes.NotFound { return nil, "", "", ErrInvoiceNotFound }
+	if err != nil { return nil, "", "", err }
+
+	var inv api.Invoice
+	err = snap.DataTo(&inv)
+	if err != nil { return nil, "", "", err }
+	if inv.BusinessId != businessUID { return nil, "", "", ErrInvoiceNotFound }
+	inv.Id = &invoiceID
+
+	switch format {
+	case "", InvoicePDF:
+		return renderTextPDF(invoiceLines(&inv)), "application/pdf", inv.Number + ".pdf", nil
+
+	case InvoiceJSON:
+		out, err := json.MarshalIndent(inv, "", "  ")
+		if err != nil { return nil, "", "", err }
+		return out, "application/json", inv.Number + ".json", nil
+	}
+	return nil, "", "", ErrUnsupportedFormat
+}
+
+// invoiceLines lays out the PDF invoice as fixed-width text.
+func invoiceLines(inv *api.Invoice) []string {
+	lines := []string{
+		"INVOICE " + inv.Number,
+		"",
+		"Billed to: " + inv.BusinessName,
+		"Period:    " + inv.PeriodStart.Format("2006-01-02") + " - " + inv.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+		"Issued:    " + inv.CreatedAt.Format("2006-01-02"),
+		"Currency:  " + inv.Currency,
+		"",
+		fmt.Sprintf("%-10s %-28s %8s %7s %7s %7s %9s", "Date", "Item / route", "Payment", "Fee", "Tax", "Tip", "Total"),
+		fmt.Sprintf("%-10s %-28s %8s %7s %7s %7s %9s", "----", "------------", "-------", "---", "---", "---", "-----"),
+	}
+	for _, l := range inv.Lines {
+		item := l.Item
+		if len(item) > 28 { item = item[:25] + "..." }
+		route := l.BusinessAddress + " -> " + l.DestinationAddress
+		if len(route) > 28 { route = route[:25] + "..." }
+		lines = append(lines,
+			fmt.Sprintf("%-10s %-28s %8.2f %7.2f %7.2f %7.2f %9.2f", l.DeliveredAt.Format("2006-01-02"), item, l.Payment, l.Fee, l.Tax, l.Tip, l.Total),
+			fmt.Sprintf("%-10s %-28s", "", route),
+		)
+	}
+	lines = append(lines,
+		"",
+		fmt.Sprintf("%-40s %10.2f", "Deliveries", inv.Subtotal),
+		fmt.Sprintf("%-40s %10.2f", fmt.Sprintf("Platform fees (%.0f%%)", inv.FeeRate*100), inv.Fees),
+		fmt.Sprintf("%-40s %10.2f", fmt.Sprintf("Tax (%.0f%% of deliveries + fees)", inv.TaxRate*100), inv.Tax),
+		fmt.Sprintf("%-40s %10.2f", "Courier tips", inv.Tips),
+		fmt.Sprintf("%-40s %10.2f", "Total due", inv.Total),
+	)
+	return lines
+}
+
+// RunInvoiceScheduler generates last month's invoices now and then every `every`,
+// until ctx is cancelled. A month is only invoiced once the tip window after its
+// last day has passed, so late tips still land on the right invoice.
+func (s *InvoiceService) RunInvoiceScheduler(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		settled := time.Now().UTC().Add(-s.deliveries.tipWindow)
+		month := time.Date(settled.Year(), settled.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+		created, err := s.GenerateInvoices(ctx, month)
+		if err != nil {
+			log.Printf("invoices %s: %v", month.Format("2006-01"), err)
+		} else if len(created) > 0 {
+			log.Printf("invoices %s: %d created", month.Format("2006-01"), len(created))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+)
+
+// Ledger entry kinds — every change to a courier's balance is recorded as one of these.
+const (
+	LedgerDelivery   = "delivery"
+	LedgerTip        = "tip"
+	LedgerAdjustment = "adjustment"
+	LedgerPayout     = "payout"
+)
+
+// LedgerEntry is one movement of a courier's balance, stored in /ledger/{id}.
+// Amount is signed: credits are positive, payouts are negative.
+type LedgerEntry struct {
+	Id         string    `firestore:"id"`
+	CourierId  string    `firestore:"courierId"`
+	Kind       string    `firestore:"kind"`
+	Amount     float64   `firestore:"amount"`
+	DeliveryId *string   `firestore:"deliveryId"`
+	PayoutId   *string   `firestore:"payoutId"`
+	Note       string    `firestore:"note"`
+	CreatedAt  time.Time `firestore:"createdAt"`
+}
+
+// addLedgerEntry writes a ledger entry inside the caller's transaction,
+// so the balance change and its record commit (or fail) together.
+func addLedgerEntry(tx *firestore.Transaction, fs *firestore.Client, e LedgerEntry) error {
+	e.Id = uuid.NewString()
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	return tx.Create(fs.Collection("ledger").Doc(e.Id), e)
+}
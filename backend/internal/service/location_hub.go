@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CourierLocation is a courier's last reported position, as the courier app writes it
+// to couriers/{id}/location/current.
+type CourierLocation struct {
+	CourierId string    `firestore:"-" json:"courierId"`
+	Lat       float64   `firestore:"lat" json:"lat"`
+	Lng       float64   `firestore:"lng" json:"lng"`
+	UpdatedAt time.Time `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// lastCourierLocation reads couriers/{uid}/location/current; nil when the courier never reported one.
+func lastCourierLocation(ctx context.Context, fs *db.FirestoreClient, courierUID string) (*CourierLocation, error) {
+	doc, err := fs.Collection("couriers").Doc(courierUID).Collection("location").Doc("current").Get(ctx)
+	if status.Code(err) == codes.NotFound { return nil, nil }
+	if err != nil { return nil, err }
+
+	var loc CourierLocation
+	if err := doc.DataTo(&loc); err != nil { return nil, nil }
+	loc.CourierId = courierUID
+	return &loc, nil
+}
+
+// LocationHub fans courier positions out to live subscribers (the WebSocket maps).
+// One Firestore listener per server watches every couriers/{id}/location/current doc;
+// changes are coalesced per courier and flushed at most once per throttle interval,
+// so a courier reporting every second doesn't cost every map a message per second.
+type LocationHub struct {
+	firestore *db.FirestoreClient
+	throttle  time.Duration // env LOCATION_WS_THROTTLE
+
+	mu      sync.Mutex
+	latest  map[string]CourierLocation // last known position per courier
+	pending map[string]CourierLocation // changed since the last flush
+	subs    map[*LocationSubscription]struct{}
+}
+
+// NewLocationHub wires Firestore into the live location fan-out.
+// called once from main.go at startup; Run must be started for updates to flow
+func NewLocationHub(fs *db.FirestoreClient) *LocationHub {
+	return &LocationHub{
+		firestore: fs,
+		throttle:  config.Duration("LOCATION_WS_THROTTLE", 2*time.Second),
+		latest:    map[string]CourierLocation{},
+		pending:   map[string]CourierLocation{},
+		subs:      map[*LocationSubscription]struct{}{},
+	}
+}
+
+// LocationSubscription receives the positions of the couriers it is allowed to see.
+// Updates queue up per courier (newest wins) until the owner calls Drain after Notify fires.
+type LocationSubscription struct {
+	Notify <-chan struct{}
+	notify chan struct{}
+
+	mu      sync.Mutex
+	all     bool            // admin: every courier
+	allowed map[string]bool // business: couriers on its active deliveries
+	pending map[string]CourierLocation
+}
+
+// Subscribe registers a subscriber. all=true sees every courier (admin); otherwise
+// nothing until SetCouriers names the couriers it may see.
+// The current position of every visible courier is queued right away.
+func (h *LocationHub) Subscribe(all bool) *LocationSubscription {
+	notify := make(chan struct{}, 1)
+	sub := &LocationSubscription{
+		Notify:  notify,
+		notify:  notify,
+		all:     all,
+		allowed: map[string]bool{},
+		pending: map[string]CourierLocation{},
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[sub] = struct{}{}
+	if all {
+		sub.push(h.latest)
+	}
+	return sub
+}
+
+// Unsubscribe stops deliveries to sub.
+func (h *LocationHub) Unsubscribe(sub *LocationSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, sub)
+}
+
+// SetCouriers replaces the couriers a (business) subscriber may see and
+// queues the last known position of the ones that just became visible.
+func (h *LocationHub) SetCouriers(sub *LocationSubscription, courierIDs []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub.mu.Lock()
+	allowed := make(map[string]bool, len(courierIDs))
+	added := map[string]CourierLocation{}
+	for _, id := range courierIDs {
+		allowed[id] = true
+		if loc, ok := h.latest[id]; ok && !sub.allowed[id] {
+			added[id] = loc
+		}
+	}
+	sub.allowed = allowed
+	sub.mu.Unlock()
+
+	sub.push(added)
+}
+
+// push queues the visible part of updates and wakes the owner.
+func (sub *LocationSubscription) push(updates map[string]CourierLocation) {
+	sub.mu.Lock()
+	queued := false
+	for id, loc := range updates {
+		if sub.all || sub.allowed[id] {
+			sub.pending[id] = loc
+			queued = true
+		}
+	}
+	sub.mu.Unlock()
+
+	if queued {
+		select {
+		case sub.notify <- struct{}{}:
+		default: // already signalled, the owner will drain everything
+		}
+	}
+}
+
+// Drain returns and clears the queued positions.
+func (sub *LocationSubscription) Drain() []CourierLocation {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	out := make([]CourierLocation, 0, len(sub.pending))
+	for _, loc := range sub.pending {
+		out = append(out, loc)
+	}
+	sub.pending = map[string]CourierLocation{}
+	return out
+}
+
+// ActiveCourierIDs returns the couriers currently carrying the business's deliveries
+// (accepted or picked up) — the only couriers a business may follow live.
+func (h *LocationHub) ActiveCourierIDs(ctx context.Context, businessUID string) ([]string, error) {
+	iter := h.firestore.Collection("deliveries").
+		Where("businessId", "==", businessUID).
+		Where("status", "in", []string{StatusAccepted, StatusPickedUp}).
+		Documents(ctx)
+	defer iter.Stop()
+
+	seen := map[string]bool{}
+	var ids []string
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		courierID, _ := doc.Data()["assignedTo"].(string)
+		if courierID != "" && !seen[courierID] {
+			seen[courierID] = true
+			ids = append(ids, courierID)
+		}
+	}
+	return ids, nil
+}
+
+// Run listens to courier locations and flushes them to subscribers until ctx is cancelled.
+// A broken listener is restarted after a short pause.
+func (h *LocationHub) Run(ctx context.Context) {
+	go func() {
+		for ctx.Err() == nil {
+			if err := h.listen(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("location listener: %v", err)
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(h.throttle)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.flush()
+		}
+	}
+}
+
+// listen records every change of a couriers/{id}/location/current doc as pending.
+func (h *LocationHub) listen(ctx context.Context) error {
+	snaps := h.firestore.CollectionGroup("location").Snapshots(ctx)
+	defer snaps.Stop()
+
+	for {
+		qs, err := snaps.Next()
+		if err != nil { return err }
+
+		h.mu.Lock()
+		for _, ch := range qs.Changes {
+			if ch.Kind == firestore.DocumentRemoved || ch.Doc.Ref.ID != "current" { continue }
+			var loc CourierLocation
+			if err := ch.Doc.DataTo(&loc); err != nil { continue }
+			loc.CourierId = ch.Doc.Ref.Parent.Parent.ID
+			h.latest[loc.CourierId] = loc
+			h.pending[loc.CourierId] = loc
+		}
+		h.mu.Unlock()
+	}
+}
+
+// flush hands the positions changed since the last tick to every subscriber.
+func (h *LocationHub) flush() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.pending) == 0 { return }
+	for sub := range h.subs {
+		sub.push(h.pending)
+	}
+	h.pending = map[string]CourierLocation{}
+}
@@ -0,0 +1,480 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NotificationService tells people about the deliveries they care about:
+// couriers about new jobs near their last position, businesses about their deliveries'
+// progress, and a delivery's recipient, when the business gave their contacts, that it is
+// on its way and when it arrived. It is an outbox sink, so every delivery event is considered once.
+//
+// Preferences live in /notificationPreferences/{uid}. Each (event, recipient) pair gets a
+// /notifications/{eventId}_{uid} record, taken together with the recipient's rate-limit
+// window in one transaction, so a retried event doesn't count twice against the limit.
+// Messages aren't sent from the sink: each is queued once as /notificationSends/{recordId}_{channel}
+// and a worker sends it, retrying with exponential backoff until maxAttempts like webhook calls.
+type NotificationService struct {
+	firestore   *db.FirestoreClient
+	email       Notifier
+	sms         Notifier
+	templates   map[api.NotificationEvent]notificationTemplate
+	rateLimit   int           // env NOTIFY_RATE_LIMIT, notifications per recipient per window
+	rateWindow  time.Duration // env NOTIFY_RATE_WINDOW
+	jobRadiusKm float64       // env NOTIFY_JOB_RADIUS_KM
+	locationAge time.Duration // env NOTIFY_LOCATION_MAX_AGE, older courier positions are ignored
+	maxAttempts int           // env NOTIFY_MAX_ATTEMPTS
+	backoffBase time.Duration // env NOTIFY_BACKOFF_BASE, doubled after every failure
+	trackingURL string        // env TRACKING_URL, public tracking page the token is appended to
+}
+
+// NewNotificationService wires Firestore, the channel notifiers and the templates
+// (built-ins, overridable from NOTIFY_TEMPLATE_DIR) into the notification domain.
+// called once from main.go at startup
+func NewNotificationService(fs *db.FirestoreClient, email, sms Notifier) (*NotificationService, error) {
+	templates, err := loadNotificationTemplates(config.String("NOTIFY_TEMPLATE_DIR", ""))
+	if err != nil { return nil, err }
+	return &NotificationService{
+		firestore:   fs,
+		email:       email,
+		sms:         sms,
+		templates:   templates,
+		rateLimit:   config.Int("NOTIFY_RATE_LIMIT", 20),
+		rateWindow:  config.Duration("NOTIFY_RATE_WINDOW", time.Hour),
+		jobRadiusKm: config.Float("NOTIFY_JOB_RADIUS_KM", 5),
+		locationAge: config.Duration("NOTIFY_LOCATION_MAX_AGE", 30*time.Minute),
+		maxAttempts: config.Int("NOTIFY_MAX_ATTEMPTS", 6),
+		backoffBase: config.Duration("NOTIFY_BACKOFF_BASE", 30*time.Second),
+		trackingURL: config.String("TRACKING_URL", ""),
+	}, nil
+}
+
+var ErrInvalidPhone = errors.New("sms needs a phone number in E.164 format, e.g. +4915112345678")
+
+var ErrInvalidNotificationEvent = errors.New("unknown notification event")
+
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+const (
+	notificationChannelEmail = "email"
+	notificationChannelSMS   = "sms"
+
+	notificationAllowed     = "allowed"
+	notificationRateLimited = "rate_limited"
+
+	notificationSendPending = "pending"
+	notificationSendSent    = "sent"
+	notificationSendDead    = "dead"
+
+	notificationLease      = 2 * time.Minute // a claimed send is retried by anyone after this
+	notificationBatchSize  = 20
+	notificationPollPeriod = 5 * time.Second
+)
+
+// defaultNotificationPreferences applies until a user saves their own: email only.
+func defaultNotificationPreferences() *api.NotificationPreferences {
+	return &api.NotificationPreferences{Email: true, MutedEvents: []api.NotificationEvent{}}
+}
+
+// GET /me/notification-preferences
+func (s *NotificationService) GetPreferences(ctx context.Context, uid string) (*api.NotificationPreferences, error) {
+	doc, err := s.firestore.Collection("notificationPreferences").Doc(uid).Get(ctx)
+	if status.Code(err) == codes.NotFound { return defaultNotificationPreferences(), nil }
+	if err != nil { return nil, err }
+
+	var prefs api.NotificationPreferences
+	err = doc.DataTo(&prefs)
+	if err != nil { return nil, err }
+	if prefs.MutedEvents == nil { prefs.MutedEvents = []api.NotificationEvent{} }
+	return &prefs, nil
+}
+
+// PUT /me/notification-preferences
+func (s *NotificationService) UpdatePreferences(ctx context.Context, uid string, prefs *api.NotificationPreferences) (*api.NotificationPreferences, error) {
+	if prefs.Phone != nil && *prefs.Phone == "" { prefs.Phone = nil }
+	if prefs.Phone != nil && !e164.MatchString(*prefs.Phone) { return nil, ErrInvalidPhone }
+	if prefs.Sms && prefs.Phone == nil { return nil, ErrInvalidPhone }
+	if prefs.MutedEvents == nil { prefs.MutedEvents = []api.NotificationEvent{} }
+	for _, e := range prefs.MutedEvents {
+		if _, ok := s.templates[e]; !ok || recipientEvents[e] { return nil, ErrInvalidNotificationEvent }
+	}
+
+	_, err := s.firestore.Collection("notificationPreferences").Doc(uid).Set(ctx, prefs)
+	if err != nil { return nil, err }
+	return prefs, nil
+}
+
+// Name identifies notifications as an outbox sink.
+func (s *NotificationService) Name() string { return "notifications" }
+
+// Deliver is the outbox sink: it queues the messages for everyone concerned by one delivery
+// event. Sending them is up to Run, so a channel that is down doesn't hold up the outbox.
+// The recipient's messages and the account holders' are queued independently, so one side
+// failing doesn't keep the other from being queued; the retry only adds what is missing.
+func (s *NotificationService) Deliver(ctx context.Context, e *DeliveryEvent) error {
+	err := s.notifyAccounts(ctx, e)
+	d := e.Delivery
+	if e.Type == EventDeliveryStatus && (d.Status == StatusPickedUp || d.Status == StatusDelivered) {
+		err = errors.Join(err, s.notifyRecipient(ctx, e))
+	}
+	return err
+}
+
+// notifyAccounts queues the messages for the couriers and the business concerned by e.
+func (s *NotificationService) notifyAccounts(ctx context.Context, e *DeliveryEvent) error {
+	d := e.Delivery
+	switch {
+	case jobPosted(e):
+		// a scheduled delivery is announced when it is released, not when it is created
+		couriers, err := s.couriersNear(ctx, d.BusinessLocation, s.jobRadiusKm)
+		if err != nil { return err }
+		for _, c := range couriers {
+			err = s.notify(ctx, e, c.CourierId, api.NotificationEventJobPosted, c.DistanceKm)
+			if err != nil { return err }
+		}
+		return nil
+
+	case d.BusinessId == nil:
+		return nil
+	case e.Type == EventDeliveryAccepted:
+		return s.notify(ctx, e, *d.BusinessId, api.NotificationEventDeliveryAccepted, 0)
+	case e.Type == EventDeliveryStatus && d.Status == StatusPickedUp:
+		return s.notify(ctx, e, *d.BusinessId, api.NotificationEventDeliveryPickedUp, 0)
+	case e.Type == EventDeliveryStatus && d.Status == StatusDelivered:
+		return s.notify(ctx, e, *d.BusinessId, api.NotificationEventDeliveryDelivered, 0)
+	case e.Type == EventDeliveryRepriced:
+		return s.notify(ctx, e, *d.BusinessId, api.NotificationEventDeliveryRepriced, 0)
+	case e.Type == EventDeliveryUnaccepted:
+		return s.notify(ctx, e, *d.BusinessId, api.NotificationEventDeliveryUnaccepted, 0)
+	case e.Type == EventDeliveryStatus && d.Status == StatusExpired:
+		return s.notify(ctx, e, *d.BusinessId, api.NotificationEventDeliveryExpired, 0)
+	case e.Type == EventDeliveryStatus && d.Status == StatusFailed:
+		return s.notify(ctx, e, *d.BusinessId, api.NotificationEventDeliveryFailed, 0)
+	case e.Type == EventDeliveryParcelMissing:
+		return s.notify(ctx, e, *d.BusinessId, api.NotificationEventDeliveryParcelMissing, 0)
+	}
+	return nil
+}
+
+// nearbyCourier is a courier whose last position is within reach of a point.
+type nearbyCourier struct {
+	CourierId  string
+	DistanceKm float64
+}
+
+// couriersNear returns the couriers whose last position, reported within locationAge,
+// lies within radiusKm of p.
+func (s *NotificationService) couriersNear(ctx context.Context, p api.GeoPoint, radiusKm float64) ([]nearbyCourier, error) {
+	iter := s.firestore.CollectionGroup("location").
+		Where("updatedAt", ">=", time.Now().UTC().Add(-s.locationAge)).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var out []nearbyCourier
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+		if doc.Ref.ID != "current" { continue }
+
+		var loc CourierLocation
+		if err := doc.DataTo(&loc); err != nil { continue }
+		dist := geoDistanceKm(p.Lat, p.Lng, loc.Lat, loc.Lng)
+		if dist <= radiusKm {
+			out = append(out, nearbyCourier{CourierId: doc.Ref.Parent.Parent.ID, DistanceKm: dist})
+		}
+	}
+	return out, nil
+}
+
+// notificationRecord is /notifications/{eventId}_{uid}.
+type notificationRecord struct {
+	UserId     string    `firestore:"userId"` // or recipientKey(address) for a delivery's recipient
+	Event      string    `firestore:"event"`
+	DeliveryId string    `firestore:"deliveryId"`
+	Status     string    `firestore:"status"`  // allowed | rate_limited
+	SentVia    []string  `firestore:"sentVia"` // channels sent so far
+	CreatedAt  time.Time `firestore:"createdAt"`
+}
+
+// notificationWindow is /notificationLimits/{uid}: a fixed rate-limit window.
+type notificationWindow struct {
+	WindowStart time.Time `firestore:"windowStart"`
+	Count       int       `firestore:"count"`
+}
+
+// notify queues event for uid on every channel they enabled, unless muted or over their limit.
+func (s *NotificationService) notify(ctx context.Context, e *DeliveryEvent, uid string, event api.NotificationEvent, distanceKm float64) error {
+	prefs, err := s.GetPreferences(ctx, uid)
+	if err != nil { return err }
+	for _, m := range prefs.MutedEvents {
+		if m == event { return nil }
+	}
+	if !prefs.Email && !prefs.Sms { return nil }
+
+	email := ""
+	if prefs.Email {
+		email, err = s.userEmail(ctx, uid)
+		if err != nil { return err }
+	}
+	phone := ""
+	if prefs.Sms && prefs.Phone != nil { phone = *prefs.Phone }
+	return s.queue(ctx, e, uid, event, email, phone, distanceKm)
+}
+
+// notifyRecipient queues the picked-up or delivered message for the person a delivery is
+// addressed to, on whichever of recipientEmail and recipientPhone the business gave. They
+// have no account and no preferences: giving their contacts is the business opting them in.
+// Their rate limit is kept per address, so no business can flood a stranger.
+func (s *NotificationService) notifyRecipient(ctx context.Context, e *DeliveryEvent) error {
+	d := e.Delivery
+	event := notificationRecipientOnTheWay
+	if d.Status == StatusDelivered { event = notificationRecipientDelivered }
+	var err error
+	if d.RecipientEmail != nil {
+		err = s.queue(ctx, e, recipientKey(*d.RecipientEmail), event, *d.RecipientEmail, "", 0)
+	}
+	if d.RecipientPhone != nil {
+		err = errors.Join(err, s.queue(ctx, e, recipientKey(*d.RecipientPhone), event, "", *d.RecipientPhone, 0))
+	}
+	return err
+}
+
+// recipientKey is what a recipient's records and rate limit are kept under: a hash of the
+// address, since an address may hold characters, like /, that a document id can't.
+// Email addresses are compared case-insensitively.
+func recipientKey(addr string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(addr))))
+	return "recipient_" + hex.EncodeToString(sum[:])
+}
+
+// queue admits event for key, the uid or address its rate limit is kept under, and queues
+// one send per non-empty address.
+func (s *NotificationService) queue(ctx context.Context, e *DeliveryEvent, key string, event api.NotificationEvent, email, phone string, distanceKm float64) error {
+	if email == "" && phone == "" { return nil }
+	recRef := s.firestore.Collection("notifications").Doc(e.Id + "_" + key)
+	rec, err := s.admit(ctx, recRef, key, string(event), e.DeliveryId)
+	if err != nil { return err }
+	if rec.Status != notificationAllowed { return nil }
+
+	tpl := s.templates[event]
+	data := notificationData{Delivery: e.Delivery, DistanceKm: distanceKm}
+	if s.trackingURL != "" && e.Delivery.TrackingToken != nil {
+		data.TrackingURL = s.trackingURL + *e.Delivery.TrackingToken
+	}
+	if email != "" {
+		err = s.enqueue(ctx, recRef, notificationChannelEmail, email, tpl.Subject, tpl.Email, data)
+		if err != nil { return err }
+	}
+	if phone != "" {
+		return s.enqueue(ctx, recRef, notificationChannelSMS, phone, nil, tpl.SMS, data)
+	}
+	return nil
+}
+
+// admit returns the record for an (event, recipient) pair, creating it on first sight and
+// counting it against the recipient's window: over the limit it is stored as rate_limited.
+func (s *NotificationService) admit(ctx context.Context, recRef *firestore.DocumentRef, uid, event, deliveryID string) (*notificationRecord, error) {
+	limitRef := s.firestore.Collection("notificationLimits").Doc(uid)
+	var rec notificationRecord
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		recSnap, err := tx.Get(recRef)
+		if err == nil { return recSnap.DataTo(&rec) }
+		if status.Code(err) != codes.NotFound { return err }
+
+		var win notificationWindow
+		limSnap, err := tx.Get(limitRef)
+		if err != nil && status.Code(err) != codes.NotFound { return err }
+		if err == nil {
+			err = limSnap.DataTo(&win)
+			if err != nil { return err }
+		}
+
+		now := time.Now().UTC()
+		if now.Sub(win.WindowStart) >= s.rateWindow {
+			win = notificationWindow{WindowStart: now}
+		}
+		rec = notificationRecord{UserId: uid, Event: event, DeliveryId: deliveryID, Status: notificationRateLimited, SentVia: []string{}, CreatedAt: now}
+		if win.Count < s.rateLimit {
+			win.Count++
+			rec.Status = notificationAllowed
+		}
+
+		err = tx.Set(limitRef, win)
+		if err != nil { return err }
+		return tx.Create(recRef, rec)
+	})
+	if err != nil { return nil, err }
+	return &rec, nil
+}
+
+// notificationSend is /notificationSends/{recordId}_{channel}: one rendered message
+// waiting for the worker.
+type notificationSend struct {
+	RecordId      string     `firestore:"recordId"`
+	Channel       string     `firestore:"channel"`
+	To            string     `firestore:"to"`
+	Subject       string     `firestore:"subject"`
+	Body          string     `firestore:"body"`
+	Status        string     `firestore:"status"` // pending | sent | dead
+	Attempts      int        `firestore:"attempts"`
+	LastError     *string    `firestore:"lastError"`
+	NextAttemptAt *time.Time `firestore:"nextAttemptAt"`
+	CreatedAt     time.Time  `firestore:"createdAt"`
+	SentAt        *time.Time `firestore:"sentAt"`
+}
+
+// enqueue renders one message and queues it for the worker. The fixed id makes queuing the
+// same message again, from a retried event or another instance, a no-op.
+func (s *NotificationService) enqueue(ctx context.Context, recRef *firestore.DocumentRef, channel, to string, subject, body *template.Template, data notificationData) error {
+	now := time.Now().UTC()
+	msg := notificationSend{RecordId: recRef.ID, Channel: channel, To: to, Status: notificationSendPending, NextAttemptAt: &now, CreatedAt: now}
+	var err error
+	if subject != nil {
+		msg.Subject, err = render(subject, data)
+		if err != nil { return err }
+	}
+	msg.Body, err = render(body, data)
+	if err != nil { return err }
+
+	_, err = s.firestore.Collection("notificationSends").Doc(recRef.ID + "_" + channel).Create(ctx, msg)
+	if err != nil && status.Code(err) != codes.AlreadyExists { return err }
+	return nil
+}
+
+// Run sends due notifications until ctx is cancelled.
+// They are queued by the outbox relay, which feeds delivery events to Deliver.
+func (s *NotificationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(notificationPollPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sendDue(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("notification sender: %v", err)
+			}
+		}
+	}
+}
+
+// sendDue claims pending sends whose time has come and sends them.
+func (s *NotificationService) sendDue(ctx context.Context) error {
+	docs, err := s.firestore.Collection("notificationSends").
+		Where("status", "==", notificationSendPending).
+		Where("nextAttemptAt", "<=", time.Now().UTC()).
+		OrderBy("nextAttemptAt", firestore.Asc).
+		Limit(notificationBatchSize).
+		Documents(ctx).GetAll()
+	if err != nil { return err }
+
+	for _, doc := range docs {
+		msg, ok, err := s.claim(ctx, doc.Ref)
+		if err != nil { return err }
+		if !ok { continue } // another instance got it first
+		if err := s.attempt(ctx, doc.Ref, msg); err != nil { return err }
+	}
+	return nil
+}
+
+// claim takes a due send for this instance by pushing nextAttemptAt past the lease,
+// so a crashed sender's message becomes due again instead of being lost.
+func (s *NotificationService) claim(ctx context.Context, ref *firestore.DocumentRef) (*notificationSend, bool, error) {
+	var msg notificationSend
+	claimed := false
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		snap, err := tx.Get(ref)
+		if err != nil { return err }
+		err = snap.DataTo(&msg)
+		if err != nil { return err }
+
+		now := time.Now().UTC()
+		if msg.Status != notificationSendPending || msg.NextAttemptAt == nil || msg.NextAttemptAt.After(now) {
+			return nil
+		}
+		lease := now.Add(notificationLease)
+		msg.NextAttemptAt = &lease
+		msg.Attempts++
+		claimed = true
+		return tx.Update(ref, []firestore.Update{
+			{Path: "nextAttemptAt", Value: lease},
+			{Path: "attempts", Value: msg.Attempts},
+		})
+	})
+	if err != nil { return nil, false, err }
+	return &msg, claimed, nil
+}
+
+// attempt sends a claimed message and records the outcome: sent, retry later, or dead.
+// A sent channel is also listed on the notification record.
+func (s *NotificationService) attempt(ctx context.Context, ref *firestore.DocumentRef, msg *notificationSend) error {
+	n := s.email
+	if msg.Channel == notificationChannelSMS { n = s.sms }
+	sendErr := n.Notify(ctx, Notification{To: msg.To, Subject: msg.Subject, Body: msg.Body})
+
+	now := time.Now().UTC()
+	var updates []firestore.Update
+	switch {
+	case sendErr == nil:
+		updates = []firestore.Update{
+			{Path: "status", Value: notificationSendSent},
+			{Path: "sentAt", Value: now},
+			{Path: "nextAttemptAt", Value: nil},
+			{Path: "lastError", Value: nil},
+		}
+	case msg.Attempts >= s.maxAttempts:
+		log.Printf("notification sender: giving up on %s after %d attempts: %v", ref.ID, msg.Attempts, sendErr)
+		updates = []firestore.Update{
+			{Path: "status", Value: notificationSendDead},
+			{Path: "nextAttemptAt", Value: nil},
+			{Path: "lastError", Value: sendErr.Error()},
+		}
+	default:
+		updates = []firestore.Update{
+			{Path: "nextAttemptAt", Value: now.Add(s.backoff(msg.Attempts))},
+			{Path: "lastError", Value: sendErr.Error()},
+		}
+	}
+	_, err := ref.Update(ctx, updates)
+	if err != nil || sendErr != nil { return err }
+	_, err = s.firestore.Collection("notifications").Doc(msg.RecordId).
+		Update(ctx, []firestore.Update{{Path: "sentVia", Value: firestore.ArrayUnion(msg.Channel)}})
+	return err
+}
+
+// backoff is the wait before the next attempt after `attempts` failures.
+func (s *NotificationService) backoff(attempts int) time.Duration {
+	d := s.backoffBase
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff { d = maxBackoff }
+	return d
+}
+
+// userEmail returns the account email of uid ("" when the user has none).
+func (s *NotificationService) userEmail(ctx context.Context, uid string) (string, error) {
+	doc, err := s.firestore.Collection("users").Doc(uid).Get(ctx)
+	if status.Code(err) == codes.NotFound { return "", nil }
+	if err != nil { return "", err }
+	email, _ := doc.Data()["email"].(string)
+	return email, nil
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecipientKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{"slash in the local part", "a/b@example.com", "ab@example.com", false},
+		{"case-insensitive", "Anna@Example.com", "anna@example.com", true},
+		{"surrounding space", " anna@example.com ", "anna@example.com", true},
+		{"different addresses", "anna@example.com", "ben@example.com", false},
+		{"phone numbers", "+4915112345678", "+4915112345679", false},
+		{"dots", "..", ".", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ka, kb := recipientKey(tt.a), recipientKey(tt.b)
+			for _, k := range []string{ka, kb} {
+				// a valid Firestore document id: no slash, not . or .., not __x__, at most 1500 bytes
+				if strings.Contains(k, "/") || k == "." || k == ".." || strings.HasPrefix(k, "__") || len(k) > 1500 {
+					t.Errorf("recipientKey gave an invalid document id %q", k)
+				}
+			}
+			if (ka == kb) != tt.same { t.Errorf("recipientKey(%q) == recipientKey(%q) is %v, want %v", tt.a, tt.b, ka == kb, tt.same) }
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+// notificationData is what the templates see.
+type notificationData struct {
+	Delivery    api.Delivery
+	DistanceKm  float64 // job.posted: courier to pickup
+	TrackingURL string  // recipient messages: public tracking page, "" without TRACKING_URL
+}
+
+// Events only a delivery's recipient is told about. They have no account, so these can't be
+// muted and aren't part of api.NotificationEvent.
+const (
+	notificationRecipientOnTheWay  api.NotificationEvent = "recipient.on_the_way"
+	notificationRecipientDelivered api.NotificationEvent = "recipient.delivered"
+)
+
+var recipientEvents = map[api.NotificationEvent]bool{
+	notificationRecipientOnTheWay:  true,
+	notificationRecipientDelivered: true,
+}
+
+// notificationTemplate renders one event: a subject and body for email, a short text for SMS.
+type notificationTemplate struct {
+	Subject *template.Template
+	Email   *template.Template
+	SMS     *template.Template
+}
+
+// defaultNotificationTexts holds subject, email and sms text per event.
+var defaultNotificationTexts = map[api.NotificationEvent][3]string{
+	api.NotificationEventJobPosted: {
+		`New job {{printf "%.1f" .DistanceKm}} km away: {{.Delivery.Item}}`,
+		"{{.Delivery.BusinessName}} posted a delivery near you.\n\n" +
+			"Pickup:  {{.Delivery.BusinessAddress}} ({{printf \"%.1f\" .DistanceKm}} km away)\n" +
+			"Deliver: {{.Delivery.DestinationAddress}}\n" +
+			"Pays:    {{printf \"%.2f\" .Delivery.Payment}}\n\n" +
+			"Open the courier app to accept it before someone else does.\n",
+		`New job {{printf "%.1f" .DistanceKm}} km away from {{.Delivery.BusinessName}}, pays {{printf "%.2f" .Delivery.Payment}}. Open the app to accept.`,
+	},
+	api.NotificationEventDeliveryAccepted: {
+		`A courier accepted your delivery of {{.Delivery.Item}}`,
+		"Your delivery of {{.Delivery.Item}} to {{.Delivery.DestinationAddress}} was accepted by a courier, who is on the way to pick it up.\n",
+		`Courier found for {{.Delivery.Item}}, on the way to pick it up.`,
+	},
+	api.NotificationEventDeliveryPickedUp: {
+		`{{.Delivery.Item}} is on its way`,
+		"Your delivery of {{.Delivery.Item}} was picked up and is on its way to {{.Delivery.DestinationAddress}}.\n",
+		`{{.Delivery.Item}} was picked up and is on its way to {{.Delivery.DestinationAddress}}.`,
+	},
+	api.NotificationEventDeliveryDelivered: {
+		`{{.Delivery.Item}} was delivered`,
+		"Your delivery of {{.Delivery.Item}} to {{.Delivery.DestinationAddress}} was delivered.\n\n" +
+			"You can now rate the courier and leave a tip in the app.\n",
+		`{{.Delivery.Item}} was delivered to {{.Delivery.DestinationAddress}}.`,
+	},
+	api.NotificationEventDeliveryRepriced: {
+		`No courier yet: {{.Delivery.Item}} now pays {{printf "%.2f" .Delivery.Payment}}`,
+		"No courier has accepted your delivery of {{.Delivery.Item}} to {{.Delivery.DestinationAddress}} yet, " +
+			"so its payment was raised to {{printf \"%.2f\" .Delivery.Payment}} as your repricing settings allow.\n",
+		`No courier yet for {{.Delivery.Item}}, payment raised to {{printf "%.2f" .Delivery.Payment}}.`,
+	},
+	api.NotificationEventDeliveryUnaccepted: {
+		`No courier has accepted {{.Delivery.Item}} yet`,
+		"No courier has accepted your delivery of {{.Delivery.Item}} to {{.Delivery.DestinationAddress}} yet, " +
+			"paying {{printf \"%.2f\" .Delivery.Payment}}.\n\n" +
+			"If nobody accepts it soon it will expire. Raising the payment makes it more attractive.\n",
+		`No courier yet for {{.Delivery.Item}}. It will expire unless someone accepts it soon.`,
+	},
+	api.NotificationEventDeliveryExpired: {
+		`{{.Delivery.Item}} expired without a courier`,
+		"No courier accepted your delivery of {{.Delivery.Item}} to {{.Delivery.DestinationAddress}} in time, so it expired.\n\n" +
+			"Post it again if it still needs delivering.\n",
+		`{{.Delivery.Item}} expired without a courier. Post it again if it still needs delivering.`,
+	},
+	api.NotificationEventDeliveryParcelMissing: {
+		`A parcel of {{.Delivery.Item}} is missing`,
+		"The courier of your delivery of {{.Delivery.Item}} to {{.Delivery.DestinationAddress}} reported parcels missing:\n\n" +
+			"{{range .Delivery.Parcels}}{{if eq .Status \"missing\"}}  {{.Label}}  {{.Description}}\n{{end}}{{end}}\n" +
+			"Check the delivery in the app for the courier's notes.\n",
+		`A parcel of {{.Delivery.Item}} was reported missing by the courier. Check the delivery in the app.`,
+	},
+	api.NotificationEventDeliveryFailed: {
+		`{{.Delivery.Item}} failed: every parcel went missing`,
+		"The courier of your delivery of {{.Delivery.Item}} to {{.Delivery.DestinationAddress}} reported every parcel missing{{if .Delivery.PickedUpAt}} on the way{{else}} at pickup{{end}}, so the delivery failed:\n\n" +
+			"{{range .Delivery.Parcels}}  {{.Label}}  {{.Description}}\n{{end}}\n" +
+			"Check the delivery in the app for the courier's notes.\n",
+		`{{.Delivery.Item}} failed: the courier reported every parcel missing. Check the delivery in the app.`,
+	},
+	notificationRecipientOnTheWay: {
+		`Your parcel from {{.Delivery.BusinessName}} is on its way`,
+		"{{.Delivery.BusinessName}}'s courier picked up {{.Delivery.Item}} and is on the way to {{.Delivery.DestinationAddress}}.\n" +
+			"{{if .TrackingURL}}\nFollow it here: {{.TrackingURL}}\n{{end}}",
+		`Your parcel from {{.Delivery.BusinessName}} is on its way.{{if .TrackingURL}} Track it: {{.TrackingURL}}{{end}}`,
+	},
+	notificationRecipientDelivered: {
+		`Your parcel from {{.Delivery.BusinessName}} was delivered`,
+		"{{.Delivery.Item}} from {{.Delivery.BusinessName}} was delivered to {{.Delivery.DestinationAddress}}.\n",
+		`Your parcel from {{.Delivery.BusinessName}} was delivered to {{.Delivery.DestinationAddress}}.`,
+	},
+}
+
+// loadNotificationTemplates parses the built-in templates, replacing any of them with
+// <dir>/<event>.subject.tmpl, <event>.email.tmpl or <event>.sms.tmpl when present.
+func loadNotificationTemplates(dir string) (map[api.NotificationEvent]notificationTemplate, error) {
+	out := map[api.NotificationEvent]notificationTemplate{}
+	for event, texts := range defaultNotificationTexts {
+		var parsed [3]*template.Template
+		for i, part := range []string{"subject", "email", "sms"} {
+			text := texts[i]
+			if dir != "" {
+				raw, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("%s.%s.tmpl", event, part)))
+				if err == nil {
+					text = strings.TrimRight(string(raw), "\n")
+					if part == "email" { text += "\n" }
+				} else if !errors.Is(err, os.ErrNotExist) {
+					return nil, err
+				}
+			}
+			t, err := template.New(string(event) + "." + part).Option("missingkey=error").Parse(text)
+			if err != nil { return nil, err }
+			parsed[i] = t
+		}
+		out[event] = notificationTemplate{Subject: parsed[0], Email: parsed[1], SMS: parsed[2]}
+	}
+	return out, nil
+}
+
+// render executes t with data.
+func render(t *template.Template, data notificationData) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil { return "", err }
+	return b.String(), nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/internal/config"
+)
+
+// Notification is one rendered message for one address (email or phone number).
+// Subject is ignored by channels that have none, like SMS.
+type Notification struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier sends notifications over one channel.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewNotifiers builds the email and SMS notifiers selected by NOTIFY_EMAIL (smtp or log)
+// and NOTIFY_SMS (http or log). Both default to the log sink, so a local setup
+// sends nothing but shows every message.
+// called once from main.go at startup
+func NewNotifiers() (email, sms Notifier, err error) {
+	var logOut io.Writer
+	if path := config.String("NOTIFY_LOG_FILE", ""); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil { return nil, nil, fmt.Errorf("NOTIFY_LOG_FILE: %w", err) }
+		logOut = f
+	}
+
+	switch kind := config.String("NOTIFY_EMAIL", "log"); kind {
+	case "log":
+		email = NewLogNotifier("email", logOut)
+	case "smtp":
+		email, err = NewSMTPNotifier(
+			config.String("SMTP_ADDR", ""), config.String("SMTP_FROM", ""),
+			config.String("SMTP_USERNAME", ""), config.String("SMTP_PASSWORD", ""),
+		)
+		if err != nil { return nil, nil, err }
+	default:
+		return nil, nil, fmt.Errorf("unknown NOTIFY_EMAIL %q (want smtp or log)", kind)
+	}
+
+	switch kind := config.String("NOTIFY_SMS", "log"); kind {
+	case "log":
+		sms = NewLogNotifier("sms", logOut)
+	case "http":
+		sms, err = NewSMSGatewayNotifier(config.String("SMS_GATEWAY_URL", ""), config.String("SMS_GATEWAY_TOKEN", ""))
+		if err != nil { return nil, nil, err }
+	default:
+		return nil, nil, fmt.Errorf("unknown NOTIFY_SMS %q (want http or log)", kind)
+	}
+	return email, sms, nil
+}
+
+// SMTPNotifier sends plain-text email through an SMTP relay (STARTTLS when offered).
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth // nil without SMTP_USERNAME
+}
+
+// NewSMTPNotifier sends as from through the relay at addr (host:port).
+func NewSMTPNotifier(addr, from, username, password string) (*SMTPNotifier, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil { return nil, fmt.Errorf("SMTP_ADDR must be host:port, got %q", addr) }
+	if from == "" { return nil, fmt.Errorf("SMTP_FROM not set") }
+
+	n := &SMTPNotifier{addr: addr, from: from}
+	if username != "" {
+		n.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return n, nil
+}
+
+// Notify sends one email. net/smtp takes no context; the relay is expected to answer quickly.
+func (n *SMTPNotifier) Notify(ctx context.Context, msg Notification) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerSafe(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{msg.To}, b.Bytes()); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+// headerSafe keeps template output from injecting extra mail headers.
+func headerSafe(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// SMSGatewayNotifier posts {"to", "message"} as JSON to a generic SMS HTTP gateway,
+// with the token as a bearer credential. Any 2xx answer counts as sent.
+type SMSGatewayNotifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewSMSGatewayNotifier targets the gateway at url.
+func NewSMSGatewayNotifier(url, token string) (*SMSGatewayNotifier, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("SMS_GATEWAY_URL must be an http(s) URL, got %q", url)
+	}
+	return &SMSGatewayNotifier{url: url, token: token, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// Notify sends one SMS.
+func (n *SMSGatewayNotifier) Notify(ctx context.Context, msg Notification) error {
+	body, err := json.Marshal(map[string]string{"to": msg.To, "message": msg.Body})
+	if err != nil { return err }
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil { return err }
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" { req.Header.Set("Authorization", "Bearer "+n.token) }
+
+	resp, err := n.client.Do(req)
+	if err != nil { return fmt.Errorf("sms gateway: %w", err) }
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("sms gateway: %s: %s", resp.Status, bytes.TrimSpace(raw))
+	}
+	return nil
+}
+
+// LogNotifier writes notifications to a log instead of sending them, for local testing.
+type LogNotifier struct {
+	channel string
+	logger  *log.Logger
+}
+
+// NewLogNotifier logs channel messages to out, or to the standard logger when out is nil.
+func NewLogNotifier(channel string, out io.Writer) *LogNotifier {
+	logger := log.Default()
+	if out != nil { logger = log.New(out, "", log.LstdFlags) }
+	return &LogNotifier{channel: channel, logger: logger}
+}
+
+// Notify logs one message.
+func (n *LogNotifier) Notify(ctx context.Context, msg Notification) error {
+	if msg.Subject != "" {
+		n.logger.Printf("notification [%s] to=%s subject=%q\n%s", n.channel, msg.To, msg.Subject, msg.Body)
+	} else {
+		n.logger.Printf("notification [%s] to=%s\n%s", n.channel, msg.To, msg.Body)
+	}
+	return nil
+}
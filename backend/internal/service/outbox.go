@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"github.com/google/uuid"
+)
+
+// OutboxSink is a consumer of delivery events fed by the OutboxRelay (webhooks, message bus, ...).
+// Name must be stable across restarts: it is the key under which deliveries to the sink are recorded.
+// Deliver may be called again for the same event if the process dies between a successful
+// Deliver and its record, so sinks should be idempotent on e.Id.
+type OutboxSink interface {
+	Name() string
+	Deliver(ctx context.Context, e *DeliveryEvent) error
+}
+
+// OutboxEntry is one event waiting to be handed to every sink, stored in /outbox/{eventId}.
+// DeliveredTo records each sink that already got it and Failures each sink still retrying, so
+// a round only goes to the sinks that are due — each sink sees every entry once. A sink that
+// fails OUTBOX_MAX_ATTEMPTS times gives up on the entry; it is then done but Dead, and kept
+// out of the retention sweep so it can be looked at.
+type OutboxEntry struct {
+	Id          string                     `firestore:"id"`
+	Event       DeliveryEvent              `firestore:"event"`
+	CreatedAt   time.Time                  `firestore:"createdAt"`
+	AvailableAt time.Time                  `firestore:"availableAt"` // not picked up before; pushed forward by claims and retries
+	ClaimedBy   string                     `firestore:"claimedBy"`   // token of the relay round holding the lease
+	DeliveredTo map[string]time.Time       `firestore:"deliveredTo"`
+	Failures    map[string]OutboxSinkError `firestore:"failures"`
+	Done        bool                       `firestore:"done"`
+	DoneAt      *time.Time                 `firestore:"doneAt"`
+	Dead        bool                       `firestore:"dead"` // some sink gave up on it
+}
+
+// OutboxSinkError is where one sink stands with an entry it failed to take.
+type OutboxSinkError struct {
+	Attempts  int       `firestore:"attempts"`
+	LastError string    `firestore:"lastError"`
+	RetryAt   time.Time `firestore:"retryAt"`
+	Dead      bool      `firestore:"dead"`
+}
+
+// addOutboxEntry queues an event inside the caller's transaction, so the event
+// is relayed exactly when the change it describes was committed.
+func addOutboxEntry(tx *firestore.Transaction, fs *firestore.Client, e DeliveryEvent) error {
+	entry := OutboxEntry{
+		Id:          e.Id,
+		Event:       e,
+		CreatedAt:   e.CreatedAt,
+		AvailableAt: e.CreatedAt,
+		DeliveredTo: map[string]time.Time{},
+	}
+	return tx.Create(fs.Collection("outbox").Doc(entry.Id), entry)
+}
+
+const (
+	outboxBatchSize  = 50
+	outboxMaxDelay   = 30 * time.Minute
+	outboxLeaseSlack = 30 * time.Second // on top of the sink timeout, for the writes around a call
+	outboxSweepEvery = time.Hour
+)
+
+// OutboxRelay hands committed outbox entries to the registered sinks and marks them done.
+// Several server instances can run it at once: entries are claimed in a transaction first,
+// and the claim is renewed before every sink call, so it never runs out while a sink works.
+type OutboxRelay struct {
+	firestore      *db.FirestoreClient
+	sinks          []OutboxSink
+	poll           time.Duration // env OUTBOX_POLL_INTERVAL
+	maxAttempts    int           // env OUTBOX_MAX_ATTEMPTS
+	sinkTimeout    time.Duration // env OUTBOX_SINK_TIMEOUT
+	retention      time.Duration // env OUTBOX_RETENTION
+	eventRetention time.Duration // env DELIVERY_EVENT_RETENTION
+}
+
+// NewOutboxRelay wires Firestore into the relay.
+// called once from main.go; sinks are registered before Run starts
+func NewOutboxRelay(fs *db.FirestoreClient) *OutboxRelay {
+	return &OutboxRelay{
+		firestore:      fs,
+		poll:           config.Duration("OUTBOX_POLL_INTERVAL", time.Second),
+		maxAttempts:    config.Int("OUTBOX_MAX_ATTEMPTS", 12),
+		sinkTimeout:    config.Duration("OUTBOX_SINK_TIMEOUT", 30*time.Second),
+		retention:      config.Duration("OUTBOX_RETENTION", 7*24*time.Hour),
+		eventRetention: config.Duration("DELIVERY_EVENT_RETENTION", 30*24*time.Hour),
+	}
+}
+
+// Register adds a sink. Not safe to call once Run has started.
+func (r *OutboxRelay) Register(sink OutboxSink) {
+	r.sinks = append(r.sinks, sink)
+}
+
+// lease is how long a claim holds: one sink call plus the writes around it.
+func (r *OutboxRelay) lease() time.Duration {
+	return r.sinkTimeout + outboxLeaseSlack
+}
+
+// Run relays due entries every poll interval, and sweeps old ones every hour, until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.poll)
+	defer ticker.Stop()
+	sweep := time.NewTicker(outboxSweepEvery)
+	defer sweep.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.relayDue(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("outbox relay: %v", err)
+			}
+		case <-sweep.C:
+			if err := r.sweep(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("outbox sweep: %v", err)
+			}
+		}
+	}
+}
+
+// relayDue claims and relays one batch of due entries, oldest first. An entry that can't be
+// relayed is logged and left to its next round; it doesn't hold up the rest of the batch.
+func (r *OutboxRelay) relayDue(ctx context.Context) error {
+	docs, err := r.firestore.Collection("outbox").
+		Where("done", "==", false).
+		Where("availableAt", "<=", time.Now().UTC()).
+		OrderBy("availableAt", firestore.Asc).
+		Limit(outboxBatchSize).
+		Documents(ctx).GetAll()
+	if err != nil { return err }
+
+	for _, doc := range docs {
+		if ctx.Err() != nil { return nil }
+		token := uuid.NewString()
+		entry, ok, err := r.claim(ctx, doc.Ref, token, false)
+		if err != nil {
+			log.Printf("outbox relay: claim %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		if !ok { continue } // another instance got it first
+		if err := r.relay(ctx, doc.Ref, entry, token); err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: %s: %v", doc.Ref.ID, err)
+		}
+	}
+	return nil
+}
+
+// claim takes a due entry for the round holding token by pushing availableAt past the lease.
+// With renew set it only extends a lease the round already holds, so a round that lost its
+// lease to another instance stops instead of calling sinks twice.
+func (r *OutboxRelay) claim(ctx context.Context, ref *firestore.DocumentRef, token string, renew bool) (*OutboxEntry, bool, error) {
+	var entry OutboxEntry
+	claimed := false
+	err := r.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		entry = OutboxEntry{}
+		snap, err := tx.Get(ref)
+		if err != nil { return err }
+		err = snap.DataTo(&entry)
+		if err != nil { return err }
+
+		now := time.Now().UTC()
+		if entry.Done { return nil }
+		if renew && entry.ClaimedBy != token { return nil }
+		if !renew && entry.AvailableAt.After(now) { return nil }
+		claimed = true
+		return tx.Update(ref, []firestore.Update{
+			{Path: "availableAt", Value: now.Add(r.lease())},
+			{Path: "claimedBy", Value: token},
+		})
+	})
+	if err != nil { return nil, false, err }
+	return &entry, claimed, nil
+}
+
+// relay offers a claimed entry to every sink that is due for it, recording each outcome right
+// away. A failing sink only backs off itself; the others still get the entry this round. Once
+// no sink is left waiting the entry is done, and the claim is released at the earliest retry.
+func (r *OutboxRelay) relay(ctx context.Context, ref *firestore.DocumentRef, entry *OutboxEntry, token string) error {
+	if entry.DeliveredTo == nil { entry.DeliveredTo = map[string]time.Time{} }
+	if entry.Failures == nil { entry.Failures = map[string]OutboxSinkError{} }
+
+	for _, sink := range r.sinks {
+		name := sink.Name()
+		if _, done := entry.DeliveredTo[name]; done { continue }
+		failure := entry.Failures[name]
+		if failure.Dead || failure.RetryAt.After(time.Now().UTC()) { continue }
+
+		_, held, err := r.claim(ctx, ref, token, true)
+		if err != nil { return err }
+		if !held { return nil } // lease lost; whoever holds it now carries on
+
+		sinkCtx, cancel := context.WithTimeout(ctx, r.sinkTimeout)
+		err = sink.Deliver(sinkCtx, &entry.Event)
+		cancel()
+		now := time.Now().UTC()
+		if err == nil {
+			entry.DeliveredTo[name] = now
+			delete(entry.Failures, name)
+			_, err = ref.Update(ctx, []firestore.Update{
+				{FieldPath: firestore.FieldPath{"deliveredTo", name}, Value: now},
+				{FieldPath: firestore.FieldPath{"failures", name}, Value: firestore.Delete},
+			})
+			if err != nil { return err }
+			continue
+		}
+		if ctx.Err() != nil { return ctx.Err() }
+
+		failure.Attempts++
+		failure.LastError = err.Error()
+		delay := time.Duration(1<<min(failure.Attempts, 16)) * time.Second
+		if delay > outboxMaxDelay { delay = outboxMaxDelay }
+		failure.RetryAt = now.Add(delay)
+		if failure.Attempts >= r.maxAttempts {
+			failure.Dead = true
+			log.Printf("outbox relay: %s gave up on event %s after %d attempts: %v", name, entry.Id, failure.Attempts, err)
+		}
+		entry.Failures[name] = failure
+		_, err = ref.Update(ctx, []firestore.Update{{FieldPath: firestore.FieldPath{"failures", name}, Value: failure}})
+		if err != nil { return err }
+	}
+
+	var next *time.Time
+	dead := false
+	for _, sink := range r.sinks {
+		if _, done := entry.DeliveredTo[sink.Name()]; done { continue }
+		failure := entry.Failures[sink.Name()]
+		if failure.Dead {
+			dead = true
+			continue
+		}
+		if next == nil || failure.RetryAt.Before(*next) { next = &failure.RetryAt }
+	}
+	if next != nil {
+		_, err := ref.Update(ctx, []firestore.Update{
+			{Path: "availableAt", Value: *next},
+			{Path: "claimedBy", Value: ""},
+		})
+		return err
+	}
+	_, err := ref.Update(ctx, []firestore.Update{
+		{Path: "done", Value: true},
+		{Path: "doneAt", Value: time.Now().UTC()},
+		{Path: "dead", Value: dead},
+		{Path: "claimedBy", Value: ""},
+	})
+	return err
+}
+
+// sweep deletes done outbox entries after OUTBOX_RETENTION, except dead ones, and delivery
+// events after DELIVERY_EVENT_RETENTION. The event stream can't resume from before that.
+func (r *OutboxRelay) sweep(ctx context.Context) error {
+	now := time.Now().UTC()
+	err := r.deleteBefore(ctx, "outbox", "doneAt", now.Add(-r.retention), func(doc *firestore.DocumentSnapshot) bool {
+		dead, _ := doc.DataAt("dead")
+		return dead == true
+	})
+	if err != nil { return err }
+	return r.deleteBefore(ctx, "deliveryEvents", "createdAt", now.Add(-r.eventRetention), nil)
+}
+
+// deleteBefore deletes the documents of collection whose field is before cutoff, in pages,
+// leaving those skip picks.
+func (r *OutboxRelay) deleteBefore(ctx context.Context, collection, field string, cutoff time.Time, skip func(*firestore.DocumentSnapshot) bool) error {
+	const page = 500
+	var after *firestore.DocumentSnapshot
+	for {
+		q := r.firestore.Collection(collection).Where(field, "<", cutoff).OrderBy(field, firestore.Asc).Limit(page)
+		if after != nil { q = q.StartAfter(after) }
+		docs, err := q.Documents(ctx).GetAll()
+		if err != nil { return err }
+		for _, doc := range docs {
+			if skip != nil && skip(doc) { continue }
+			if _, err := doc.Ref.Delete(ctx); err != nil { return err }
+		}
+		if len(docs) < page { return nil }
+		after = docs[len(docs)-1]
+	}
+}
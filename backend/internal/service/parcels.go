@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrInvalidParcels = errors.New("each parcel needs a unique label, a description and a weightKg of 0 or more")
+
+var ErrParcelNotFound = errors.New("delivery has no parcel with this label")
+
+var ErrInvalidScan = errors.New("scan doesn't fit the delivery's or the parcel's status")
+
+var ErrUnscannedParcels = errors.New("scan every parcel or report it missing first, with at least one picked up")
+
+var ErrNothingDelivered = errors.New("no parcel was dropped off; fail the delivery if every parcel was lost")
+
+var ErrNotFailable = errors.New("a delivery can only fail once every parcel was reported missing")
+
+// validateParcels checks the optional parcel list of a new delivery.
+func validateParcels(req *api.DeliveryCreate) error {
+	if req.Parcels == nil { return nil }
+	seen := map[string]bool{}
+	for _, p := range *req.Parcels {
+		label := strings.TrimSpace(p.Label)
+		if label == "" || seen[label] || strings.TrimSpace(p.Description) == "" { return ErrInvalidParcels }
+		if p.WeightKg != nil && *p.WeightKg < 0 { return ErrInvalidParcels }
+		seen[label] = true
+	}
+	return nil
+}
+
+// parcelsWeight adds up the parcels' weights; nil when none of them has one.
+func parcelsWeight(list *[]api.ParcelCreate) *float64 {
+	if list == nil { return nil }
+	var total *float64
+	for _, p := range *list {
+		if p.WeightKg == nil { continue }
+		if total == nil { total = new(float64) }
+		*total += *p.WeightKg
+	}
+	return total
+}
+
+// newParcels turns the parcels of a new delivery into ones waiting for their pickup scan.
+func newParcels(list *[]api.ParcelCreate) *[]api.Parcel {
+	if list == nil || len(*list) == 0 { return nil }
+	out := make([]api.Parcel, 0, len(*list))
+	for _, p := range *list {
+		out = append(out, api.Parcel{
+			Label:       strings.TrimSpace(p.Label),
+			Description: strings.TrimSpace(p.Description),
+			WeightKg:    p.WeightKg,
+			Status:      api.ParcelStatusPending,
+			Scans:       []api.ParcelScan{},
+		})
+	}
+	return &out
+}
+
+// scanResult is the status a parcel gets from event, given where the delivery is.
+// Parcels are picked up while the delivery is accepted and dropped off while it is picked up.
+// One reported missing at pickup may still turn up before the courier leaves; one lost on
+// the way stays missing.
+func scanResult(delivery api.DeliveryStatus, parcel api.ParcelStatus, event api.ParcelScanEvent) (api.ParcelStatus, error) {
+	switch {
+	case event == api.ParcelScanEventPickedUp && delivery == api.DeliveryStatusAccepted &&
+		(parcel == api.ParcelStatusPending || parcel == api.ParcelStatusMissing):
+		return api.ParcelStatusPickedUp, nil
+	case event == api.ParcelScanEventDelivered && delivery == api.DeliveryStatusPickedUp && parcel == api.ParcelStatusPickedUp:
+		return api.ParcelStatusDelivered, nil
+	case event == api.ParcelScanEventMissing && delivery == api.DeliveryStatusAccepted && parcel == api.ParcelStatusPending,
+		event == api.ParcelScanEventMissing && delivery == api.DeliveryStatusPickedUp && parcel == api.ParcelStatusPickedUp:
+		return api.ParcelStatusMissing, nil
+	}
+	return "", ErrInvalidScan
+}
+
+// parcelsReady checks that d's parcels allow moving it to newStatus: every parcel scanned
+// or reported missing at pickup, with at least one on board, and every parcel on board
+// dropped off or reported missing at delivery, with at least one handed over. With every
+// parcel missing, at pickup or on the way, there is nothing to hand over, and failing the
+// delivery is the only way on. Deliveries without parcels always pass, except that they
+// can't fail.
+func parcelsReady(d *api.Delivery, newStatus string) error {
+	if d.Parcels == nil || len(*d.Parcels) == 0 {
+		if newStatus == StatusFailed { return ErrNotFailable }
+		return nil
+	}
+	count := map[api.ParcelStatus]int{}
+	for _, p := range *d.Parcels {
+		count[p.Status]++
+	}
+	switch newStatus {
+	case StatusPickedUp:
+		if count[api.ParcelStatusPending] > 0 || count[api.ParcelStatusPickedUp] == 0 { return ErrUnscannedParcels }
+	case StatusDelivered:
+		if count[api.ParcelStatusPickedUp] > 0 { return ErrUnscannedParcels }
+		if count[api.ParcelStatusDelivered] == 0 { return ErrNothingDelivered }
+	case StatusFailed:
+		if count[api.ParcelStatusMissing] != len(*d.Parcels) { return ErrNotFailable }
+	}
+	return nil
+}
+
+// POST /deliveries/{id}/parcels/{label}/scans
+// ScanParcel records a scan of one parcel by the assigned courier. A parcel reported missing
+// is emitted as delivery.parcel_missing so the business hears about it; other scans as
+// delivery.parcel_scanned.
+func (s *DeliveryService) ScanParcel(ctx context.Context, deliveryID, label, courierUID string, scan api.ParcelScanCreate) (*api.Delivery, error) {
+	if scan.Note != nil {
+		trimmed := strings.TrimSpace(*scan.Note)
+		scan.Note = &trimmed
+		if trimmed == "" { scan.Note = nil }
+	}
+
+	docRef := s.firestore.Collection("deliveries").Doc(deliveryID)
+	var d api.Delivery
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound { return ErrDeliveryNotFound }
+		if err != nil { return err }
+		d = api.Delivery{}
+		err = snap.DataTo(&d)
+		if err != nil { return err }
+
+		if d.AssignedTo == nil || *d.AssignedTo != courierUID { return ErrInvalidUpdate }
+		if d.Parcels == nil { return ErrParcelNotFound }
+
+		before := d
+		parcels := append([]api.Parcel(nil), *d.Parcels...)
+		i := 0
+		for i < len(parcels) && parcels[i].Label != label {
+			i++
+		}
+		if i == len(parcels) { return ErrParcelNotFound }
+
+		result, err := scanResult(d.Status, parcels[i].Status, scan.Event)
+		if err != nil { return err }
+		p := parcels[i]
+		p.Scans = append(append([]api.ParcelScan(nil), p.Scans...), api.ParcelScan{
+			Event:     scan.Event,
+			CourierId: courierUID,
+			Note:      scan.Note,
+			At:        time.Now().UTC(),
+		})
+		p.Status = result
+		parcels[i] = p
+		d.Parcels = &parcels
+
+		err = tx.Set(docRef, d)
+		if err != nil { return err }
+		eventType := EventDeliveryParcelScanned
+		if result == api.ParcelStatusMissing { eventType = EventDeliveryParcelMissing }
+		return addDeliveryEvent(tx, s.firestore.Client, eventType, deliveryID, &before, d)
+	})
+	if err != nil { return nil, err }
+	d.Id = &deliveryID
+	return &d, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+func TestScanResult(t *testing.T) {
+	type scan struct {
+		delivery api.DeliveryStatus
+		parcel   api.ParcelStatus
+		event    api.ParcelScanEvent
+	}
+	// every scan that's allowed; all other combinations are ErrInvalidScan
+	valid := map[scan]api.ParcelStatus{
+		{api.DeliveryStatusAccepted, api.ParcelStatusPending, api.ParcelScanEventPickedUp}:  api.ParcelStatusPickedUp,
+		{api.DeliveryStatusAccepted, api.ParcelStatusMissing, api.ParcelScanEventPickedUp}:  api.ParcelStatusPickedUp, // found after all
+		{api.DeliveryStatusAccepted, api.ParcelStatusPending, api.ParcelScanEventMissing}:   api.ParcelStatusMissing,
+		{api.DeliveryStatusPickedUp, api.ParcelStatusPickedUp, api.ParcelScanEventDelivered}: api.ParcelStatusDelivered,
+		{api.DeliveryStatusPickedUp, api.ParcelStatusPickedUp, api.ParcelScanEventMissing}:  api.ParcelStatusMissing,
+	}
+
+	deliveries := []api.DeliveryStatus{api.DeliveryStatusPosted, api.DeliveryStatusAccepted, api.DeliveryStatusPickedUp,
+		api.DeliveryStatusDelivered, api.DeliveryStatusFailed, api.DeliveryStatusExpired}
+	parcels := []api.ParcelStatus{api.ParcelStatusPending, api.ParcelStatusPickedUp, api.ParcelStatusDelivered, api.ParcelStatusMissing}
+	events := []api.ParcelScanEvent{api.ParcelScanEventPickedUp, api.ParcelScanEventDelivered, api.ParcelScanEventMissing}
+
+	for _, d := range deliveries {
+		for _, p := range parcels {
+			for _, e := range events {
+				tt := scan{d, p, e}
+				t.Run(string(d)+"/"+string(p)+"/"+string(e), func(t *testing.T) {
+					got, err := scanResult(tt.delivery, tt.parcel, tt.event)
+					want, ok := valid[tt]
+					if !ok {
+						if err != ErrInvalidScan { t.Fatalf("scanResult = %q, %v, want ErrInvalidScan", got, err) }
+						return
+					}
+					if err != nil { t.Fatalf("scanResult: %v", err) }
+					if got != want { t.Errorf("scanResult = %q, want %q", got, want) }
+				})
+			}
+		}
+	}
+}
+
+func TestParcelsReady(t *testing.T) {
+	tests := []struct {
+		name      string
+		parcels   []api.ParcelStatus // nil: the delivery has no parcels
+		newStatus string
+		want      error
+	}{
+		{"no parcels, picked up", nil, StatusPickedUp, nil},
+		{"no parcels, delivered", nil, StatusDelivered, nil},
+		{"no parcels can't fail", nil, StatusFailed, ErrNotFailable},
+		{"all on board", []api.ParcelStatus{api.ParcelStatusPickedUp, api.ParcelStatusPickedUp}, StatusPickedUp, nil},
+		{"one missing at pickup", []api.ParcelStatus{api.ParcelStatusPickedUp, api.ParcelStatusMissing}, StatusPickedUp, nil},
+		{"one still pending", []api.ParcelStatus{api.ParcelStatusPickedUp, api.ParcelStatusPending}, StatusPickedUp, ErrUnscannedParcels},
+		{"nothing on board", []api.ParcelStatus{api.ParcelStatusMissing, api.ParcelStatusMissing}, StatusPickedUp, ErrUnscannedParcels},
+		{"all dropped off", []api.ParcelStatus{api.ParcelStatusDelivered, api.ParcelStatusDelivered}, StatusDelivered, nil},
+		{"one lost on the way", []api.ParcelStatus{api.ParcelStatusDelivered, api.ParcelStatusMissing}, StatusDelivered, nil},
+		{"one still on board", []api.ParcelStatus{api.ParcelStatusDelivered, api.ParcelStatusPickedUp}, StatusDelivered, ErrUnscannedParcels},
+		{"nothing handed over", []api.ParcelStatus{api.ParcelStatusMissing, api.ParcelStatusMissing}, StatusDelivered, ErrNothingDelivered},
+		{"all missing fails", []api.ParcelStatus{api.ParcelStatusMissing, api.ParcelStatusMissing}, StatusFailed, nil},
+		{"one still on board can't fail", []api.ParcelStatus{api.ParcelStatusMissing, api.ParcelStatusPickedUp}, StatusFailed, ErrNotFailable},
+		{"one handed over can't fail", []api.ParcelStatus{api.ParcelStatusMissing, api.ParcelStatusDelivered}, StatusFailed, ErrNotFailable},
+		{"pending can't fail", []api.ParcelStatus{api.ParcelStatusMissing, api.ParcelStatusPending}, StatusFailed, ErrNotFailable},
+		{"other statuses aren't checked", []api.ParcelStatus{api.ParcelStatusPending}, StatusAccepted, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d api.Delivery
+			if tt.parcels != nil {
+				parcels := make([]api.Parcel, len(tt.parcels))
+				for i, st := range tt.parcels {
+					parcels[i] = api.Parcel{Label: string(rune('a' + i)), Status: st}
+				}
+				d.Parcels = &parcels
+			}
+			if err := parcelsReady(&d, tt.newStatus); err != tt.want { t.Errorf("parcelsReady(%v, %s) = %v, want %v", tt.parcels, tt.newStatus, err, tt.want) }
+		})
+	}
+}
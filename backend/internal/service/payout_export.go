@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+// payoutCurrency is the only currency payouts are made in (SEPA credit transfers are EUR-only).
+const payoutCurrency = "EUR"
+
+// Payout batch export formats.
+const (
+	ExportCSV  = "csv"
+	ExportSEPA = "sepa"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// ExportPayoutBatch renders a paid batch as a file for the finance team.
+// Returns the file content, its content type and a suggested file name.
+// The SEPA debtor (our account) comes from env PAYOUT_DEBTOR_NAME / PAYOUT_DEBTOR_IBAN.
+func (s *PayoutService) ExportPayoutBatch(ctx context.Context, batchID, format string) ([]byte, string, string, error) {
+	batch, payouts, err := s.getBatchWithPayouts(ctx, batchID)
+	if err != nil { return nil, "", "", err }
+
+	switch format {
+	case "", ExportCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write([]string{"payout_id", "courier_id", "courier_name", "account_holder", "iban", "amount", "currency", "reference"})
+		for _, p := range payouts {
+			_ = w.Write([]string{
+				*p.Id, p.CourierId, csvCell(p.CourierName), csvCell(p.AccountHolder), p.Iban,
+				fmt.Sprintf("%.2f", p.Amount), payoutCurrency, "Courier payout " + *p.Id,
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil { return nil, "", "", err }
+		return buf.Bytes(), "text/csv", "payout-batch-" + batchID + ".csv", nil
+
+	case ExportSEPA:
+		doc := buildSEPATransfer(batchID, batch.Total, payouts)
+		out, err := xml.MarshalIndent(doc, "", "  ")
+		if err != nil { return nil, "", "", err }
+		return append([]byte(xml.Header), out...), "application/xml", "payout-batch-" + batchID + ".xml", nil
+	}
+	return nil, "", "", ErrUnsupportedFormat
+}
+
+// csvCell keeps user-entered text from being run as a formula when the file is opened
+// in a spreadsheet: a leading =, +, -, @, tab or carriage return gets a ' in front.
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) { return "'" + v }
+	return v
+}
+
+// ---- minimal ISO 20022 pain.001.001.03 (SEPA credit transfer initiation) ----
+
+type sepaDocument struct {
+	XMLName xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.03 Document"`
+	Init    sepaInit `xml:"CstmrCdtTrfInitn"`
+}
+
+type sepaInit struct {
+	GrpHdr sepaGroupHeader `xml:"GrpHdr"`
+	PmtInf sepaPaymentInfo `xml:"PmtInf"`
+}
+
+type sepaGroupHeader struct {
+	MsgId    string    `xml:"MsgId"`
+	CreDtTm  string    `xml:"CreDtTm"`
+	NbOfTxs  int       `xml:"NbOfTxs"`
+	CtrlSum  string    `xml:"CtrlSum"`
+	InitgPty sepaParty `xml:"InitgPty"`
+}
+
+type sepaParty struct {
+	Nm string `xml:"Nm"`
+}
+
+type sepaAccount struct {
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type sepaPaymentInfo struct {
+	PmtInfId    string         `xml:"PmtInfId"`
+	PmtMtd      string         `xml:"PmtMtd"`
+	NbOfTxs     int            `xml:"NbOfTxs"`
+	CtrlSum     string         `xml:"CtrlSum"`
+	SvcLvl      string         `xml:"PmtTpInf>SvcLvl>Cd"`
+	ReqdExctnDt string         `xml:"ReqdExctnDt"`
+	Dbtr        sepaParty      `xml:"Dbtr"`
+	DbtrAcct    sepaAccount    `xml:"DbtrAcct"`
+	DbtrAgt     string         `xml:"DbtrAgt>FinInstnId>Othr>Id"`
+	ChrgBr      string         `xml:"ChrgBr"`
+	Txs         []sepaTransfer `xml:"CdtTrfTxInf"`
+}
+
+type sepaTransfer struct {
+	EndToEndId string      `xml:"PmtId>EndToEndId"`
+	Amt        sepaAmount  `xml:"Amt>InstdAmt"`
+	Cdtr       sepaParty   `xml:"Cdtr"`
+	CdtrAcct   sepaAccount `xml:"CdtrAcct"`
+	Ustrd      string      `xml:"RmtInf>Ustrd"`
+}
+
+type sepaAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+func sepaID(id string) string {
+	id = strings.ReplaceAll(id, "-", "")
+	if len(id) > 35 {
+		id = id[:35]
+	}
+	return id
+}
+
+func buildSEPATransfer(batchID string, total float64, payouts []*api.Payout) sepaDocument {
+	now := time.Now().UTC()
+	debtor := sepaParty{Nm: os.Getenv("PAYOUT_DEBTOR_NAME")}
+	// SEPA ids are limited to 35 characters; a UUID without dashes is 32
+	msgID := sepaID(batchID)
+
+	txs := make([]sepaTransfer, 0, len(payouts))
+	for _, p := range payouts {
+		txs = append(txs, sepaTransfer{
+			EndToEndId: sepaID(*p.Id),
+			Amt:        sepaAmount{Ccy: payoutCurrency, Value: fmt.Sprintf("%.2f", p.Amount)},
+			Cdtr:       sepaParty{Nm: p.AccountHolder},
+			CdtrAcct:   sepaAccount{IBAN: p.Iban},
+			Ustrd:      "Courier payout " + *p.Id,
+		})
+	}
+
+	ctrlSum := fmt.Sprintf("%.2f", total)
+	return sepaDocument{Init: sepaInit{
+		GrpHdr: sepaGroupHeader{
+			MsgId:    msgID,
+			CreDtTm:  now.Format("2006-01-02T15:04:05"),
+			NbOfTxs:  len(txs),
+			CtrlSum:  ctrlSum,
+			InitgPty: debtor,
+		},
+		PmtInf: sepaPaymentInfo{
+			PmtInfId:    msgID,
+			PmtMtd:      "TRF",
+			NbOfTxs:     len(txs),
+			CtrlSum:     ctrlSum,
+			SvcLvl:      "SEPA",
+			ReqdExctnDt: now.Format("2006-01-02"),
+			Dbtr:        debtor,
+			DbtrAcct:    sepaAccount{IBAN: os.Getenv("PAYOUT_DEBTOR_IBAN")},
+			DbtrAgt:     "NOTPROVIDED",
+			ChrgBr:      "SLEV",
+			Txs:         txs,
+		},
+	}}
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+)
+
+// PayoutService handles courier withdrawals: requests, admin review and payout batches.
+// A requested amount is reserved on the courier (reservedBalance) until it is either
+// rejected (reservation released) or paid in a batch (balance and reservation both drop).
+type PayoutService struct {
+	firestore *db.FirestoreClient
+}
+
+// NewPayoutService wires Firestore into the payout domain.
+// called once from main.go at startup
+func NewPayoutService(fs *db.FirestoreClient) *PayoutService {
+	return &PayoutService{firestore: fs}
+}
+
+var ErrInvalidAmount = errors.New("amount must be greater than zero")
+
+var ErrInsufficientBalance = errors.New("amount exceeds available balance")
+
+var ErrInvalidIBAN = errors.New("invalid IBAN")
+
+var ErrNoAccountHolder = errors.New("account holder is required")
+
+var ErrNoApprovedPayouts = errors.New("no approved payouts to batch")
+
+// maxBatchSize keeps one batch inside a single Firestore transaction
+// (every payout costs a payout write, a courier write and a ledger write).
+const maxBatchSize = 150
+
+// payoutTransitions lists the allowed next statuses for a payout.
+var payoutTransitions = map[api.PayoutStatus][]api.PayoutStatus{
+	api.PayoutStatusPending:  {api.PayoutStatusApproved, api.PayoutStatusRejected},
+	api.PayoutStatusApproved: {api.PayoutStatusPaid, api.PayoutStatusRejected},
+}
+
+func isValidPayoutTransition(from, to api.PayoutStatus) error {
+	for _, next := range payoutTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return ErrInvalidTransition{From: string(from), To: string(to)}
+}
+
+// roundCents rounds a money amount to two decimals.
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// normalizeIBAN strips spaces, upper-cases and checks the ISO 13616 mod-97 checksum.
+func normalizeIBAN(raw string) (string, error) {
+	iban := strings.ToUpper(strings.ReplaceAll(raw, " ", ""))
+	if len(iban) < 15 || len(iban) > 34 {
+		return "", ErrInvalidIBAN
+	}
+	// move country code + check digits to the end, letters become 10..35
+	rearranged := iban[4:] + iban[:4]
+	var digits strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		default:
+			return "", ErrInvalidIBAN
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok || new(big.Int).Mod(n, big.NewInt(97)).Int64() != 1 {
+		return "", ErrInvalidIBAN
+	}
+	return iban, nil
+}
+
+// POST /couriers/me/payouts
+// RequestPayout reserves the requested amount on the courier and stores a pending payout.
+// The read of balance/reservedBalance and the reservation happen in one transaction,
+// so two parallel requests can't both spend the same available balance.
+func (s *PayoutService) RequestPayout(ctx context.Context, courierUID string, req *api.PayoutCreate) (*api.Payout, error) {
+	amount := roundCents(req.Amount)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	iban, err := normalizeIBAN(req.Iban)
+	if err != nil {
+		return nil, err
+	}
+	// the holder becomes the creditor name of the SEPA transfer, which banks require
+	holder := strings.TrimSpace(req.AccountHolder)
+	if holder == "" {
+		return nil, ErrNoAccountHolder
+	}
+
+	id := uuid.NewString()
+	payoutRef := s.firestore.Collection("payouts").Doc(id)
+	courierRef := s.firestore.Collection("users").Doc(courierUID)
+	var payout api.Payout
+
+	err = s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		courierSnap, err := tx.Get(courierRef)
+		if err != nil { return err }
+
+		var courier api.CourierUser
+		err = courierSnap.DataTo(&courier)
+		if err != nil { return err }
+
+		available := roundCents(courier.Balance - courier.ReservedBalance)
+		if amount > available {
+			return ErrInsufficientBalance
+		}
+
+		now := time.Now().UTC()
+		payout = api.Payout{
+			Id:            &id,
+			CourierId:     courierUID,
+			CourierName:   courier.CourierName,
+			Amount:        amount,
+			Iban:          iban,
+			AccountHolder: holder,
+			Status:        api.PayoutStatusPending,
+			CreatedAt:     &now,
+		}
+
+		err = tx.Update(courierRef, []firestore.Update{{Path: "reservedBalance", Value: roundCents(courier.ReservedBalance + amount)}})
+		if err != nil { return err }
+		return tx.Create(payoutRef, payout)
+	})
+	if err != nil { return nil, err }
+	return &payout, nil
+}
+
+// GET /couriers/me/payouts and GET /payouts
+// ListPayouts returns payouts newest first, optionally only one courier's and/or one status.
+func (s *PayoutService) ListPayouts(ctx context.Context, courierUID string, status *string) ([]*api.Payout, error) {
+	q := s.firestore.Collection("payouts").OrderBy("createdAt", firestore.Desc)
+	if courierUID != "" {
+		q = q.Where("courierId", "==", courierUID)
+	}
+	if status != nil {
+		q = q.Where("status", "==", *status)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var result []*api.Payout
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var p api.Payout
+		if err := doc.DataTo(&p); err != nil { continue }
+		id := doc.Ref.ID
+		p.Id = &id
+		result = append(result, &p)
+	}
+	return result, nil
+}
+
+// POST /payouts/{id}/approve
+// ApprovePayout marks a pending payout as approved; the amount stays reserved until paid.
+func (s *PayoutService) ApprovePayout(ctx context.Context, payoutID, adminUID string) (*api.Payout, error) {
+	return s.reviewPayout(ctx, payoutID, adminUID, api.PayoutStatusApproved, nil)
+}
+
+// POST /payouts/{id}/reject
+// RejectPayout marks a pending/approved payout as rejected and releases the courier's reservation.
+func (s *PayoutService) RejectPayout(ctx context.Context, payoutID, adminUID string, reason *string) (*api.Payout, error) {
+	return s.reviewPayout(ctx, payoutID, adminUID, api.PayoutStatusRejected, reason)
+}
+
+// reviewPayout applies an admin decision to a payout in one transaction.
+func (s *PayoutService) reviewPayout(ctx context.Context, payoutID, adminUID string, to api.PayoutStatus, reason *string) (*api.Payout, error) {
+	payoutRef := s.firestore.Collection("payouts").Doc(payoutID)
+	var payout api.Payout
+
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(payoutRef)
+		if err != nil { return err }
+
+		err = snap.DataTo(&payout)
+		if err != nil { return err }
+
+		err = isValidPayoutTransition(payout.Status, to)
+		if err != nil { return err }
+
+		// all reads must happen before any write inside a transaction
+		var courierRef *firestore.DocumentRef
+		var courier api.CourierUser
+		if to == api.PayoutStatusRejected {
+			courierRef = s.firestore.Collection("users").Doc(payout.CourierId)
+			courierSnap, err := tx.Get(courierRef)
+			if err != nil { return err }
+			err = courierSnap.DataTo(&courier)
+			if err != nil { return err }
+		}
+
+		now := time.Now().UTC()
+		payout.Status = to
+		payout.ReviewedBy = &adminUID
+		payout.ReviewedAt = &now
+		payout.Reason = reason
+		payout.Id = &payoutID
+
+		if courierRef != nil {
+			released := math.Max(0, roundCents(courier.ReservedBalance-payout.Amount))
+			err = tx.Update(courierRef, []firestore.Update{{Path: "reservedBalance", Value: released}})
+			if err != nil { return err }
+		}
+		return tx.Set(payoutRef, payout)
+	})
+	if err != nil { return nil, err }
+	return &payout, nil
+}
+
+// POST /payouts/batches
+// CreatePayoutBatch collects approved payouts into one batch and settles them:
+// payout → paid, courier balance and reservation drop by the amount, and a payout
+// ledger entry is written — all in one transaction so a batch is never half-applied.
+func (s *PayoutService) CreatePayoutBatch(ctx context.Context, adminUID string) (*api.PayoutBatch, error) {
+	batchID := uuid.NewString()
+	batchRef := s.firestore.Collection("payoutBatches").Doc(batchID)
+	var batch api.PayoutBatch
+
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		q := s.firestore.Collection("payouts").
+			Where("status", "==", string(api.PayoutStatusApproved)).
+			Limit(maxBatchSize)
+		snaps, err := tx.Documents(q).GetAll()
+		if err != nil { return err }
+		if len(snaps) == 0 {
+			return ErrNoApprovedPayouts
+		}
+
+		payouts := make([]api.Payout, len(snaps))
+		owed := map[string]float64{} // courier ID → total paid out in this batch
+		for i, snap := range snaps {
+			err = snap.DataTo(&payouts[i])
+			if err != nil { return err }
+			owed[payouts[i].CourierId] += payouts[i].Amount
+		}
+
+		// read every affected courier before writing anything
+		couriers := map[string]api.CourierUser{}
+		for courierID := range owed {
+			courierSnap, err := tx.Get(s.firestore.Collection("users").Doc(courierID))
+			if err != nil { return err }
+			var courier api.CourierUser
+			err = courierSnap.DataTo(&courier)
+			if err != nil { return err }
+			couriers[courierID] = courier
+		}
+
+		for courierID, amount := range owed {
+			courier := couriers[courierID]
+			err = tx.Update(s.firestore.Collection("users").Doc(courierID), []firestore.Update{
+				{Path: "balance", Value: roundCents(courier.Balance - amount)},
+				{Path: "reservedBalance", Value: math.Max(0, roundCents(courier.ReservedBalance-amount))},
+			})
+			if err != nil { return err }
+		}
+
+		now := time.Now().UTC()
+		total := 0.0
+		ids := make([]string, 0, len(snaps))
+		for i, snap := range snaps {
+			p := payouts[i]
+			payoutID := snap.Ref.ID
+			err = tx.Update(snap.Ref, []firestore.Update{
+				{Path: "status", Value: string(api.PayoutStatusPaid)},
+				{Path: "batchId", Value: batchID},
+			})
+			if err != nil { return err }
+
+			err = addLedgerEntry(tx, s.firestore.Client, LedgerEntry{
+				CourierId: p.CourierId,
+				Kind:      LedgerPayout,
+				Amount:    -p.Amount,
+				PayoutId:  &payoutID,
+				Note:      "payout batch " + batchID,
+				CreatedAt: now,
+			})
+			if err != nil { return err }
+
+			total += p.Amount
+			ids = append(ids, payoutID)
+		}
+
+		batch = api.PayoutBatch{
+			Id:        &batchID,
+			PayoutIds: ids,
+			Count:     len(ids),
+			Total:     roundCents(total),
+			CreatedBy: adminUID,
+			CreatedAt: &now,
+		}
+		return tx.Create(batchRef, batch)
+	})
+	if err != nil { return nil, err }
+	return &batch, nil
+}
+
+// getBatchWithPayouts loads a batch and the payouts it settled, in batch order.
+func (s *PayoutService) getBatchWithPayouts(ctx context.Context, batchID string) (*api.PayoutBatch, []*api.Payout, error) {
+	snap, err := s.firestore.Collection("payoutBatches").Doc(batchID).Get(ctx)
+	if err != nil { return nil, nil, err }
+
+	var batch api.PayoutBatch
+	err = snap.DataTo(&batch)
+	if err != nil { return nil, nil, err }
+
+	refs := make([]*firestore.DocumentRef, len(batch.PayoutIds))
+	for i, id := range batch.PayoutIds {
+		refs[i] = s.firestore.Collection("payouts").Doc(id)
+	}
+	snaps, err := s.firestore.GetAll(ctx, refs)
+	if err != nil { return nil, nil, err }
+
+	payouts := make([]*api.Payout, 0, len(snaps))
+	for _, ps := range snaps {
+		var p api.Payout
+		if err := ps.DataTo(&p); err != nil { return nil, nil, err }
+		id := ps.Ref.ID
+		p.Id = &id
+		payouts = append(payouts, &p)
+	}
+	return &batch, payouts, nil
+}
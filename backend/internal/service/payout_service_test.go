@@ -0,0 +1,35 @@
+package service
+
+import "testing"
+
+func TestNormalizeIBAN(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string // "" when invalid
+	}{
+		{"german", "DE89370400440532013000", "DE89370400440532013000"},
+		{"british, letters in the BBAN", "GB82WEST12345698765432", "GB82WEST12345698765432"},
+		{"dutch", "NL91ABNA0417164300", "NL91ABNA0417164300"},
+		{"shortest country, Norway", "NO9386011117947", "NO9386011117947"},
+		{"printed form", "de89 3704 0044 0532 0130 00", "DE89370400440532013000"},
+		{"wrong check digits", "DE88370400440532013000", ""},
+		{"one digit off", "DE89370400440532013001", ""},
+		{"swapped digits", "DE89370400440532010300", ""},
+		{"too short", "DE893704004405", ""},
+		{"too long", "DE8937040044053201300000000000000000", ""},
+		{"dashes", "DE89-3704-0044-0532-0130-00", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeIBAN(tt.raw)
+			if tt.want == "" {
+				if err != ErrInvalidIBAN { t.Fatalf("normalizeIBAN(%q) = %q, %v, want ErrInvalidIBAN", tt.raw, got, err) }
+				return
+			}
+			if err != nil { t.Fatalf("normalizeIBAN(%q): %v", tt.raw, err) }
+			if got != tt.want { t.Errorf("normalizeIBAN(%q) = %q, want %q", tt.raw, got, tt.want) }
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+)
+
+// pricingRates turn a routed trip into a suggested payment:
+// base + perKm·distance + perMinute·duration, never below min.
+type pricingRates struct {
+	base      float64 // env PRICING_BASE
+	perKm     float64 // env PRICING_PER_KM
+	perMinute float64 // env PRICING_PER_MINUTE
+	min       float64 // env PRICING_MIN
+}
+
+func loadPricingRates() pricingRates {
+	return pricingRates{
+		base:      config.Float("PRICING_BASE", 2.5),
+		perKm:     config.Float("PRICING_PER_KM", 1.0),
+		perMinute: config.Float("PRICING_PER_MINUTE", 0.2),
+		min:       config.Float("PRICING_MIN", 4),
+	}
+}
+
+var ErrInvalidLocation = errors.New("locations need a latitude within ±90 and a longitude within ±180")
+
+func validLocation(p api.GeoPoint) bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// POST /deliveries/quote
+// QuoteDelivery routes pickup → destination and prices the trip, so a business can see
+// what a delivery will take before posting it. The payment stays the business's choice.
+// The destination's service zone may set its own rates and a minimum payment.
+func (s *DeliveryService) QuoteDelivery(ctx context.Context, req *api.DeliveryQuoteRequest) (*api.DeliveryQuote, error) {
+	if !validLocation(req.BusinessLocation) || !validLocation(req.DestinationLocation) { return nil, ErrInvalidLocation }
+
+	z, err := s.zones.locate(ctx, req.DestinationLocation)
+	if err != nil { return nil, err }
+	rates := s.pricing
+	if z != nil { rates = z.rates(rates) }
+
+	route, err := s.router.Route(ctx, req.BusinessLocation, req.DestinationLocation)
+	if err != nil { return nil, err }
+	minutes := route.Duration.Minutes()
+	payment := math.Max(rates.base+rates.perKm*route.DistanceKm+rates.perMinute*minutes, rates.min)
+	quote := &api.DeliveryQuote{
+		DistanceKm:      math.Round(route.DistanceKm*100) / 100,
+		DurationMinutes: math.Round(minutes*10) / 10,
+		Polyline:        route.Polyline,
+		Provider:        route.Provider,
+	}
+	if z != nil {
+		if z.MinPayment != nil { payment = math.Max(payment, *z.MinPayment) }
+		quote.ZoneId = &z.Id
+	}
+	quote.Payment = roundCents(payment)
+	return quote, nil
+}
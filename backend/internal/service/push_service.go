@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PushService sends Web Push messages to couriers' browsers about new deliveries
+// posted near them, so they don't have to keep the dashboard open.
+//
+// Subscriptions live in /couriers/{uid}/pushSubscriptions/{id}, one per browser; the id
+// is derived from the endpoint, so re-subscribing a browser replaces its entry.
+// Pushes are best effort: a failing browser is logged and skipped, one its push service
+// reports gone (404/410) is unsubscribed.
+type PushService struct {
+	firestore     *db.FirestoreClient
+	vapid         *vapidKey     // nil when VAPID keys aren't configured: push is off
+	subject       string        // env VAPID_SUBJECT, contact for push services
+	defaultRadius float64       // env PUSH_DEFAULT_RADIUS_KM
+	ttl           time.Duration // env PUSH_TTL, how long a push service keeps an undelivered job push
+	locationAge   time.Duration // env PUSH_LOCATION_MAX_AGE, couriers last seen before this aren't pushed
+	client        *http.Client
+}
+
+// NewPushService wires Firestore and the VAPID key pair (VAPID_PUBLIC_KEY, VAPID_PRIVATE_KEY)
+// into the push domain. Without a private key push stays off; a malformed key is an error.
+// called once from main.go at startup
+func NewPushService(fs *db.FirestoreClient) (*PushService, error) {
+	s := &PushService{
+		firestore:     fs,
+		subject:       config.String("VAPID_SUBJECT", "mailto:admin@localhost"),
+		defaultRadius: config.Float("PUSH_DEFAULT_RADIUS_KM", 5),
+		ttl:           config.Duration("PUSH_TTL", 15*time.Minute),
+		locationAge:   config.Duration("PUSH_LOCATION_MAX_AGE", 30*time.Minute),
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}
+	if priv := config.String("VAPID_PRIVATE_KEY", ""); priv != "" {
+		key, err := parseVAPIDKey(config.String("VAPID_PUBLIC_KEY", ""), priv)
+		if err != nil { return nil, err }
+		s.vapid = key
+	}
+	return s, nil
+}
+
+var ErrPushDisabled = errors.New("web push is not configured on this server")
+
+var ErrInvalidPushSubscription = errors.New("push subscription needs an https endpoint, p256dh and auth keys")
+
+var ErrInvalidPushRadius = errors.New("radiusKm must be between 0 and 100")
+
+var ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+
+// maxPushRadiusKm caps the radius couriers may pick, so one job doesn't page a whole region.
+const maxPushRadiusKm = 100
+
+// Enabled reports whether VAPID keys are configured.
+func (s *PushService) Enabled() bool { return s.vapid != nil }
+
+// GET /push/vapid-public-key
+func (s *PushService) VAPIDPublicKey() (string, error) {
+	if !s.Enabled() { return "", ErrPushDisabled }
+	return s.vapid.public, nil
+}
+
+func (s *PushService) subscriptions(courierUID string) *firestore.CollectionRef {
+	return s.firestore.Collection("couriers").Doc(courierUID).Collection("pushSubscriptions")
+}
+
+// POST /couriers/me/push-subscriptions
+// CreateSubscription stores (or replaces) a browser subscription of the courier.
+func (s *PushService) CreateSubscription(ctx context.Context, courierUID string, req *api.PushSubscriptionCreate) (*api.PushSubscription, error) {
+	if !s.Enabled() { return nil, ErrPushDisabled }
+	u, err := url.Parse(req.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" { return nil, ErrInvalidPushSubscription }
+	// a test encryption validates both keys the way a real push would use them
+	if _, err := encryptWebPush(req.Keys.P256dh, req.Keys.Auth, []byte("{}")); err != nil {
+		return nil, ErrInvalidPushSubscription
+	}
+	radius := s.defaultRadius
+	if req.RadiusKm != nil { radius = *req.RadiusKm }
+	if radius <= 0 || radius > maxPushRadiusKm { return nil, ErrInvalidPushRadius }
+
+	sum := sha256.Sum256([]byte(req.Endpoint))
+	id := hex.EncodeToString(sum[:12])
+	now := time.Now().UTC()
+	sub := api.PushSubscription{
+		Id:        &id,
+		Endpoint:  req.Endpoint,
+		Keys:      req.Keys,
+		RadiusKm:  radius,
+		CreatedAt: &now,
+	}
+	_, err = s.subscriptions(courierUID).Doc(id).Set(ctx, sub)
+	if err != nil { return nil, err }
+	return &sub, nil
+}
+
+// GET /couriers/me/push-subscriptions
+func (s *PushService) ListSubscriptions(ctx context.Context, courierUID string) ([]*api.PushSubscription, error) {
+	iter := s.subscriptions(courierUID).OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	subs := []*api.PushSubscription{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var sub api.PushSubscription
+		if err := doc.DataTo(&sub); err != nil { return nil, err }
+		subs = append(subs, &sub)
+	}
+	return subs, nil
+}
+
+// DELETE /couriers/me/push-subscriptions/{id}
+func (s *PushService) DeleteSubscription(ctx context.Context, courierUID, id string) error {
+	ref := s.subscriptions(courierUID).Doc(id)
+	if _, err := ref.Get(ctx); err != nil {
+		if status.Code(err) == codes.NotFound { return ErrPushSubscriptionNotFound }
+		return err
+	}
+	_, err := ref.Delete(ctx)
+	return err
+}
+
+// pushMessage is the JSON the courier app's service worker receives.
+type pushMessage struct {
+	Type       string  `json:"type"`
+	DeliveryId string  `json:"deliveryId"`
+	Title      string  `json:"title"`
+	Body       string  `json:"body"`
+	DistanceKm float64 `json:"distanceKm"`
+	Payment    float64 `json:"payment"`
+}
+
+// Name identifies web push as an outbox sink.
+func (s *PushService) Name() string { return "webpush" }
+
+// Deliver is the outbox sink: a newly posted delivery is pushed to every subscribed
+// courier whose last known position, reported within locationAge, lies within their
+// subscription's radius of the pickup. Scheduled deliveries are pushed when they are released.
+func (s *PushService) Deliver(ctx context.Context, e *DeliveryEvent) error {
+	if !s.Enabled() || !jobPosted(e) { return nil }
+	pickup := e.Delivery.BusinessLocation
+
+	iter := s.firestore.CollectionGroup("pushSubscriptions").Documents(ctx)
+	defer iter.Stop()
+
+	// last known positions, looked up once per courier
+	positions := map[string]*CourierLocation{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return err }
+
+		courierUID := doc.Ref.Parent.Parent.ID
+		loc, seen := positions[courierUID]
+		if !seen {
+			loc, err = lastCourierLocation(ctx, s.firestore, courierUID)
+			if err != nil { return err }
+			// a position from yesterday says nothing about where the courier is now
+			if loc != nil && time.Since(loc.UpdatedAt) > s.locationAge { loc = nil }
+			positions[courierUID] = loc
+		}
+		if loc == nil { continue }
+
+		var sub api.PushSubscription
+		if err := doc.DataTo(&sub); err != nil { continue }
+		dist := geoDistanceKm(pickup.Lat, pickup.Lng, loc.Lat, loc.Lng)
+		if dist > sub.RadiusKm { continue }
+
+		msg := pushMessage{
+			Type:       "job.posted",
+			DeliveryId: e.DeliveryId,
+			Title:      fmt.Sprintf("New job %.1f km away", dist),
+			Body:       fmt.Sprintf("%s: %s, pays %.2f", e.Delivery.BusinessName, e.Delivery.Item, e.Delivery.Payment),
+			DistanceKm: dist,
+			Payment:    e.Delivery.Payment,
+		}
+		gone, err := s.push(ctx, &sub, e.DeliveryId, msg)
+		if gone {
+			_, err = doc.Ref.Delete(ctx)
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("web push to courier %s: %v", courierUID, err)
+		}
+	}
+	return ctx.Err()
+}
+
+// push encrypts msg for sub and hands it to the browser's push service.
+// gone reports that the subscription no longer exists and should be dropped.
+func (s *PushService) push(ctx context.Context, sub *api.PushSubscription, topic string, msg pushMessage) (gone bool, err error) {
+	plain, err := json.Marshal(msg)
+	if err != nil { return false, err }
+	body, err := encryptWebPush(sub.Keys.P256dh, sub.Keys.Auth, plain)
+	if err != nil { return false, err }
+	auth, err := s.vapid.authorization(sub.Endpoint, s.subject)
+	if err != nil { return false, err }
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil { return false, err }
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(s.ttl.Seconds())))
+	req.Header.Set("Urgency", "high")
+	// a redelivered event replaces the pending push instead of adding a second one
+	req.Header.Set("Topic", pushTopic(topic))
+
+	resp, err := s.client.Do(req)
+	if err != nil { return false, err }
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return true, nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return false, fmt.Errorf("push service answered %s", resp.Status)
+	}
+	return false, nil
+}
+
+// pushTopic makes a valid Topic header (at most 32 URL-safe base64 characters) from an id.
+func pushTopic(id string) string {
+	t := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return -1
+	}, id)
+	if len(t) > 32 { t = t[:32] }
+	return t
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRecurrence = errors.New("recurrence must be a five-field cron expression with an upcoming time, in a known IANA time zone")
+
+// cronSchedule is a parsed "minute hour day-of-month month day-of-week" expression;
+// each field is a bitset of the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool // field was *, so only the other day field counts
+}
+
+// parseCron parses a standard five-field cron expression. Fields take *, numbers,
+// ranges (a-b), lists (a,b) and steps (*/n, a-b/n); day-of-week 7 is Sunday like 0.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 { return nil, ErrInvalidRecurrence }
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]uint64
+	for i, f := range fields {
+		set, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil { return nil, err }
+		sets[i] = set
+	}
+	// Sunday is both 0 and 7
+	if sets[4]&(1<<7) != 0 { sets[4] |= 1 }
+	return &cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: strings.HasPrefix(fields[2], "*"),
+		dowAny: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 { return 0, ErrInvalidRecurrence }
+			rng, step = part[:i], n
+		}
+		from, to := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			from, err = strconv.Atoi(a)
+			if err != nil { return 0, ErrInvalidRecurrence }
+			to = from
+			if isRange {
+				to, err = strconv.Atoi(b)
+				if err != nil { return 0, ErrInvalidRecurrence }
+			} else if step > 1 {
+				to = hi // "5/15" runs from 5 to the end
+			}
+		}
+		if from < lo || to > hi || from > to { return 0, ErrInvalidRecurrence }
+		for v := from; v <= to; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// dayMatches applies cron's rule for the two day fields: when both are restricted,
+// a day matching either one counts.
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// next is the first matching minute after `after`, in loc; false when there is none
+// within five years (e.g. "0 0 30 2 *"). Fields are matched on the wall clock, so a time
+// the clocks skip in spring fires right after the gap instead of that day being lost, and
+// one they repeat in autumn fires once.
+func (c *cronSchedule) next(after time.Time, loc *time.Location) (time.Time, bool) {
+	local := after.In(loc)
+	// the wall clock read as UTC, which has no gaps or repeats to trip over
+	t := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), 0, 0, time.UTC).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case c.month&(1<<int(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.dayMatches(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<t.Hour()) == 0:
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, time.UTC)
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			at := time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, loc)
+			if at.After(after) { return at, true }
+			t = t.Add(time.Minute) // a repeated hour's second pass, already behind us
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+		minute  uint64 // checked when non-zero
+		dow     uint64
+	}{
+		{expr: "* * * * *"},
+		{expr: "30 7 * * 1-5", minute: 1 << 30, dow: 1<<1 | 1<<2 | 1<<3 | 1<<4 | 1<<5},
+		{expr: "*/15 * * * *", minute: 1<<0 | 1<<15 | 1<<30 | 1<<45},
+		{expr: "5/20 * * * *", minute: 1<<5 | 1<<25 | 1<<45},
+		{expr: "0,10-12 * * * *", minute: 1<<0 | 1<<10 | 1<<11 | 1<<12},
+		{expr: "0 0 * * 7", dow: 1<<0 | 1<<7}, // Sunday is 0 and 7
+		{expr: "0 0 1,15 * *"},
+		{expr: "  0   0 * *   * "},
+		{expr: "", wantErr: true},
+		{expr: "* * * *", wantErr: true},
+		{expr: "* * * * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "* 24 * * *", wantErr: true},
+		{expr: "* * 0 * *", wantErr: true},
+		{expr: "* * 32 * *", wantErr: true},
+		{expr: "* * * 13 *", wantErr: true},
+		{expr: "* * * * 8", wantErr: true},
+		{expr: "5-1 * * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "a * * * *", wantErr: true},
+		{expr: "1-x * * * *", wantErr: true},
+		{expr: "1, * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := parseCron(tt.expr)
+			if tt.wantErr {
+				if err != ErrInvalidRecurrence { t.Fatalf("parseCron(%q) = %v, want ErrInvalidRecurrence", tt.expr, err) }
+				return
+			}
+			if err != nil { t.Fatalf("parseCron(%q): %v", tt.expr, err) }
+			if tt.minute != 0 && c.minute != tt.minute { t.Errorf("minute = %b, want %b", c.minute, tt.minute) }
+			if tt.dow != 0 && c.dow != tt.dow { t.Errorf("dow = %b, want %b", c.dow, tt.dow) }
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil { t.Skip("no time zone data:", err) }
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil { t.Skip("no time zone data:", err) }
+
+	tests := []struct {
+		name  string
+		expr  string
+		loc   *time.Location
+		after time.Time
+		want  time.Time // zero: no next time
+	}{
+		{"every minute", "* * * * *", time.UTC,
+			time.Date(2025, 5, 1, 10, 0, 30, 0, time.UTC), time.Date(2025, 5, 1, 10, 1, 0, 0, time.UTC)},
+		{"strictly after", "0 8 * * *", time.UTC,
+			time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC), time.Date(2025, 5, 2, 8, 0, 0, 0, time.UTC)},
+		{"weekdays skip the weekend", "30 7 * * 1-5", time.UTC,
+			time.Date(2025, 5, 2, 8, 0, 0, 0, time.UTC), time.Date(2025, 5, 5, 7, 30, 0, 0, time.UTC)},
+		{"day of month or weekday", "0 9 13 * 5", time.UTC,
+			time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 6, 9, 0, 0, 0, time.UTC)},
+		{"31st skips 30-day months", "0 0 31 * *", time.UTC,
+			time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)},
+		{"last minute of January", "59 23 * * *", time.UTC,
+			time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC), time.Date(2025, 2, 1, 23, 59, 0, 0, time.UTC)},
+		{"year end", "0 0 1 * *", time.UTC,
+			time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"29 February waits for a leap year", "0 12 29 2 *", time.UTC,
+			time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"30 February never comes", "0 0 30 2 *", time.UTC,
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}},
+		{"local time zone", "0 9 * * *", berlin,
+			time.Date(2025, 7, 1, 6, 0, 0, 0, time.UTC), time.Date(2025, 7, 1, 7, 0, 0, 0, time.UTC)},
+		{"local date differs from UTC", "0 1 * * *", newYork,
+			time.Date(2025, 7, 1, 3, 0, 0, 0, time.UTC), time.Date(2025, 7, 1, 5, 0, 0, 0, time.UTC)},
+		{"spring gap fires right after it", "30 2 * * *", berlin,
+			time.Date(2025, 3, 29, 12, 0, 0, 0, berlin), time.Date(2025, 3, 30, 3, 30, 0, 0, berlin)},
+		{"day after the spring gap", "30 2 * * *", berlin,
+			time.Date(2025, 3, 30, 3, 30, 0, 0, berlin), time.Date(2025, 3, 31, 2, 30, 0, 0, berlin)},
+		{"after the spring gap", "0 3 * * *", berlin,
+			time.Date(2025, 3, 30, 1, 59, 0, 0, berlin), time.Date(2025, 3, 30, 3, 0, 0, 0, berlin)},
+		{"repeated autumn hour fires once", "30 2 * * *", berlin,
+			time.Date(2025, 10, 26, 0, 30, 0, 0, time.UTC), time.Date(2025, 10, 27, 1, 30, 0, 0, time.UTC)},
+		{"autumn day", "0 12 * * *", berlin,
+			time.Date(2025, 10, 25, 12, 0, 0, 0, berlin), time.Date(2025, 10, 26, 11, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := parseCron(tt.expr)
+			if err != nil { t.Fatalf("parseCron(%q): %v", tt.expr, err) }
+			got, ok := c.next(tt.after, tt.loc)
+			if tt.want.IsZero() {
+				if ok { t.Fatalf("next = %v, want none", got) }
+				return
+			}
+			if !ok { t.Fatalf("next = none, want %v", tt.want) }
+			if !got.Equal(tt.want) { t.Errorf("next = %v, want %v", got, tt.want.In(tt.loc)) }
+		})
+	}
+}
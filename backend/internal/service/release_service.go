@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"google.golang.org/api/iterator"
+)
+
+// ReleaseService announces scheduled deliveries once couriers may see them. A delivery
+// posted with a releaseAt ahead is hidden from couriers until then, so its delivery.created
+// reaches none of them; at releaseAt a delivery.released event is emitted instead, which the
+// notification and push sinks and the couriers' event streams take as the job being posted.
+type ReleaseService struct {
+	firestore *db.FirestoreClient
+}
+
+// NewReleaseService wires Firestore into the release job.
+// called once from main.go at startup
+func NewReleaseService(fs *db.FirestoreClient) *ReleaseService {
+	return &ReleaseService{firestore: fs}
+}
+
+// due reports whether d is a posted delivery whose releaseAt passed without an announcement.
+func (s *ReleaseService) due(d *api.Delivery, now time.Time) bool {
+	return d.Status == StatusPosted && d.AssignedTo == nil && d.ReleaseAt != nil && d.ReleasedAt == nil && !now.Before(*d.ReleaseAt)
+}
+
+// Sweep announces every scheduled delivery whose time has come and returns how many it
+// released. One delivery failing doesn't hold up the others.
+func (s *ReleaseService) Sweep(ctx context.Context, now time.Time) (int, error) {
+	iter := s.firestore.Collection("deliveries").
+		Where("status", "==", StatusPosted).
+		Where("releaseAt", "<=", now).
+		Documents(ctx)
+	defer iter.Stop()
+
+	released := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return released, err }
+
+		var d api.Delivery
+		if err := doc.DataTo(&d); err != nil || !s.due(&d, now) { continue }
+		ok, err := s.release(ctx, doc.Ref, now)
+		if err != nil {
+			log.Printf("release of delivery %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		if ok { released++ }
+	}
+	return released, nil
+}
+
+// release re-checks the delivery in a transaction, so one released by another instance or
+// accepted meanwhile is left alone, then marks it released and emits delivery.released.
+func (s *ReleaseService) release(ctx context.Context, ref *firestore.DocumentRef, now time.Time) (bool, error) {
+	changed := false
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		changed = false
+		snap, err := tx.Get(ref)
+		if err != nil { return err }
+		var d api.Delivery
+		err = snap.DataTo(&d)
+		if err != nil { return err }
+		if !s.due(&d, now) { return nil }
+
+		before := d
+		d.ReleasedAt = &now
+		err = tx.Set(ref, d)
+		if err != nil { return err }
+		changed = true
+		return addDeliveryEvent(tx, s.firestore.Client, EventDeliveryReleased, ref.ID, &before, d)
+	})
+	return changed, err
+}
+
+// Run sweeps now and then every `every`, until ctx is cancelled.
+func (s *ReleaseService) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		released, err := s.Sweep(ctx, time.Now().UTC())
+		if err != nil {
+			log.Printf("release: %v", err)
+		} else if released > 0 {
+			log.Printf("release: %d scheduled deliveries opened to couriers", released)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
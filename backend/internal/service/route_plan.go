@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"google.golang.org/api/iterator"
+)
+
+// planStop is one pickup or drop-off to visit; a drop-off whose parcel is still at the
+// business points at its pickup, which has to come first.
+type planStop struct {
+	stop   api.CourierRouteStop
+	pickup int // index of this drop-off's pickup in the stop list, -1 if none
+}
+
+// maxPlanPasses bounds the improvement loop; each pass is O(n³) and n is a courier's load.
+const maxPlanPasses = 50
+
+// unreachableCost is what planOrder charges for a leg the router found no way for, in
+// seconds: more than any real ride, so orders with fewer such legs always win.
+const unreachableCost = 1e12
+
+var ErrUnreachableStops = errors.New("some of the stops can't be reached from each other by road")
+
+// GET /couriers/me/route
+// PlanCourierRoute orders the pickups and drop-offs of every delivery the courier is on,
+// starting at their last known position. It is a pickup-and-delivery TSP solved
+// heuristically: nearest feasible neighbour first, then moving single stops and reversing
+// segments while that shortens the total travel time and keeps pickups before drop-offs.
+// When no order avoids a leg the router can't ride, there is no plan: ErrUnreachableStops.
+func (s *DeliveryService) PlanCourierRoute(ctx context.Context, courierUID string) (*api.CourierRoute, error) {
+	stops, err := s.routeStops(ctx, courierUID)
+	if err != nil { return nil, err }
+	loc, err := lastCourierLocation(ctx, s.firestore, courierUID)
+	if err != nil { return nil, err }
+
+	plan := &api.CourierRoute{Stops: []api.CourierRouteStop{}, Provider: s.router.Name()}
+	var points []api.GeoPoint
+	if loc != nil {
+		plan.Start = &api.GeoPoint{Lat: loc.Lat, Lng: loc.Lng}
+		points = append(points, *plan.Start)
+	}
+	if len(stops) == 0 { return plan, nil }
+	for _, st := range stops {
+		points = append(points, st.stop.Location)
+	}
+
+	table, err := s.router.Table(ctx, points, points)
+	if err != nil { return nil, err }
+	order := planOrder(stops, table, loc != nil)
+	if _, _, _, ok := tableStops(stops, order, table, loc != nil); !ok { return nil, ErrUnreachableStops }
+
+	// the final sequence is routed as a whole for the geometry and per-leg figures
+	var waypoints []api.GeoPoint
+	if plan.Start != nil { waypoints = append(waypoints, *plan.Start) }
+	for _, i := range order {
+		waypoints = append(waypoints, stops[i].stop.Location)
+	}
+	if len(waypoints) < 2 {
+		plan.Stops = append(plan.Stops, stops[order[0]].stop)
+		plan.Polyline = encodePolyline(waypoints)
+		return plan, nil
+	}
+	route, err := s.router.Route(ctx, waypoints...)
+	if err != nil { return nil, err }
+
+	plan.Provider = route.Provider
+	plan.Polyline = route.Polyline
+	plan.DistanceKm = math.Round(route.DistanceKm*100) / 100
+	plan.DurationMinutes = math.Round(route.Duration.Minutes()*10) / 10
+	if plan.Start == nil {
+		plan.Stops = append(plan.Stops, stops[order[0]].stop) // nothing to ride to the first stop
+		order = order[1:]
+	}
+	// route.Legs[k] ends at order[k]
+	for k, i := range order {
+		st := stops[i].stop
+		if k < len(route.Legs) {
+			leg := route.Legs[k]
+			st.LegDistanceKm = math.Round(leg.DistanceKm*100) / 100
+			st.LegDurationMinutes = math.Round(leg.Duration.Minutes()*10) / 10
+		}
+		plan.Stops = append(plan.Stops, st)
+	}
+	return plan, nil
+}
+
+// routeStops lists the stops left on the courier's accepted and picked-up deliveries.
+func (s *DeliveryService) routeStops(ctx context.Context, courierUID string) ([]planStop, error) {
+	iter := s.firestore.Collection("deliveries").
+		Where("assignedTo", "==", courierUID).
+		Where("status", "in", []string{StatusAccepted, StatusPickedUp}).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var list []api.Delivery
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var d api.Delivery
+		if err := doc.DataTo(&d); err != nil { continue }
+		id := doc.Ref.ID
+		d.Id = &id
+		list = append(list, d)
+	}
+	// a stable input keeps the suggested order stable between calls
+	sort.Slice(list, func(i, j int) bool { return *list[i].Id < *list[j].Id })
+	return deliveryStops(list), nil
+}
+
+// deliveryStops lists the stops left on each delivery: pickup and drop-off while accepted
+// (or still posted), only the drop-off once picked up.
+func deliveryStops(list []api.Delivery) []planStop {
+	var stops []planStop
+	for _, d := range list {
+		pickup := -1
+		if d.Status == StatusAccepted || d.Status == StatusPosted {
+			pickup = len(stops)
+			stops = append(stops, planStop{pickup: -1, stop: api.CourierRouteStop{
+				DeliveryId: *d.Id, Type: api.Pickup, Item: d.Item,
+				Address: d.BusinessAddress, Location: d.BusinessLocation,
+			}})
+		}
+		stops = append(stops, planStop{pickup: pickup, stop: api.CourierRouteStop{
+			DeliveryId: *d.Id, Type: api.Dropoff, Item: d.Item,
+			Address: d.DestinationAddress, Location: d.DestinationLocation,
+		}})
+	}
+	return stops
+}
+
+// planOrder returns the visiting order of stops (indexes into stops). table holds the
+// legs between all points: the start first when hasStart, then the stops in order.
+func planOrder(stops []planStop, table [][]RouteLeg, hasStart bool) []int {
+	off := 0
+	if hasStart { off = 1 }
+	cost := func(order []int) float64 {
+		total := 0.0
+		prev := -1
+		if hasStart { prev = 0 }
+		for _, i := range order {
+			if prev >= 0 && !table[prev][i+off].reachable() {
+				total += unreachableCost
+			} else if prev >= 0 {
+				total += table[prev][i+off].Duration.Seconds()
+			}
+			prev = i + off
+		}
+		return total
+	}
+
+	// nearest feasible neighbour; without a start every stop is tried as the first one
+	firsts := []int{-1}
+	if !hasStart {
+		firsts = firsts[:0]
+		for i, st := range stops {
+			if st.pickup < 0 { firsts = append(firsts, i) }
+		}
+	}
+	var best []int
+	for _, first := range firsts {
+		order := nearestNeighbour(stops, table, off, first)
+		if best == nil || cost(order) < cost(best) { best = order }
+	}
+
+	// local search: relocate one stop, or reverse a segment, while it pays off
+	for pass := 0; pass < maxPlanPasses; pass++ {
+		improved := false
+		for i := range best {
+			for j := range best {
+				if i == j { continue }
+				cand := relocate(best, i, j)
+				if feasibleOrder(stops, cand) && cost(cand) < cost(best)-1e-9 {
+					best, improved = cand, true
+				}
+			}
+		}
+		for i := 0; i < len(best)-1; i++ {
+			for j := i + 1; j < len(best); j++ {
+				cand := reverseSegment(best, i, j)
+				if feasibleOrder(stops, cand) && cost(cand) < cost(best)-1e-9 {
+					best, improved = cand, true
+				}
+			}
+		}
+		if !improved { break }
+	}
+	return best
+}
+
+// tableStops lays the stops out in order with their legs taken from table (indexed like
+// planOrder's), and returns the totals of the ride. ok is false when a leg is unreachable;
+// the totals then leave it out.
+func tableStops(stops []planStop, order []int, table [][]RouteLeg, hasStart bool) (out []api.CourierRouteStop, km float64, dur time.Duration, ok bool) {
+	off := 0
+	if hasStart { off = 1 }
+	out = make([]api.CourierRouteStop, 0, len(order))
+	ok = true
+	prev := -1
+	if hasStart { prev = 0 }
+	for _, i := range order {
+		st := stops[i].stop
+		if prev >= 0 && !table[prev][i+off].reachable() {
+			ok = false
+		} else if prev >= 0 {
+			leg := table[prev][i+off]
+			km += leg.DistanceKm
+			dur += leg.Duration
+			st.LegDistanceKm = math.Round(leg.DistanceKm*100) / 100
+			st.LegDurationMinutes = math.Round(leg.Duration.Minutes()*10) / 10
+		}
+		out = append(out, st)
+		prev = i + off
+	}
+	return out, km, dur, ok
+}
+
+// nearestNeighbour builds an order by always riding to the closest stop that may be
+// visited next, starting at stop first (or at the start point when first is -1).
+func nearestNeighbour(stops []planStop, table [][]RouteLeg, off, first int) []int {
+	visited := make([]bool, len(stops))
+	order := make([]int, 0, len(stops))
+	prev := 0 // the start point
+	if first >= 0 {
+		visited[first] = true
+		order = append(order, first)
+		prev = first + off
+	}
+	for len(order) < len(stops) {
+		next := -1
+		for i, st := range stops {
+			if visited[i] || (st.pickup >= 0 && !visited[st.pickup]) { continue }
+			if next < 0 || table[prev][i+off].Duration < table[prev][next+off].Duration { next = i }
+		}
+		visited[next] = true
+		order = append(order, next)
+		prev = next + off
+	}
+	return order
+}
+
+// feasibleOrder reports whether every drop-off comes after its pickup.
+func feasibleOrder(stops []planStop, order []int) bool {
+	pos := make([]int, len(stops))
+	for p, i := range order {
+		pos[i] = p
+	}
+	for i, st := range stops {
+		if st.pickup >= 0 && pos[st.pickup] > pos[i] { return false }
+	}
+	return true
+}
+
+// relocate moves the stop at position i to position j.
+func relocate(order []int, i, j int) []int {
+	out := make([]int, 0, len(order))
+	out = append(out, order[:i]...)
+	out = append(out, order[i+1:]...)
+	out = append(out[:j], append([]int{order[i]}, out[j:]...)...)
+	return out
+}
+
+// reverseSegment reverses positions i..j.
+func reverseSegment(order []int, i, j int) []int {
+	out := append([]int(nil), order...)
+	for ; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+// lineTable is a travel table for points on a line, one minute and one km per unit,
+// with the legs listed in unreachable ([from, to] point indexes) cut.
+func lineTable(pos []float64, unreachable ...[2]int) [][]RouteLeg {
+	table := make([][]RouteLeg, len(pos))
+	for i := range pos {
+		table[i] = make([]RouteLeg, len(pos))
+		for j := range pos {
+			d := math.Abs(pos[i] - pos[j])
+			table[i][j] = RouteLeg{DistanceKm: d, Duration: time.Duration(d * float64(time.Minute))}
+		}
+	}
+	for _, u := range unreachable {
+		table[u[0]][u[1]] = unreachableLeg
+	}
+	return table
+}
+
+func TestPlanOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		pickups     []int     // per stop: index of its pickup, -1 for none
+		pos         []float64 // the start first when hasStart, then the stops
+		hasStart    bool
+		unreachable [][2]int
+		want        []int
+		wantMinutes float64
+	}{
+		{"one delivery", []int{-1, 0}, []float64{0, 1, 2}, true, nil, []int{0, 1}, 2},
+		{"pickup before a closer drop-off", []int{-1, 0}, []float64{0, 10, 1}, true, nil, []int{0, 1}, 19},
+		{"two deliveries along the way", []int{-1, 0, -1, 2}, []float64{0, 1, 3, 2, 4}, true, nil, []int{0, 2, 1, 3}, 4},
+		{"drop-offs only, nearest first", []int{-1, -1, -1}, []float64{0, 5, 1, 3}, true, nil, []int{1, 2, 0}, 5},
+		{"no start picks the best first stop", []int{-1, -1, -1}, []float64{5, 1, 3}, false, nil, nil, 4},
+		{"no start still picks up first", []int{-1, 0}, []float64{3, 0}, false, nil, []int{0, 1}, 3},
+		{"avoids an unreachable leg", []int{-1, -1}, []float64{0, 1, 2}, true, [][2]int{{1, 2}}, []int{1, 0}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stops := make([]planStop, len(tt.pickups))
+			for i, p := range tt.pickups {
+				stops[i] = planStop{pickup: p}
+			}
+			table := lineTable(tt.pos, tt.unreachable...)
+
+			order := planOrder(stops, table, tt.hasStart)
+			if len(order) != len(stops) { t.Fatalf("order %v doesn't visit all %d stops", order, len(stops)) }
+			seen := slices.Clone(order)
+			slices.Sort(seen)
+			for i, v := range seen {
+				if v != i { t.Fatalf("order %v isn't a permutation of the stops", order) }
+			}
+			if !feasibleOrder(stops, order) { t.Errorf("order %v visits a drop-off before its pickup", order) }
+			if tt.want != nil && !slices.Equal(order, tt.want) { t.Errorf("order = %v, want %v", order, tt.want) }
+
+			_, _, dur, ok := tableStops(stops, order, table, tt.hasStart)
+			if !ok { t.Fatalf("order %v rides an unreachable leg", order) }
+			if got := dur.Minutes(); math.Abs(got-tt.wantMinutes) > 1e-9 { t.Errorf("ride = %v min, want %v", got, tt.wantMinutes) }
+		})
+	}
+}
+
+func TestTableStopsUnreachable(t *testing.T) {
+	stops := []planStop{{pickup: -1}, {pickup: -1}}
+	table := lineTable([]float64{0, 1, 2}, [2]int{1, 2})
+
+	if _, _, _, ok := tableStops(stops, []int{0, 1}, table, true); ok { t.Error("ok for an order riding the unreachable leg") }
+	_, km, dur, ok := tableStops(stops, []int{1, 0}, table, true)
+	if !ok || km != 3 || dur != 3*time.Minute { t.Errorf("tableStops = %v km, %v, %v; want 3 km, 3m0s, true", km, dur, ok) }
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+)
+
+// Router answers how far and how long a courier travels between points. Pricing, ETAs
+// and distance ranking go through it, so swapping straight lines for a road network is
+// one setting (ROUTER).
+type Router interface {
+	// Name identifies the provider in quotes and logs.
+	Name() string
+	// Route follows the waypoints in order; at least two are needed.
+	Route(ctx context.Context, waypoints ...api.GeoPoint) (*Route, error)
+	// Table returns the leg from every source to every destination, [source][destination].
+	Table(ctx context.Context, sources, destinations []api.GeoPoint) ([][]RouteLeg, error)
+}
+
+// Route is the travel along a list of waypoints.
+type Route struct {
+	DistanceKm float64
+	Duration   time.Duration
+	Polyline   string     // encoded polyline (precision 5) of the whole route
+	Legs       []RouteLeg // one per pair of consecutive waypoints
+	Provider   string     // the Router that answered
+}
+
+// RouteLeg is the travel between two points. An unreachable pair has infinite
+// distance and the maximum duration, so it sorts last.
+type RouteLeg struct {
+	DistanceKm float64
+	Duration   time.Duration
+}
+
+var unreachableLeg = RouteLeg{DistanceKm: math.Inf(1), Duration: time.Duration(math.MaxInt64)}
+
+// reachable reports whether the router found a way for the leg. Unreachable legs must be
+// checked for before adding legs up: their duration overflows any sum.
+func (l RouteLeg) reachable() bool { return !math.IsInf(l.DistanceKm, 1) }
+
+var ErrTooFewWaypoints = errors.New("a route needs at least two waypoints")
+
+// NewRouter builds the router selected by ROUTER (haversine or osrm).
+// OSRM answers fall back to straight lines when the OSRM server fails.
+// called once from main.go at startup
+func NewRouter() (Router, error) {
+	fallback := &HaversineRouter{speedKmh: config.Float("ROUTER_SPEED_KMH", 25)}
+	switch r := config.String("ROUTER", "haversine"); r {
+	case "haversine":
+		return fallback, nil
+	case "osrm":
+		return NewOSRMRouter(
+			config.String("OSRM_URL", "http://localhost:5000"),
+			config.String("OSRM_PROFILE", "driving"),
+			config.Duration("ROUTER_TIMEOUT", 5*time.Second),
+			fallback,
+		)
+	default:
+		return nil, fmt.Errorf("unknown ROUTER %q (want haversine or osrm)", r)
+	}
+}
+
+// HaversineRouter travels in straight lines at a constant speed (env ROUTER_SPEED_KMH).
+// It needs nothing but underestimates real rides; it is also the fallback of OSRM.
+type HaversineRouter struct {
+	speedKmh float64
+}
+
+// Name identifies straight-line routing.
+func (r *HaversineRouter) Name() string { return "haversine" }
+
+// Route joins the waypoints with straight lines.
+func (r *HaversineRouter) Route(ctx context.Context, waypoints ...api.GeoPoint) (*Route, error) {
+	if len(waypoints) < 2 { return nil, ErrTooFewWaypoints }
+	route := &Route{Polyline: encodePolyline(waypoints), Provider: r.Name()}
+	for i := 1; i < len(waypoints); i++ {
+		leg := r.leg(waypoints[i-1], waypoints[i])
+		route.Legs = append(route.Legs, leg)
+		route.DistanceKm += leg.DistanceKm
+		route.Duration += leg.Duration
+	}
+	return route, nil
+}
+
+// Table measures every source-destination pair in a straight line.
+func (r *HaversineRouter) Table(ctx context.Context, sources, destinations []api.GeoPoint) ([][]RouteLeg, error) {
+	out := make([][]RouteLeg, len(sources))
+	for i, a := range sources {
+		out[i] = make([]RouteLeg, len(destinations))
+		for j, b := range destinations {
+			out[i][j] = r.leg(a, b)
+		}
+	}
+	return out, nil
+}
+
+func (r *HaversineRouter) leg(a, b api.GeoPoint) RouteLeg {
+	km := geoDistanceKm(a.Lat, a.Lng, b.Lat, b.Lng)
+	return RouteLeg{DistanceKm: km, Duration: time.Duration(km / r.speedKmh * float64(time.Hour))}
+}
+
+// encodePolyline writes points in the Google encoded polyline format (precision 5),
+// the format OSRM returns, so clients decode either provider the same way.
+func encodePolyline(points []api.GeoPoint) string {
+	var b strings.Builder
+	var prevLat, prevLng int64
+	for _, p := range points {
+		lat := int64(math.Round(p.Lat * 1e5))
+		lng := int64(math.Round(p.Lng * 1e5))
+		writePolylineValue(&b, lat-prevLat)
+		writePolylineValue(&b, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+	return b.String()
+}
+
+func writePolylineValue(b *strings.Builder, v int64) {
+	u := uint64(v) << 1
+	if v < 0 { u = ^u }
+	for u >= 0x20 {
+		b.WriteByte(byte(0x20|u&0x1f) + 63)
+		u >>= 5
+	}
+	b.WriteByte(byte(u) + 63)
+}
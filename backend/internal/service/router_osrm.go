@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+// OSRMRouter asks an OSRM server (e.g. a locally run osrm-routed on a regional extract)
+// for road distances and travel times through its HTTP API (route and table services).
+// When the server can't answer, the request goes to the fallback router instead, so a
+// routing outage degrades estimates rather than failing deliveries.
+type OSRMRouter struct {
+	baseURL  string // e.g. http://localhost:5000
+	profile  string // env OSRM_PROFILE; osrm-routed serves whatever profile it was built with
+	client   *http.Client
+	fallback Router
+}
+
+// osrmMaxTable is osrm-routed's default --max-table-size: coordinates per table request.
+const osrmMaxTable = 100
+
+// NewOSRMRouter targets the OSRM server at baseURL.
+func NewOSRMRouter(baseURL, profile string, timeout time.Duration, fallback Router) (*OSRMRouter, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("OSRM_URL must be an http(s) URL, got %q", baseURL)
+	}
+	return &OSRMRouter{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		profile:  url.PathEscape(profile),
+		client:   &http.Client{Timeout: timeout},
+		fallback: fallback,
+	}, nil
+}
+
+// Name identifies OSRM routing.
+func (r *OSRMRouter) Name() string { return "osrm" }
+
+type osrmRouteResponse struct {
+	Routes []struct {
+		Distance float64 `json:"distance"` // metres
+		Duration float64 `json:"duration"` // seconds
+		Geometry string  `json:"geometry"`
+		Legs     []struct {
+			Distance float64 `json:"distance"`
+			Duration float64 `json:"duration"`
+		} `json:"legs"`
+	} `json:"routes"`
+}
+
+type osrmTableResponse struct {
+	Distances [][]*float64 `json:"distances"` // null when unreachable
+	Durations [][]*float64 `json:"durations"`
+}
+
+// Route asks OSRM's route service for the road route through the waypoints.
+func (r *OSRMRouter) Route(ctx context.Context, waypoints ...api.GeoPoint) (*Route, error) {
+	if len(waypoints) < 2 { return nil, ErrTooFewWaypoints }
+	var out osrmRouteResponse
+	err := r.get(ctx, "route", waypoints, "overview=full&geometries=polyline&steps=false", &out)
+	if err == nil && len(out.Routes) == 0 { err = fmt.Errorf("osrm route: no route found") }
+	if err != nil {
+		log.Printf("%v; falling back to %s", err, r.fallback.Name())
+		return r.fallback.Route(ctx, waypoints...)
+	}
+
+	best := out.Routes[0]
+	route := &Route{
+		DistanceKm: best.Distance / 1000,
+		Duration:   osrmSeconds(best.Duration),
+		Polyline:   best.Geometry,
+		Provider:   r.Name(),
+	}
+	for _, l := range best.Legs {
+		route.Legs = append(route.Legs, RouteLeg{DistanceKm: l.Distance / 1000, Duration: osrmSeconds(l.Duration)})
+	}
+	return route, nil
+}
+
+// Table asks OSRM's table service for every source-destination pair, splitting the
+// destinations so no request exceeds osrmMaxTable coordinates.
+func (r *OSRMRouter) Table(ctx context.Context, sources, destinations []api.GeoPoint) ([][]RouteLeg, error) {
+	out := make([][]RouteLeg, len(sources))
+	for i := range out {
+		out[i] = make([]RouteLeg, 0, len(destinations))
+	}
+	chunk := osrmMaxTable - len(sources)
+	if chunk < 1 {
+		log.Printf("osrm table: %d sources exceed the table size; falling back to %s", len(sources), r.fallback.Name())
+		return r.fallback.Table(ctx, sources, destinations)
+	}
+
+	for start := 0; start < len(destinations); start += chunk {
+		end := min(start+chunk, len(destinations))
+		part, err := r.table(ctx, sources, destinations[start:end])
+		if err != nil {
+			log.Printf("%v; falling back to %s", err, r.fallback.Name())
+			part, err = r.fallback.Table(ctx, sources, destinations[start:end])
+			if err != nil { return nil, err }
+		}
+		for i := range sources {
+			out[i] = append(out[i], part[i]...)
+		}
+	}
+	return out, nil
+}
+
+// table is one table request: sources first, then destinations, in one coordinate list.
+func (r *OSRMRouter) table(ctx context.Context, sources, destinations []api.GeoPoint) ([][]RouteLeg, error) {
+	idx := func(from, n int) string {
+		s := make([]string, n)
+		for i := range s {
+			s[i] = strconv.Itoa(from + i)
+		}
+		return strings.Join(s, ";")
+	}
+	q := fmt.Sprintf("sources=%s&destinations=%s&annotations=distance,duration", idx(0, len(sources)), idx(len(sources), len(destinations)))
+	var resp osrmTableResponse
+	err := r.get(ctx, "table", append(append([]api.GeoPoint{}, sources...), destinations...), q, &resp)
+	if err != nil { return nil, err }
+	if len(resp.Durations) != len(sources) || len(resp.Distances) != len(sources) {
+		return nil, fmt.Errorf("osrm table: answer doesn't match the request")
+	}
+
+	out := make([][]RouteLeg, len(sources))
+	for i := range sources {
+		if len(resp.Durations[i]) != len(destinations) || len(resp.Distances[i]) != len(destinations) {
+			return nil, fmt.Errorf("osrm table: answer doesn't match the request")
+		}
+		out[i] = make([]RouteLeg, len(destinations))
+		for j := range destinations {
+			dist, dur := resp.Distances[i][j], resp.Durations[i][j]
+			if dist == nil || dur == nil {
+				out[i][j] = unreachableLeg
+				continue
+			}
+			out[i][j] = RouteLeg{DistanceKm: *dist / 1000, Duration: osrmSeconds(*dur)}
+		}
+	}
+	return out, nil
+}
+
+// get calls one OSRM service (/{service}/v1/{profile}/{lng,lat;...}?query) and decodes
+// the answer. The query is passed as is: OSRM wants its ';' and ',' separators unescaped.
+func (r *OSRMRouter) get(ctx context.Context, service string, points []api.GeoPoint, query string, out any) error {
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = strconv.FormatFloat(p.Lng, 'f', 6, 64) + "," + strconv.FormatFloat(p.Lat, 'f', 6, 64)
+	}
+	endpoint := fmt.Sprintf("%s/%s/v1/%s/%s?%s", r.baseURL, service, r.profile, strings.Join(coords, ";"), query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil { return err }
+	resp, err := r.client.Do(req)
+	if err != nil { return fmt.Errorf("osrm %s: %w", service, err) }
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil { return fmt.Errorf("osrm %s: %w", service, err) }
+
+	// OSRM reports failures as {"code": "...", "message": "..."}, with a 4xx status
+	var status struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return fmt.Errorf("osrm %s: %s: %s", service, resp.Status, bytes.TrimSpace(raw[:min(len(raw), 200)]))
+	}
+	if status.Code != "Ok" { return fmt.Errorf("osrm %s: %s: %s", service, status.Code, status.Message) }
+	if err := json.Unmarshal(raw, out); err != nil { return fmt.Errorf("osrm %s: %w", service, err) }
+	return nil
+}
+
+func osrmSeconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
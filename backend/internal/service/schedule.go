@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+var ErrInvalidWindow = errors.New("deliverBefore must be in the future and after pickupAfter")
+
+var ErrNotReleased = errors.New("scheduled delivery isn't open to couriers yet")
+
+var ErrBeforePickupWindow = errors.New("parcel can't be picked up before the delivery's pickupAfter")
+
+// validateWindow checks the optional pickup-after / deliver-before window of a new delivery.
+// A pickupAfter in the past is fine: the parcel is simply ready now.
+func validateWindow(req *api.DeliveryCreate, now time.Time) error {
+	if req.DeliverBefore == nil { return nil }
+	if !req.DeliverBefore.After(now) { return ErrInvalidWindow }
+	if req.PickupAfter != nil && !req.DeliverBefore.After(*req.PickupAfter) { return ErrInvalidWindow }
+	return nil
+}
+
+// releaseTime is when couriers start seeing a delivery picked up from pickupAfter on;
+// nil when that is already the case at now.
+func (s *DeliveryService) releaseTime(pickupAfter *time.Time, now time.Time) *time.Time {
+	if pickupAfter == nil { return nil }
+	at := pickupAfter.Add(-s.scheduleLead).UTC()
+	if !at.After(now) { return nil }
+	return &at
+}
+
+// released reports whether couriers may see and accept d at now.
+func released(d *api.Delivery, now time.Time) bool {
+	return d.ReleaseAt == nil || !now.Before(*d.ReleaseAt)
+}
+
+// jobPosted reports whether e is the moment couriers start seeing a posted delivery: its
+// creation, or for a scheduled one its release.
+func jobPosted(e *DeliveryEvent) bool {
+	if e.Delivery.Status != StatusPosted { return false }
+	return (e.Type == EventDeliveryCreated && e.Delivery.ReleaseAt == nil) || e.Type == EventDeliveryReleased
+}
+
+// sortByUrgency orders deliveries by their deliverBefore, earliest first; deliveries
+// without a deadline keep their order after those with one.
+func sortByUrgency(list []*api.Delivery) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i].DeliverBefore, list[j].DeliverBefore
+		if a == nil || b == nil { return a != nil && b == nil }
+		return a.Before(*b)
+	})
+}
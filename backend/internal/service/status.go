@@ -8,13 +8,17 @@ const (
 	StatusAccepted  = "accepted"
 	StatusPickedUp  = "picked_up"
 	StatusDelivered = "delivered"
+	StatusExpired   = "expired"
+	StatusFailed    = "failed"
 )
 
-// transitionMap encodes the allowed “next” value for each current status.
-var transitionMap = map[string]string{
-	StatusPosted:    StatusAccepted,
-	StatusAccepted:  StatusPickedUp,
-	StatusPickedUp:  StatusDelivered,
+// transitionMap encodes the allowed “next” values for each current status.
+// A posted delivery nobody accepts in time expires instead; one whose parcels all went
+// missing, at pickup or on the way, fails.
+var transitionMap = map[string][]string{
+	StatusPosted:    {StatusAccepted, StatusExpired},
+	StatusAccepted:  {StatusPickedUp, StatusFailed},
+	StatusPickedUp:  {StatusDelivered, StatusFailed},
 }
 
 // ErrInvalidTransition is returned when caller skips or repeats a state.
@@ -28,9 +32,8 @@ func (e ErrInvalidTransition) Error() string {
 
 // isValidTransition returns nil if (from->to) is allowed.
 func isValidTransition(from, to string) error {
-	wantNext, fromOk := transitionMap[from]
-	if !fromOk || wantNext != to {
-		return ErrInvalidTransition{From: from, To: to}
+	for _, next := range transitionMap[from] {
+		if next == to { return nil }
 	}
-	return nil
+	return ErrInvalidTransition{From: from, To: to}
 }
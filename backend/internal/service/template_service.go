@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TemplateService keeps the businesses' recurring delivery templates in /deliveryTemplates
+// and posts their occurrences as scheduled deliveries through DeliveryService.CreateDelivery.
+// nextRunAt is the next occurrence still to be posted; the scheduler claims it by moving it
+// on in a transaction before posting, so each occurrence is posted at most once even with
+// several server instances running.
+type TemplateService struct {
+	firestore  *db.FirestoreClient
+	deliveries *DeliveryService
+	users      *UserService
+	postAhead  time.Duration // how long before an occurrence its delivery is posted (env TEMPLATE_POST_AHEAD)
+}
+
+// NewTemplateService wires Firestore, the delivery domain and the users into templates.
+// called once from main.go at startup
+func NewTemplateService(fs *db.FirestoreClient, deliveries *DeliveryService, users *UserService) *TemplateService {
+	return &TemplateService{
+		firestore:  fs,
+		deliveries: deliveries,
+		users:      users,
+		postAhead:  config.Duration("TEMPLATE_POST_AHEAD", time.Hour),
+	}
+}
+
+var ErrTemplateNotFound = errors.New("delivery template not found")
+
+var ErrInvalidTemplate = errors.New("template needs a name and an item, a payment that isn't negative and a positive deliverWithinMinutes")
+
+// errOccurrenceTaken means another run already posted (or skipped) the occurrence.
+var errOccurrenceTaken = errors.New("occurrence already taken")
+
+const (
+	defaultOccurrences = 10
+	maxOccurrences     = 100
+)
+
+func (s *TemplateService) templates() *firestore.CollectionRef {
+	return s.firestore.Collection("deliveryTemplates")
+}
+
+// templateSchedule parses the stored recurrence of t.
+func templateSchedule(t *api.DeliveryTemplate) (*cronSchedule, *time.Location, error) {
+	cron, err := parseCron(t.Recurrence)
+	if err != nil { return nil, nil, err }
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil { return nil, nil, ErrInvalidRecurrence }
+	return cron, loc, nil
+}
+
+// nextRun is t's first occurrence after `after`; nil when there is none.
+func nextRun(t *api.DeliveryTemplate, after time.Time) *time.Time {
+	cron, loc, err := templateSchedule(t)
+	if err != nil { return nil }
+	at, ok := cron.next(after, loc)
+	if !ok { return nil }
+	at = at.UTC()
+	return &at
+}
+
+// applyTemplate validates req and copies it onto t; the delivery fields are checked
+// with the same rules CreateDelivery applies, so occurrences don't fail on them later.
+func (s *TemplateService) applyTemplate(ctx context.Context, t *api.DeliveryTemplate, req *api.DeliveryTemplateCreate) error {
+	name, item := strings.TrimSpace(req.Name), strings.TrimSpace(req.Item)
+	if name == "" || item == "" || req.Payment < 0 { return ErrInvalidTemplate }
+	if req.DeliverWithinMinutes != nil && *req.DeliverWithinMinutes <= 0 { return ErrInvalidTemplate }
+
+	dc := &api.DeliveryCreate{
+		RecipientName:  req.RecipientName,
+		RecipientPhone: req.RecipientPhone,
+		RecipientEmail: req.RecipientEmail,
+		Size:           req.Size,
+		WeightKg:       req.WeightKg,
+	}
+	if err := validateRecipient(dc); err != nil { return err }
+	if err := validateParcel(dc); err != nil { return err }
+	if _, err := s.deliveries.zones.locate(ctx, req.DestinationLocation); err != nil { return err }
+
+	t.Name = name
+	t.Recurrence = strings.Join(strings.Fields(req.Recurrence), " ")
+	t.Timezone = "UTC"
+	if req.Timezone != nil && *req.Timezone != "" { t.Timezone = *req.Timezone }
+	t.DeliverWithinMinutes = req.DeliverWithinMinutes
+	t.DestinationAddress = req.DestinationAddress
+	t.DestinationLocation = req.DestinationLocation
+	t.Item = item
+	t.Payment = req.Payment
+	t.RecipientName, t.RecipientPhone, t.RecipientEmail = dc.RecipientName, dc.RecipientPhone, dc.RecipientEmail
+	t.Size = req.Size
+	t.WeightKg = req.WeightKg
+
+	if _, _, err := templateSchedule(t); err != nil { return err }
+	if nextRun(t, time.Now()) == nil { return ErrInvalidRecurrence }
+	return nil
+}
+
+// templateDelivery is the delivery posted for t's occurrence at `at`, picked up at the business.
+func templateDelivery(t *api.DeliveryTemplate, business *api.BusinessUser, at time.Time) *api.DeliveryCreate {
+	req := &api.DeliveryCreate{
+		BusinessName:        business.BusinessName,
+		BusinessAddress:     business.BusinessAddress,
+		BusinessLocation:    business.Location,
+		DestinationAddress:  t.DestinationAddress,
+		DestinationLocation: t.DestinationLocation,
+		Item:                t.Item,
+		Payment:             t.Payment,
+		RecipientName:       t.RecipientName,
+		RecipientPhone:      t.RecipientPhone,
+		RecipientEmail:      t.RecipientEmail,
+		Size:                t.Size,
+		WeightKg:            t.WeightKg,
+		PickupAfter:         &at,
+	}
+	if t.DeliverWithinMinutes != nil {
+		before := at.Add(time.Duration(*t.DeliverWithinMinutes) * time.Minute)
+		req.DeliverBefore = &before
+	}
+	return req
+}
+
+// POST /delivery-templates
+func (s *TemplateService) CreateTemplate(ctx context.Context, businessUID string, req *api.DeliveryTemplateCreate) (*api.DeliveryTemplate, error) {
+	now := time.Now().UTC()
+	t := api.DeliveryTemplate{
+		Id:         uuid.NewString(),
+		BusinessId: businessUID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.applyTemplate(ctx, &t, req); err != nil { return nil, err }
+	t.NextRunAt = nextRun(&t, now)
+
+	_, err := s.templates().Doc(t.Id).Create(ctx, t)
+	if err != nil { return nil, err }
+	return &t, nil
+}
+
+// GET /delivery-templates
+// ListTemplates returns the business's templates.
+func (s *TemplateService) ListTemplates(ctx context.Context, businessUID string) ([]*api.DeliveryTemplate, error) {
+	iter := s.templates().Where("businessId", "==", businessUID).Documents(ctx)
+	defer iter.Stop()
+
+	result := []*api.DeliveryTemplate{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var t api.DeliveryTemplate
+		if err := doc.DataTo(&t); err != nil { continue }
+		t.Id = doc.Ref.ID
+		result = append(result, &t)
+	}
+	return result, nil
+}
+
+// decodeOwnTemplate decodes a template snapshot, reporting someone else's as not found.
+func decodeOwnTemplate(snap *firestore.DocumentSnapshot, err error, businessUID string) (*api.DeliveryTemplate, error) {
+	if status.Code(err) == codes.NotFound { return nil, ErrTemplateNotFound }
+	if err != nil { return nil, err }
+
+	var t api.DeliveryTemplate
+	err = snap.DataTo(&t)
+	if err != nil { return nil, err }
+	if t.BusinessId != businessUID { return nil, ErrTemplateNotFound }
+	t.Id = snap.Ref.ID
+	return &t, nil
+}
+
+// GET /delivery-templates/{id}
+func (s *TemplateService) GetTemplate(ctx context.Context, templateID, businessUID string) (*api.DeliveryTemplate, error) {
+	snap, err := s.templates().Doc(templateID).Get(ctx)
+	return decodeOwnTemplate(snap, err, businessUID)
+}
+
+// updateOwnTemplate applies change to the business's template in a transaction and
+// returns the stored result.
+func (s *TemplateService) updateOwnTemplate(ctx context.Context, templateID, businessUID string, change func(t *api.DeliveryTemplate, now time.Time) error) (*api.DeliveryTemplate, error) {
+	ref := s.templates().Doc(templateID)
+	var updated *api.DeliveryTemplate
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		t, err := decodeOwnTemplate(snap, err, businessUID)
+		if err != nil { return err }
+
+		now := time.Now().UTC()
+		if err := change(t, now); err != nil { return err }
+		t.UpdatedAt = now
+		updated = t
+		return tx.Set(ref, t)
+	})
+	if err != nil { return nil, err }
+	return updated, nil
+}
+
+// PUT /delivery-templates/{id}
+// UpdateTemplate replaces the template's fields; the next occurrence is recomputed from
+// now, so a changed recurrence applies straight away. Deliveries already posted stay.
+func (s *TemplateService) UpdateTemplate(ctx context.Context, templateID, businessUID string, req *api.DeliveryTemplateCreate) (*api.DeliveryTemplate, error) {
+	return s.updateOwnTemplate(ctx, templateID, businessUID, func(t *api.DeliveryTemplate, now time.Time) error {
+		if err := s.applyTemplate(ctx, t, req); err != nil { return err }
+		t.NextRunAt = nextRun(t, now)
+		return nil
+	})
+}
+
+// POST /delivery-templates/{id}/pause
+// POST /delivery-templates/{id}/resume
+// SetPaused stops or restarts posting occurrences. Resuming continues from the next
+// occurrence after now; the ones missed while paused aren't posted.
+func (s *TemplateService) SetPaused(ctx context.Context, templateID, businessUID string, paused bool) (*api.DeliveryTemplate, error) {
+	return s.updateOwnTemplate(ctx, templateID, businessUID, func(t *api.DeliveryTemplate, now time.Time) error {
+		if t.Paused && !paused { t.NextRunAt = nextRun(t, now) }
+		t.Paused = paused
+		return nil
+	})
+}
+
+// DELETE /delivery-templates/{id}
+func (s *TemplateService) DeleteTemplate(ctx context.Context, templateID, businessUID string) error {
+	if _, err := s.GetTemplate(ctx, templateID, businessUID); err != nil { return err }
+	_, err := s.templates().Doc(templateID).Delete(ctx)
+	return err
+}
+
+// GET /delivery-templates/{id}/occurrences
+// Occurrences lists the next count deliveries the template will post, starting with the
+// one not posted yet; none while paused.
+func (s *TemplateService) Occurrences(ctx context.Context, templateID, businessUID string, count int) ([]api.DeliveryTemplateOccurrence, error) {
+	t, err := s.GetTemplate(ctx, templateID, businessUID)
+	if err != nil { return nil, err }
+	if count <= 0 { count = defaultOccurrences }
+	count = min(count, maxOccurrences)
+
+	result := []api.DeliveryTemplateOccurrence{}
+	if t.Paused { return result, nil }
+	for at := t.NextRunAt; at != nil && len(result) < count; at = nextRun(t, *at) {
+		o := api.DeliveryTemplateOccurrence{PickupAfter: *at}
+		if t.DeliverWithinMinutes != nil {
+			before := at.Add(time.Duration(*t.DeliverWithinMinutes) * time.Minute)
+			o.DeliverBefore = &before
+		}
+		result = append(result, o)
+	}
+	return result, nil
+}
+
+// PostDue posts every occurrence due within postAhead of now and returns how many
+// deliveries were created. One template failing doesn't hold up the others.
+func (s *TemplateService) PostDue(ctx context.Context, now time.Time) (int, error) {
+	// nextRunAt only, so the query needs no composite index; paused ones are skipped here
+	iter := s.templates().Where("nextRunAt", "<=", now.Add(s.postAhead)).Documents(ctx)
+	defer iter.Stop()
+
+	posted := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return posted, err }
+
+		var t api.DeliveryTemplate
+		if err := doc.DataTo(&t); err != nil || t.Paused || t.NextRunAt == nil { continue }
+		err = s.postOccurrence(ctx, doc.Ref, *t.NextRunAt, now)
+		if errors.Is(err, errOccurrenceTaken) { continue }
+		if err != nil {
+			log.Printf("delivery template %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		posted++
+	}
+	return posted, nil
+}
+
+// postOccurrence claims the template's occurrence at `at` and posts its delivery. A
+// failed post is recorded on the template as lastError; the occurrence isn't retried.
+func (s *TemplateService) postOccurrence(ctx context.Context, ref *firestore.DocumentRef, at, now time.Time) error {
+	var t api.DeliveryTemplate
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if err != nil { return err }
+		if err := snap.DataTo(&t); err != nil { return err }
+		if t.Paused || t.NextRunAt == nil || !t.NextRunAt.Equal(at) { return errOccurrenceTaken }
+
+		// occurrences missed while the server was down are skipped, all but this one
+		after := at
+		if now.After(after) { after = now }
+		var next any = firestore.Delete
+		if n := nextRun(&t, after); n != nil { next = *n }
+		return tx.Update(ref, []firestore.Update{{Path: "nextRunAt", Value: next}})
+	})
+	if err != nil { return err }
+
+	business, err := s.users.GetBusinessInfo(ctx, t.BusinessId)
+	var d *api.Delivery
+	if err == nil {
+		d, err = s.deliveries.CreateDelivery(ctx, templateDelivery(&t, business, at), t.BusinessId)
+	}
+
+	updates := []firestore.Update{{Path: "lastRunAt", Value: at}}
+	if err != nil {
+		updates = append(updates, firestore.Update{Path: "lastError", Value: err.Error()})
+	} else {
+		updates = append(updates,
+			firestore.Update{Path: "lastDeliveryId", Value: *d.Id},
+			firestore.Update{Path: "lastError", Value: firestore.Delete})
+	}
+	if _, uerr := ref.Update(ctx, updates); uerr != nil && err == nil { err = uerr }
+	return err
+}
+
+// RunTemplateScheduler posts due occurrences now and then every `every`, until ctx is cancelled.
+func (s *TemplateService) RunTemplateScheduler(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		posted, err := s.PostDue(ctx, time.Now().UTC())
+		if err != nil {
+			log.Printf("delivery templates: %v", err)
+		} else if posted > 0 {
+			log.Printf("delivery templates: %d deliveries posted", posted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math"
+	"net/mail"
+	"strings"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"google.golang.org/api/iterator"
+)
+
+var ErrInvalidRecipient = errors.New("recipientPhone must be E.164 (e.g. +4915112345678) and recipientEmail a valid address")
+
+var ErrTrackingNotFound = errors.New("tracking link not found")
+
+// trackingGridDeg is the grid courier positions are snapped to on the public page (~500 m).
+const trackingGridDeg = 0.005
+
+// newTrackingToken returns 256 random bits, base64url: the only credential of a tracking link.
+func newTrackingToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil { return "", err }
+	return b64.EncodeToString(buf), nil
+}
+
+// validateRecipient trims the optional recipient contacts and checks their format.
+func validateRecipient(req *api.DeliveryCreate) error {
+	for _, f := range []**string{&req.RecipientName, &req.RecipientPhone, &req.RecipientEmail} {
+		if *f == nil { continue }
+		v := strings.TrimSpace(**f)
+		if v == "" { *f = nil } else { *f = &v }
+	}
+	if req.RecipientPhone != nil && !e164.MatchString(*req.RecipientPhone) { return ErrInvalidRecipient }
+	if req.RecipientEmail != nil {
+		addr, err := mail.ParseAddress(*req.RecipientEmail)
+		if err != nil || addr.Address != *req.RecipientEmail { return ErrInvalidRecipient }
+	}
+	return nil
+}
+
+// GET /track/{token}
+// GetTracking returns the public view of the delivery behind a tracking token:
+// status, the stored drop-off ETA and the courier's rounded position while on the way.
+func (s *DeliveryService) GetTracking(ctx context.Context, token string) (*api.Tracking, error) {
+	if token == "" { return nil, ErrTrackingNotFound }
+	iter := s.firestore.Collection("deliveries").Where("trackingToken", "==", token).Limit(1).Documents(ctx)
+	defer iter.Stop()
+	doc, err := iter.Next()
+	if err == iterator.Done { return nil, ErrTrackingNotFound }
+	if err != nil { return nil, err }
+
+	var d api.Delivery
+	err = doc.DataTo(&d)
+	if err != nil { return nil, err }
+
+	t := &api.Tracking{
+		Status:       api.TrackingStatus(d.Status),
+		BusinessName: d.BusinessName,
+		Item:         d.Item,
+		DeliveredAt:  d.DeliveredAt,
+	}
+	if d.CreatedAt != nil { t.CreatedAt = *d.CreatedAt }
+
+	onTheWay := d.Status == StatusAccepted || d.Status == StatusPickedUp
+	if !onTheWay || d.AssignedTo == nil { return t, nil }
+	if d.Eta != nil { t.EstimatedArrival = &d.Eta.DropoffAt }
+
+	loc, err := lastCourierLocation(ctx, s.firestore, *d.AssignedTo)
+	if err != nil || loc == nil { return t, err }
+
+	t.CourierPosition = &api.GeoPoint{Lat: snapToGrid(loc.Lat), Lng: snapToGrid(loc.Lng)}
+	t.CourierPositionAt = &loc.UpdatedAt
+	return t, nil
+}
+
+// snapToGrid hides the exact position behind a ~500 m grid.
+func snapToGrid(deg float64) float64 {
+	return math.Round(math.Round(deg/trackingGridDeg)*trackingGridDeg*1e6) / 1e6
+}
+
+// RedactForCourier hides what a courier has no business seeing: the tracking token
+// always, the recipient's contacts unless the delivery is assigned to them.
+func RedactForCourier(d api.Delivery, courierUID string) api.Delivery {
+	d.TrackingToken = nil
+	if d.AssignedTo == nil || *d.AssignedTo != courierUID {
+		d.RecipientName = nil
+		d.RecipientPhone = nil
+		d.RecipientEmail = nil
+	}
+	return d
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/Evap1/courier-system/backend/internal/db"
 	"github.com/Evap1/courier-system/backend/api"
 	"fmt"
+	"sort"
 	"google.golang.org/api/iterator"
 
 )
@@ -129,3 +130,19 @@ func (u *UserService) GetAllBusinesses(ctx context.Context) ([]*api.BusinessUser
 	}
 	return businesses, nil
 }
+
+// GetLowRatedCouriers returns couriers with at least minRatings ratings whose average is below `below`,
+// lowest average first. Filtering happens here since Firestore can't range over two fields at once.
+func (u *UserService) GetLowRatedCouriers(ctx context.Context, below float64, minRatings int) ([]*api.CourierUser, error) {
+	all, err := u.GetAllCouriers(ctx)
+	if err != nil { return nil, err }
+
+	low := []*api.CourierUser{}
+	for _, c := range all {
+		if c.RatingCount >= minRatings && c.RatingCount > 0 && c.RatingAverage < below {
+			low = append(low, c)
+		}
+	}
+	sort.Slice(low, func(i, j int) bool { return low[i].RatingAverage < low[j].RatingAverage })
+	return low, nil
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WebhookService notifies businesses' own systems about their deliveries.
+//
+// Registrations live in /webhooks. Every matching delivery event becomes one
+// /webhookDeliveries doc ({eventId}_{webhookId}, so no instance ever queues it twice),
+// which a worker sends with an HMAC signature and retries with exponential backoff.
+// After maxAttempts failures the call is marked dead and shows up in the dead-letter list.
+type WebhookService struct {
+	firestore   *db.FirestoreClient
+	client      *http.Client
+	maxAttempts int           // env WEBHOOK_MAX_ATTEMPTS
+	backoffBase time.Duration // env WEBHOOK_BACKOFF_BASE, doubled after every failure
+}
+
+// NewWebhookService wires Firestore and an HTTP client into the webhook domain.
+// called once from main.go at startup; Run must be started for events to be sent
+func NewWebhookService(fs *db.FirestoreClient) *WebhookService {
+	return &WebhookService{
+		firestore:   fs,
+		client:      &http.Client{Timeout: config.Duration("WEBHOOK_TIMEOUT", 10*time.Second)},
+		maxAttempts: config.Int("WEBHOOK_MAX_ATTEMPTS", 8),
+		backoffBase: config.Duration("WEBHOOK_BACKOFF_BASE", 30*time.Second),
+	}
+}
+
+var ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http(s) URL")
+
+var ErrInvalidWebhookEvent = errors.New("unknown webhook event type")
+
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+const (
+	webhookTestEvent  = "webhook.test"
+	maxBackoff        = 6 * time.Hour
+	webhookLease      = 2 * time.Minute // a claimed call is retried by anyone after this
+	webhookBatchSize  = 20
+	webhookPollPeriod = 5 * time.Second
+)
+
+// webhookEventFor names the webhook event of a delivery event; "" when businesses aren't notified.
+func webhookEventFor(e *DeliveryEvent) api.WebhookEvent {
+	switch {
+	case e.Type == EventDeliveryAccepted:
+		return api.WebhookEventDeliveryAccepted
+	case e.Type == EventDeliveryStatus && e.Delivery.Status == api.DeliveryStatusPickedUp:
+		return api.WebhookEventDeliveryPickedUp
+	case e.Type == EventDeliveryStatus && e.Delivery.Status == api.DeliveryStatusDelivered:
+		return api.WebhookEventDeliveryDelivered
+	}
+	return ""
+}
+
+// webhookPayload is the JSON body POSTed to a webhook.
+type webhookPayload struct {
+	Id        string       `json:"id"`
+	Type      string       `json:"type"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Delivery  DeliveryJSON `json:"delivery"`
+}
+
+// signWebhook returns the X-Webhook-Signature value for body sent at ts:
+// t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">.
+// Receivers recompute it with their secret and reject stale timestamps.
+func signWebhook(secret string, ts time.Time, body []byte) string {
+	unix := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff is the wait before the next attempt after `attempts` failures.
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	d := s.backoffBase
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff { d = maxBackoff }
+	return d
+}
+
+// POST /webhooks
+// CreateWebhook registers a business endpoint for the given event types.
+// A random secret is generated when none is given.
+func (s *WebhookService) CreateWebhook(ctx context.Context, businessUID string, req *api.WebhookCreate) (*api.Webhook, error) {
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidWebhookURL
+	}
+	if len(req.Events) == 0 { return nil, ErrInvalidWebhookEvent }
+	for _, e := range req.Events {
+		if e != api.WebhookEventDeliveryAccepted && e != api.WebhookEventDeliveryPickedUp && e != api.WebhookEventDeliveryDelivered {
+			return nil, ErrInvalidWebhookEvent
+		}
+	}
+
+	secret := ""
+	if req.Secret != nil { secret = *req.Secret }
+	if secret == "" {
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil { return nil, err }
+		secret = "whsec_" + hex.EncodeToString(buf)
+	}
+
+	id := uuid.NewString()
+	now := time.Now().UTC()
+	hook := api.Webhook{
+		Id:         &id,
+		BusinessId: businessUID,
+		Url:        req.Url,
+		Events:     req.Events,
+		Secret:     &secret,
+		CreatedAt:  &now,
+	}
+	_, err = s.firestore.Collection("webhooks").Doc(id).Create(ctx, hook)
+	if err != nil { return nil, err }
+	return &hook, nil
+}
+
+// GET /webhooks
+// ListWebhooks returns the business's registrations, without their secrets.
+func (s *WebhookService) ListWebhooks(ctx context.Context, businessUID string) ([]*api.Webhook, error) {
+	iter := s.firestore.Collection("webhooks").Where("businessId", "==", businessUID).Documents(ctx)
+	defer iter.Stop()
+
+	result := []*api.Webhook{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var w api.Webhook
+		if err := doc.DataTo(&w); err != nil { continue }
+		id := doc.Ref.ID
+		w.Id = &id
+		w.Secret = nil // only shown once, on create
+		result = append(result, &w)
+	}
+	return result, nil
+}
+
+// getOwnWebhook loads a webhook, reporting someone else's as not found.
+func (s *WebhookService) getOwnWebhook(ctx context.Context, webhookID, businessUID string) (*api.Webhook, error) {
+	snap, err := s.firestore.Collection("webhooks").Doc(webhookID).Get(ctx)
+	if status.Code(err) == codes.NotFound { return nil, ErrWebhookNotFound }
+	if err != nil { return nil, err }
+
+	var w api.Webhook
+	err = snap.DataTo(&w)
+	if err != nil { return nil, err }
+	if w.BusinessId != businessUID { return nil, ErrWebhookNotFound }
+	w.Id = &webhookID
+	return &w, nil
+}
+
+// DELETE /webhooks/{id}
+// DeleteWebhook removes a registration; its queued calls are dropped by the worker.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID, businessUID string) error {
+	if _, err := s.getOwnWebhook(ctx, webhookID, businessUID); err != nil { return err }
+	_, err := s.firestore.Collection("webhooks").Doc(webhookID).Delete(ctx)
+	return err
+}
+
+// GET /webhooks/dead-letters
+// ListDeadLetters returns the business's calls that failed every attempt, newest first.
+func (s *WebhookService) ListDeadLetters(ctx context.Context, businessUID string) ([]*api.WebhookDelivery, error) {
+	iter := s.firestore.Collection("webhookDeliveries").
+		Where("businessId", "==", businessUID).
+		Where("status", "==", string(api.WebhookDeliveryStatusDead)).
+		OrderBy("createdAt", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	result := []*api.WebhookDelivery{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var wd api.WebhookDelivery
+		if err := doc.DataTo(&wd); err != nil { continue }
+		id := doc.Ref.ID
+		wd.Id = &id
+		result = append(result, &wd)
+	}
+	return result, nil
+}
+
+// POST /webhooks/{id}/test
+// TestWebhook sends one signed webhook.test call right away, without retries,
+// and reports how it went. The sample delivery is the business's latest one if any.
+func (s *WebhookService) TestWebhook(ctx context.Context, webhookID, businessUID string) (*api.WebhookDelivery, error) {
+	hook, err := s.getOwnWebhook(ctx, webhookID, businessUID)
+	if err != nil { return nil, err }
+
+	var sample api.Delivery
+	docs, err := s.firestore.Collection("deliveries").
+		Where("businessId", "==", businessUID).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(1).
+		Documents(ctx).GetAll()
+	if err == nil && len(docs) > 0 && docs[0].DataTo(&sample) == nil {
+		id := docs[0].Ref.ID
+		sample.Id = &id
+	}
+
+	now := time.Now().UTC()
+	id := "test-" + uuid.NewString()
+	body, err := json.Marshal(webhookPayload{Id: id, Type: webhookTestEvent, CreatedAt: now, Delivery: DeliveryJSON(sample)})
+	if err != nil { return nil, err }
+
+	wd := &api.WebhookDelivery{
+		Id:         &id,
+		WebhookId:  webhookID,
+		BusinessId: businessUID,
+		Event:      webhookTestEvent,
+		Payload:    string(body),
+		Attempts:   1,
+		CreatedAt:  &now,
+	}
+	if sample.Id != nil { wd.DeliveryId = *sample.Id }
+
+	code, err := s.send(ctx, hook, wd)
+	if code != 0 { wd.LastStatusCode = &code }
+	if err != nil {
+		msg := err.Error()
+		wd.LastError = &msg
+		wd.Status = api.WebhookDeliveryStatusFailed
+		return wd, nil
+	}
+	delivered := time.Now().UTC()
+	wd.DeliveredAt = &delivered
+	wd.Status = api.WebhookDeliveryStatusDelivered
+	return wd, nil
+}
+
+// send POSTs the stored payload once. Any non-2xx answer counts as a failure.
+func (s *WebhookService) send(ctx context.Context, hook *api.Webhook, wd *api.WebhookDelivery) (int, error) {
+	if hook.Secret == nil { return 0, errors.New("webhook has no signing secret") }
+	body := []byte(wd.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Url, bytes.NewReader(body))
+	if err != nil { return 0, err }
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "courier-system-webhooks/1")
+	req.Header.Set("X-Webhook-Id", *wd.Id)
+	req.Header.Set("X-Webhook-Event", wd.Event)
+	req.Header.Set("X-Webhook-Signature", signWebhook(*hook.Secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil { return 0, err }
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Run sends due webhook calls until ctx is cancelled.
+// Calls are queued by the outbox relay, which feeds delivery events to Deliver.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sendDue(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("webhook sender: %v", err)
+			}
+		}
+	}
+}
+
+// Name identifies the webhook queue as an outbox sink.
+func (s *WebhookService) Name() string { return "webhooks" }
+
+// Deliver is the outbox sink: it queues the calls for one delivery event.
+func (s *WebhookService) Deliver(ctx context.Context, e *DeliveryEvent) error {
+	return s.enqueue(ctx, e)
+}
+
+// enqueue creates one pending call per webhook of the delivery's business subscribed to the event.
+func (s *WebhookService) enqueue(ctx context.Context, e *DeliveryEvent) error {
+	event := webhookEventFor(e)
+	if event == "" || e.Delivery.BusinessId == nil { return nil }
+
+	hooks, err := s.ListWebhooks(ctx, *e.Delivery.BusinessId)
+	if err != nil { return err }
+
+	for _, hook := range hooks {
+		subscribed := false
+		for _, ev := range hook.Events {
+			if ev == event { subscribed = true }
+		}
+		if !subscribed { continue }
+
+		id := e.Id + "_" + *hook.Id
+		body, err := json.Marshal(webhookPayload{Id: id, Type: string(event), CreatedAt: e.CreatedAt, Delivery: DeliveryJSON(e.Delivery)})
+		if err != nil { return err }
+
+		now := time.Now().UTC()
+		wd := api.WebhookDelivery{
+			Id:            &id,
+			WebhookId:     *hook.Id,
+			BusinessId:    hook.BusinessId,
+			Event:         string(event),
+			DeliveryId:    e.DeliveryId,
+			Payload:       string(body),
+			Status:        api.WebhookDeliveryStatusPending,
+			NextAttemptAt: &now,
+			CreatedAt:     &now,
+		}
+		// another instance may have queued it already; the fixed id makes that a no-op
+		_, err = s.firestore.Collection("webhookDeliveries").Doc(id).Create(ctx, wd)
+		if err != nil && status.Code(err) != codes.AlreadyExists { return err }
+	}
+	return nil
+}
+
+// sendDue claims pending calls whose time has come and sends them.
+func (s *WebhookService) sendDue(ctx context.Context) error {
+	docs, err := s.firestore.Collection("webhookDeliveries").
+		Where("status", "==", string(api.WebhookDeliveryStatusPending)).
+		Where("nextAttemptAt", "<=", time.Now().UTC()).
+		OrderBy("nextAttemptAt", firestore.Asc).
+		Limit(webhookBatchSize).
+		Documents(ctx).GetAll()
+	if err != nil { return err }
+
+	for _, doc := range docs {
+		wd, ok, err := s.claim(ctx, doc.Ref)
+		if err != nil { return err }
+		if !ok { continue } // another instance got it first
+		if err := s.attempt(ctx, doc.Ref, wd); err != nil { return err }
+	}
+	return nil
+}
+
+// claim takes a due call for this instance by pushing nextAttemptAt past the lease,
+// so a crashed sender's call becomes due again instead of being lost.
+func (s *WebhookService) claim(ctx context.Context, ref *firestore.DocumentRef) (*api.WebhookDelivery, bool, error) {
+	var wd api.WebhookDelivery
+	claimed := false
+	err := s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+		snap, err := tx.Get(ref)
+		if err != nil { return err }
+		err = snap.DataTo(&wd)
+		if err != nil { return err }
+
+		now := time.Now().UTC()
+		if wd.Status != api.WebhookDeliveryStatusPending || wd.NextAttemptAt == nil || wd.NextAttemptAt.After(now) {
+			return nil
+		}
+		lease := now.Add(webhookLease)
+		wd.NextAttemptAt = &lease
+		wd.Attempts++
+		claimed = true
+		return tx.Update(ref, []firestore.Update{
+			{Path: "nextAttemptAt", Value: lease},
+			{Path: "attempts", Value: wd.Attempts},
+		})
+	})
+	if err != nil { return nil, false, err }
+	id := ref.ID
+	wd.Id = &id
+	return &wd, claimed, nil
+}
+
+// attempt sends a claimed call and records the outcome: delivered, retry later, or dead.
+func (s *WebhookService) attempt(ctx context.Context, ref *firestore.DocumentRef, wd *api.WebhookDelivery) error {
+	var code int
+	hookSnap, err := s.firestore.Collection("webhooks").Doc(wd.WebhookId).Get(ctx)
+	switch {
+	case status.Code(err) == codes.NotFound:
+		err = errors.New("webhook was deleted")
+		wd.Attempts = s.maxAttempts // nobody left to retry for
+	case err != nil:
+		return err
+	default:
+		var hook api.Webhook
+		if err = hookSnap.DataTo(&hook); err != nil { return err }
+		code, err = s.send(ctx, &hook, wd)
+	}
+
+	now := time.Now().UTC()
+	updates := []firestore.Update{}
+	if code != 0 { updates = append(updates, firestore.Update{Path: "lastStatusCode", Value: code}) }
+
+	if err == nil {
+		updates = append(updates,
+			firestore.Update{Path: "status", Value: string(api.WebhookDeliveryStatusDelivered)},
+			firestore.Update{Path: "deliveredAt", Value: now},
+			firestore.Update{Path: "nextAttemptAt", Value: nil},
+			firestore.Update{Path: "lastError", Value: nil},
+		)
+	} else if wd.Attempts >= s.maxAttempts {
+		updates = append(updates,
+			firestore.Update{Path: "status", Value: string(api.WebhookDeliveryStatusDead)},
+			firestore.Update{Path: "nextAttemptAt", Value: nil},
+			firestore.Update{Path: "lastError", Value: err.Error()},
+		)
+	} else {
+		updates = append(updates,
+			firestore.Update{Path: "nextAttemptAt", Value: now.Add(s.backoff(wd.Attempts))},
+			firestore.Update{Path: "lastError", Value: err.Error()},
+		)
+	}
+	_, err = ref.Update(ctx, updates)
+	return err
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+func TestWebhookPayloadJSON(t *testing.T) {
+	id, size := "d1", api.Small
+	createdAt := time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC)
+	parcels := []api.Parcel{{Label: "p1", Description: "box", Status: api.ParcelStatusPickedUp, Scans: []api.ParcelScan{}}}
+	d := api.Delivery{
+		Id: &id, BusinessName: "Bakery", Item: "bread", Status: api.DeliveryStatusPickedUp, Payment: 7.5,
+		DestinationLocation: api.GeoPoint{Lat: 52.5, Lng: 13.4}, CreatedAt: &createdAt, Size: &size, Parcels: &parcels,
+	}
+
+	body, err := json.Marshal(webhookPayload{Id: "e1", Type: "delivery.picked_up", CreatedAt: createdAt, Delivery: DeliveryJSON(d)})
+	if err != nil { t.Fatal(err) }
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil { t.Fatal(err) }
+	delivery, _ := got["delivery"].(map[string]any)
+
+	tests := []struct {
+		name string
+		v    any
+		want any
+	}{
+		{"envelope id", got["id"], "e1"},
+		{"envelope createdAt", got["createdAt"], "2025-06-02T09:30:00Z"},
+		{"delivery id", delivery["id"], "d1"},
+		{"delivery businessName", delivery["businessName"], "Bakery"},
+		{"delivery status", delivery["status"], "picked_up"},
+		{"delivery payment", delivery["payment"], 7.5},
+		{"delivery createdAt", delivery["createdAt"], "2025-06-02T09:30:00Z"},
+		{"nested struct", delivery["destinationLocation"], map[string]any{"lat": 52.5, "lng": 13.4}},
+		{"nested slice", len(delivery["parcels"].([]any)), 1},
+		{"struct in a slice", delivery["parcels"].([]any)[0].(map[string]any)["label"], "p1"},
+		{"nil pointer without omitempty", delivery["assignedTo"], nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !equalJSON(tt.v, tt.want) { t.Errorf("got %#v, want %#v", tt.v, tt.want) }
+		})
+	}
+
+	for _, key := range []string{"Id", "BusinessName", "Delivery"} {
+		if _, ok := got[key]; ok { t.Errorf("body has Go field name %q", key) }
+		if _, ok := delivery[key]; ok { t.Errorf("delivery has Go field name %q", key) }
+	}
+	if _, ok := delivery["assignedTo"]; !ok { t.Error("assignedTo should be written as null") }
+
+	// consumers decode the camelCase body back into the generated type
+	var back struct{ Delivery api.Delivery }
+	if err := json.Unmarshal(body, &back); err != nil { t.Fatal(err) }
+	if back.Delivery.BusinessName != "Bakery" || back.Delivery.DestinationLocation.Lng != 13.4 || *back.Delivery.Id != "d1" {
+		t.Errorf("decoded %+v", back.Delivery)
+	}
+}
+
+// equalJSON compares decoded JSON values.
+func equalJSON(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
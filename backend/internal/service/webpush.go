@@ -0,0 +1,134 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Web Push primitives: VAPID authorization (RFC 8292) and aes128gcm payload
+// encryption (RFC 8291, RFC 8188), so the browser's push service can neither read
+// the message nor accept pushes for the subscription from anyone else.
+
+var b64 = base64.RawURLEncoding
+
+// decodeB64 accepts base64url with or without padding, as browsers hand out both.
+func decodeB64(s string) ([]byte, error) {
+	return b64.DecodeString(strings.TrimRight(s, "="))
+}
+
+// vapidKey is the server's P-256 signing key; the public half is the
+// applicationServerKey browsers subscribe with.
+type vapidKey struct {
+	private *ecdsa.PrivateKey
+	public  string // uncompressed point, base64url
+}
+
+// parseVAPIDKey reads a key pair as printed by `npx web-push generate-vapid-keys`:
+// the raw 32-byte private scalar and the 65-byte uncompressed public point, base64url.
+func parseVAPIDKey(publicKey, privateKey string) (*vapidKey, error) {
+	d, err := decodeB64(privateKey)
+	if err != nil || len(d) != 32 { return nil, errors.New("VAPID_PRIVATE_KEY must be a base64url 32-byte P-256 key") }
+
+	priv, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil { return nil, fmt.Errorf("VAPID_PRIVATE_KEY: %w", err) }
+	pub := priv.PublicKey().Bytes()
+	if publicKey != "" {
+		given, err := decodeB64(publicKey)
+		if err != nil || string(given) != string(pub) {
+			return nil, errors.New("VAPID_PUBLIC_KEY doesn't belong to VAPID_PRIVATE_KEY")
+		}
+	}
+
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	key.Curve = elliptic.P256()
+	key.X = new(big.Int).SetBytes(pub[1:33])
+	key.Y = new(big.Int).SetBytes(pub[33:])
+	return &vapidKey{private: key, public: b64.EncodeToString(pub)}, nil
+}
+
+// authorization returns the Authorization header for a push to endpoint:
+// an ES256 JWT for the endpoint's origin, valid for 12 hours, plus the public key.
+func (k *vapidKey) authorization(endpoint, subject string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil { return "", err }
+
+	header := b64.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": subject,
+	})
+	if err != nil { return "", err }
+	signing := header + "." + b64.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signing))
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest[:])
+	if err != nil { return "", err }
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	return fmt.Sprintf("vapid t=%s.%s, k=%s", signing, b64.EncodeToString(sig), k.public), nil
+}
+
+// encryptWebPush encrypts plaintext for the subscription keys as a single aes128gcm record.
+func encryptWebPush(p256dh, authSecret string, plaintext []byte) ([]byte, error) {
+	uaRaw, err := decodeB64(p256dh)
+	if err != nil { return nil, fmt.Errorf("p256dh: %w", err) }
+	uaPublic, err := ecdh.P256().NewPublicKey(uaRaw)
+	if err != nil { return nil, fmt.Errorf("p256dh: %w", err) }
+	auth, err := decodeB64(authSecret)
+	if err != nil || len(auth) != 16 { return nil, errors.New("auth must be a base64url 16-byte secret") }
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil { return nil, err }
+	asPublic := asPrivate.PublicKey().Bytes()
+	shared, err := asPrivate.ECDH(uaPublic)
+	if err != nil { return nil, err }
+
+	// IKM binds the shared secret to both keys and the subscription's auth secret
+	keyInfo := append(append([]byte("WebPush: info\x00"), uaRaw...), asPublic...)
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, auth, keyInfo), ikm); err != nil { return nil, err }
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil { return nil, err }
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek := make([]byte, 16)
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), cek); err != nil { return nil, err }
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce); err != nil { return nil, err }
+
+	block, err := aes.NewCipher(cek)
+	if err != nil { return nil, err }
+	gcm, err := cipher.NewGCM(block)
+	if err != nil { return nil, err }
+
+	// header: salt | record size | key id length | key id (our ephemeral public key)
+	const recordSize = 4096
+	if len(plaintext)+1+gcm.Overhead() > recordSize { return nil, errors.New("push payload too large") }
+	out := make([]byte, 0, 16+4+1+len(asPublic)+len(plaintext)+1+gcm.Overhead())
+	out = append(out, salt...)
+	out = binary.BigEndian.AppendUint32(out, recordSize)
+	out = append(out, byte(len(asPublic)))
+	out = append(out, asPublic...)
+	// 0x02 marks the last (and only) record
+	return gcm.Seal(out, nonce, append(plaintext[:len(plaintext):len(plaintext)], 0x02), nil), nil
+}
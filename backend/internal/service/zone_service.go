@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/config"
+	"github.com/Evap1/courier-system/backend/internal/db"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ZoneService manages the service zones: the areas deliveries may go to, and the rules
+// that apply there (operating hours, minimum payment, pricing).
+//
+// Zones live in /zones. Firestore can't store GeoJSON's nested arrays, so a polygon is
+// stored as rings of points and turned back into GeoJSON on the way out. Lookups use an
+// in-memory copy of all zones, reloaded every ZONE_CACHE_TTL and after every change made
+// through this instance. Without any zone there is no coverage check at all.
+type ZoneService struct {
+	firestore *db.FirestoreClient
+	cacheTTL  time.Duration // env ZONE_CACHE_TTL
+
+	mu      sync.Mutex
+	cached  []*zone // oldest first
+	fetched time.Time
+}
+
+// NewZoneService wires Firestore into the zone domain.
+// called once from main.go at startup
+func NewZoneService(fs *db.FirestoreClient) *ZoneService {
+	return &ZoneService{
+		firestore: fs,
+		cacheTTL:  config.Duration("ZONE_CACHE_TTL", time.Minute),
+	}
+}
+
+var ErrInvalidZone = errors.New("zone needs a name and a GeoJSON Polygon of closed rings with at least 4 [lng, lat] positions")
+
+var ErrInvalidZoneRules = errors.New("hours need ISO weekdays 1-7 and HH:MM times, payments and rates can't be negative")
+
+var ErrZoneNotFound = errors.New("zone not found")
+
+var ErrUnknownZone = errors.New("unknown zone id")
+
+var ErrOutsideCoverage = errors.New("destination is outside every service zone")
+
+var ErrZoneClosed = errors.New("the destination's service zone is closed at this time")
+
+var ErrBelowZoneMinimum = errors.New("payment is below the minimum of the destination's service zone")
+
+var ErrOutsideCourierZones = errors.New("delivery is outside the courier's service zones")
+
+// zoneDoc is how a zone is stored in /zones/{id}.
+type zoneDoc struct {
+	Name       string           `firestore:"name"`
+	Active     bool             `firestore:"active"`
+	Rings      []zoneRing       `firestore:"rings"`
+	Timezone   string           `firestore:"timezone"`
+	Hours      []api.ZoneHours  `firestore:"hours"`
+	MinPayment *float64         `firestore:"minPayment,omitempty"`
+	Pricing    *api.ZonePricing `firestore:"pricing,omitempty"`
+	CreatedAt  time.Time        `firestore:"createdAt"`
+	UpdatedAt  time.Time        `firestore:"updatedAt"`
+}
+
+type zoneRing struct {
+	Points []api.GeoPoint `firestore:"points"`
+}
+
+// zone is a stored zone prepared for lookups.
+type zone struct {
+	api.Zone
+	loc                            *time.Location
+	minLat, maxLat, minLng, maxLng float64 // bounding box
+}
+
+// zoneDocFrom validates a create/replace request into its stored form.
+func zoneDocFrom(req *api.ZoneCreate) (zoneDoc, error) {
+	d := zoneDoc{Name: strings.TrimSpace(req.Name), Active: true, Timezone: "UTC", Hours: []api.ZoneHours{}}
+	if d.Name == "" || req.Polygon.Type != api.Polygon || len(req.Polygon.Coordinates) == 0 {
+		return d, ErrInvalidZone
+	}
+	for _, ring := range req.Polygon.Coordinates {
+		if len(ring) < 4 { return d, ErrInvalidZone }
+		var r zoneRing
+		for _, pos := range ring {
+			if len(pos) < 2 { return d, ErrInvalidZone }
+			p := api.GeoPoint{Lat: pos[1], Lng: pos[0]}
+			if !validLocation(p) { return d, ErrInvalidZone }
+			r.Points = append(r.Points, p)
+		}
+		if r.Points[0] != r.Points[len(r.Points)-1] { return d, ErrInvalidZone }
+		d.Rings = append(d.Rings, r)
+	}
+
+	if req.Active != nil { d.Active = *req.Active }
+	if req.Timezone != nil && *req.Timezone != "" { d.Timezone = *req.Timezone }
+	if _, err := time.LoadLocation(d.Timezone); err != nil {
+		return d, fmt.Errorf("%w: unknown time zone %q", ErrInvalidZoneRules, d.Timezone)
+	}
+	if req.Hours != nil {
+		for _, h := range *req.Hours {
+			_, okOpen := clockMinutes(h.Open)
+			_, okClose := clockMinutes(h.Close)
+			if !okOpen || !okClose || h.Open == "24:00" || len(h.Days) == 0 { return d, ErrInvalidZoneRules }
+			for _, day := range h.Days {
+				if day < 1 || day > 7 { return d, ErrInvalidZoneRules }
+			}
+		}
+		d.Hours = *req.Hours
+	}
+	if req.MinPayment != nil && *req.MinPayment < 0 { return d, ErrInvalidZoneRules }
+	d.MinPayment = req.MinPayment
+	if p := req.Pricing; p != nil {
+		for _, v := range []*float64{p.Base, p.PerKm, p.PerMinute, p.Min} {
+			if v != nil && *v < 0 { return d, ErrInvalidZoneRules }
+		}
+	}
+	d.Pricing = req.Pricing
+	return d, nil
+}
+
+// clockMinutes parses HH:MM (up to 24:00) into minutes after midnight.
+func clockMinutes(s string) (int, bool) {
+	if len(s) != 5 || s[2] != ':' { return 0, false }
+	h, errH := strconv.Atoi(s[:2])
+	m, errM := strconv.Atoi(s[3:])
+	if errH != nil || errM != nil || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) { return 0, false }
+	return h*60 + m, true
+}
+
+// compileZone turns a stored zone into its API shape with what lookups need.
+func compileZone(id string, d *zoneDoc) (*zone, error) {
+	loc, err := time.LoadLocation(d.Timezone)
+	if err != nil { return nil, fmt.Errorf("zone %s: %w", id, err) }
+	z := &zone{
+		Zone: api.Zone{
+			Id:         id,
+			Name:       d.Name,
+			Active:     d.Active,
+			Polygon:    api.ZonePolygon{Type: api.Polygon, Coordinates: [][][]float64{}},
+			Timezone:   d.Timezone,
+			Hours:      d.Hours,
+			MinPayment: d.MinPayment,
+			Pricing:    d.Pricing,
+			CreatedAt:  d.CreatedAt,
+			UpdatedAt:  d.UpdatedAt,
+		},
+		loc:    loc,
+		minLat: 90, maxLat: -90, minLng: 180, maxLng: -180,
+	}
+	if z.Hours == nil { z.Hours = []api.ZoneHours{} }
+	for _, r := range d.Rings {
+		ring := make([][]float64, len(r.Points))
+		for i, p := range r.Points {
+			ring[i] = []float64{p.Lng, p.Lat}
+			z.minLat, z.maxLat = min(z.minLat, p.Lat), max(z.maxLat, p.Lat)
+			z.minLng, z.maxLng = min(z.minLng, p.Lng), max(z.maxLng, p.Lng)
+		}
+		z.Polygon.Coordinates = append(z.Polygon.Coordinates, ring)
+	}
+	return z, nil
+}
+
+// contains reports whether p lies inside the polygon (and outside its holes), by ray
+// casting on plain longitude/latitude; fine at city scale, not across the antimeridian.
+func (z *zone) contains(p api.GeoPoint) bool {
+	if p.Lat < z.minLat || p.Lat > z.maxLat || p.Lng < z.minLng || p.Lng > z.maxLng { return false }
+	inside := false
+	for _, ring := range z.Polygon.Coordinates {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			xi, yi := ring[i][0], ring[i][1]
+			xj, yj := ring[j][0], ring[j][1]
+			if (yi > p.Lat) != (yj > p.Lat) && p.Lng < (xj-xi)*(p.Lat-yi)/(yj-yi)+xi {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// openAt reports whether t falls in one of the zone's operating windows.
+func (z *zone) openAt(t time.Time) bool {
+	if len(z.Hours) == 0 { return true }
+	local := t.In(z.loc)
+	minute := local.Hour()*60 + local.Minute()
+	today := isoWeekday(local.Weekday())
+	yesterday := today - 1
+	if yesterday == 0 { yesterday = 7 }
+
+	for _, h := range z.Hours {
+		from, _ := clockMinutes(h.Open)
+		until, _ := clockMinutes(h.Close)
+		if until > from {
+			if slices.Contains(h.Days, today) && minute >= from && minute < until { return true }
+			continue
+		}
+		// runs past midnight: the evening part today, the early part after yesterday's open
+		if slices.Contains(h.Days, today) && minute >= from { return true }
+		if slices.Contains(h.Days, yesterday) && minute < until { return true }
+	}
+	return false
+}
+
+func isoWeekday(d time.Weekday) int {
+	if d == time.Sunday { return 7 }
+	return int(d)
+}
+
+// rates applies the zone's pricing over the defaults.
+func (z *zone) rates(def pricingRates) pricingRates {
+	p := z.Pricing
+	if p == nil { return def }
+	if p.Base != nil { def.base = *p.Base }
+	if p.PerKm != nil { def.perKm = *p.PerKm }
+	if p.PerMinute != nil { def.perMinute = *p.PerMinute }
+	if p.Min != nil { def.min = *p.Min }
+	return def
+}
+
+// inZones reports whether a delivery's zone is one of ids.
+func inZones(zoneID *string, ids []string) bool {
+	return zoneID != nil && slices.Contains(ids, *zoneID)
+}
+
+// zones returns every zone, oldest first, from the cache when fresh enough.
+func (s *ZoneService) zones(ctx context.Context) ([]*zone, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.fetched.IsZero() && time.Since(s.fetched) < s.cacheTTL { return s.cached, nil }
+
+	iter := s.firestore.Collection("zones").OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+	var list []*zone
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return nil, err }
+
+		var d zoneDoc
+		if err := doc.DataTo(&d); err != nil { return nil, err }
+		z, err := compileZone(doc.Ref.ID, &d)
+		if err != nil { return nil, err }
+		list = append(list, z)
+	}
+	s.cached, s.fetched = list, time.Now()
+	return list, nil
+}
+
+// invalidate makes the next lookup reload the zones.
+func (s *ZoneService) invalidate() {
+	s.mu.Lock()
+	s.fetched = time.Time{}
+	s.mu.Unlock()
+}
+
+// locate finds the active zone containing p; where zones overlap the oldest wins.
+// nil without error when no zones are configured, ErrOutsideCoverage when none contains p.
+func (s *ZoneService) locate(ctx context.Context, p api.GeoPoint) (*zone, error) {
+	all, err := s.zones(ctx)
+	if err != nil { return nil, err }
+	if len(all) == 0 { return nil, nil }
+	for _, z := range all {
+		if z.Active && z.contains(p) { return z, nil }
+	}
+	return nil, ErrOutsideCoverage
+}
+
+// GET /zones
+func (s *ZoneService) ListZones(ctx context.Context) ([]*api.Zone, error) {
+	all, err := s.zones(ctx)
+	if err != nil { return nil, err }
+	out := make([]*api.Zone, len(all))
+	for i, z := range all {
+		out[i] = &z.Zone
+	}
+	return out, nil
+}
+
+// GET /zones/{id}
+func (s *ZoneService) GetZone(ctx context.Context, id string) (*api.Zone, error) {
+	doc, err := s.firestore.Collection("zones").Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound { return nil, ErrZoneNotFound }
+	if err != nil { return nil, err }
+	var d zoneDoc
+	if err := doc.DataTo(&d); err != nil { return nil, err }
+	z, err := compileZone(id, &d)
+	if err != nil { return nil, err }
+	return &z.Zone, nil
+}
+
+// POST /zones
+func (s *ZoneService) CreateZone(ctx context.Context, req *api.ZoneCreate) (*api.Zone, error) {
+	d, err := zoneDocFrom(req)
+	if err != nil { return nil, err }
+	now := time.Now().UTC()
+	d.CreatedAt, d.UpdatedAt = now, now
+
+	id := uuid.NewString()
+	_, err = s.firestore.Collection("zones").Doc(id).Create(ctx, d)
+	if err != nil { return nil, err }
+	s.invalidate()
+	z, err := compileZone(id, &d)
+	if err != nil { return nil, err }
+	return &z.Zone, nil
+}
+
+// PUT /zones/{id}
+// UpdateZone replaces a zone's polygon and rules. Deliveries already posted keep their zone.
+func (s *ZoneService) UpdateZone(ctx context.Context, id string, req *api.ZoneCreate) (*api.Zone, error) {
+	d, err := zoneDocFrom(req)
+	if err != nil { return nil, err }
+
+	ref := s.firestore.Collection("zones").Doc(id)
+	err = s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound { return ErrZoneNotFound }
+		if err != nil { return err }
+		var old zoneDoc
+		if err := snap.DataTo(&old); err != nil { return err }
+		d.CreatedAt, d.UpdatedAt = old.CreatedAt, time.Now().UTC()
+		return tx.Set(ref, d)
+	})
+	if err != nil { return nil, err }
+	s.invalidate()
+	z, err := compileZone(id, &d)
+	if err != nil { return nil, err }
+	return &z.Zone, nil
+}
+
+// DELETE /zones/{id}
+// DeleteZone removes a zone and takes it off the couriers assigned to it.
+func (s *ZoneService) DeleteZone(ctx context.Context, id string) error {
+	ref := s.firestore.Collection("zones").Doc(id)
+	if _, err := ref.Get(ctx); err != nil {
+		if status.Code(err) == codes.NotFound { return ErrZoneNotFound }
+		return err
+	}
+	if _, err := ref.Delete(ctx); err != nil { return err }
+	s.invalidate()
+
+	iter := s.firestore.Collection("users").Where("zoneIds", "array-contains", id).Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done { break }
+		if err != nil { return err }
+		_, err = doc.Ref.Update(ctx, []firestore.Update{{Path: "zoneIds", Value: firestore.ArrayRemove(id)}})
+		if err != nil { return err }
+	}
+	return nil
+}
+
+// PUT /couriers/{id}/zones
+// AssignCourier sets the zones a courier works in; an empty list lets them work everywhere.
+func (s *ZoneService) AssignCourier(ctx context.Context, courierUID string, zoneIDs []string) (*api.CourierUser, error) {
+	ids := []string{}
+	for _, id := range zoneIDs {
+		if id == "" { return nil, ErrUnknownZone }
+		if !slices.Contains(ids, id) { ids = append(ids, id) }
+	}
+	refs := make([]*firestore.DocumentRef, len(ids))
+	for i, id := range ids {
+		refs[i] = s.firestore.Collection("zones").Doc(id)
+	}
+	snaps, err := s.firestore.GetAll(ctx, refs)
+	if err != nil { return nil, err }
+	for _, snap := range snaps {
+		if !snap.Exists() { return nil, fmt.Errorf("%w: %s", ErrUnknownZone, snap.Ref.ID) }
+	}
+
+	userRef := s.firestore.Collection("users").Doc(courierUID)
+	var courier api.CourierUser
+	err = s.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(userRef)
+		if status.Code(err) == codes.NotFound { return ErrCourierNotFound }
+		if err != nil { return err }
+		if err := snap.DataTo(&courier); err != nil { return err }
+		if courier.Role != api.Courier { return ErrCourierNotFound }
+
+		var value any = firestore.Delete
+		if len(ids) > 0 { value = ids }
+		return tx.Update(userRef, []firestore.Update{{Path: "zoneIds", Value: value}})
+	})
+	if err != nil { return nil, err }
+
+	courier.Id = courierUID
+	courier.ZoneIds = nil
+	if len(ids) > 0 { courier.ZoneIds = &ids }
+	return &courier, nil
+}
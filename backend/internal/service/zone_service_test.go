@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/api"
+)
+
+// testZone compiles a zone from [lng, lat] rings, the outer one first.
+func testZone(t *testing.T, timezone string, hours []api.ZoneHours, rings ...[][2]float64) *zone {
+	t.Helper()
+	d := zoneDoc{Name: "test", Active: true, Timezone: timezone, Hours: hours}
+	for _, ring := range rings {
+		var r zoneRing
+		for _, pos := range ring {
+			r.Points = append(r.Points, api.GeoPoint{Lng: pos[0], Lat: pos[1]})
+		}
+		d.Rings = append(d.Rings, r)
+	}
+	z, err := compileZone("z1", &d)
+	if err != nil { t.Skip("no time zone data:", err) }
+	return z
+}
+
+func TestZoneContains(t *testing.T) {
+	square := [][2]float64{{13.0, 52.0}, {14.0, 52.0}, {14.0, 53.0}, {13.0, 53.0}, {13.0, 52.0}}
+	hole := [][2]float64{{13.4, 52.4}, {13.6, 52.4}, {13.6, 52.6}, {13.4, 52.6}, {13.4, 52.4}}
+	z := testZone(t, "UTC", nil, square, hole)
+
+	tests := []struct {
+		name string
+		p    api.GeoPoint
+		want bool
+	}{
+		{"inside", api.GeoPoint{Lat: 52.2, Lng: 13.2}, true},
+		{"inside near the hole", api.GeoPoint{Lat: 52.5, Lng: 13.35}, true},
+		{"in the hole", api.GeoPoint{Lat: 52.5, Lng: 13.5}, false},
+		{"north of it", api.GeoPoint{Lat: 53.1, Lng: 13.5}, false},
+		{"east of it", api.GeoPoint{Lat: 52.5, Lng: 14.1}, false},
+		{"west of it", api.GeoPoint{Lat: 52.5, Lng: 12.9}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := z.contains(tt.p); got != tt.want { t.Errorf("contains(%v) = %v, want %v", tt.p, got, tt.want) }
+		})
+	}
+
+	// a triangle: inside its bounding box isn't inside it
+	tri := testZone(t, "UTC", nil, [][2]float64{{0, 0}, {2, 0}, {0, 2}, {0, 0}})
+	if !tri.contains(api.GeoPoint{Lat: 0.5, Lng: 0.5}) { t.Error("triangle should contain (0.5, 0.5)") }
+	if tri.contains(api.GeoPoint{Lat: 1.5, Lng: 1.5}) { t.Error("triangle shouldn't contain (1.5, 1.5)") }
+}
+
+func TestZoneOpenAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil { t.Skip("no time zone data:", err) }
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 6, day, hour, minute, 0, 0, berlin) } // 2 June 2025 is a Monday
+
+	square := [][2]float64{{13.0, 52.0}, {14.0, 52.0}, {14.0, 53.0}, {13.0, 53.0}, {13.0, 52.0}}
+	tests := []struct {
+		name  string
+		hours []api.ZoneHours
+		t     time.Time
+		want  bool
+	}{
+		{"no hours is always open", nil, at(2, 3, 0), true},
+		{"weekday daytime", []api.ZoneHours{{Days: []int{1, 2, 3, 4, 5}, Open: "08:00", Close: "20:00"}}, at(2, 8, 0), true},
+		{"close is exclusive", []api.ZoneHours{{Days: []int{1}, Open: "08:00", Close: "20:00"}}, at(2, 20, 0), false},
+		{"before opening", []api.ZoneHours{{Days: []int{1}, Open: "08:00", Close: "20:00"}}, at(2, 7, 59), false},
+		{"other weekday", []api.ZoneHours{{Days: []int{1}, Open: "08:00", Close: "20:00"}}, at(3, 12, 0), false},
+		{"sunday is 7", []api.ZoneHours{{Days: []int{7}, Open: "08:00", Close: "20:00"}}, at(8, 12, 0), true},
+		{"until midnight", []api.ZoneHours{{Days: []int{1}, Open: "18:00", Close: "24:00"}}, at(2, 23, 59), true},
+		{"24:00 ends at midnight", []api.ZoneHours{{Days: []int{1}, Open: "18:00", Close: "24:00"}}, at(3, 0, 0), false},
+		{"overnight, evening part", []api.ZoneHours{{Days: []int{5}, Open: "22:00", Close: "02:00"}}, at(6, 23, 30), true},
+		{"overnight, after midnight", []api.ZoneHours{{Days: []int{5}, Open: "22:00", Close: "02:00"}}, at(7, 1, 59), true},
+		{"overnight, closed at its end", []api.ZoneHours{{Days: []int{5}, Open: "22:00", Close: "02:00"}}, at(7, 2, 0), false},
+		{"overnight, not before the listed day", []api.ZoneHours{{Days: []int{5}, Open: "22:00", Close: "02:00"}}, at(6, 1, 0), false},
+		{"overnight from sunday into monday", []api.ZoneHours{{Days: []int{7}, Open: "22:00", Close: "02:00"}}, at(9, 1, 0), true},
+		{"local time, not UTC", []api.ZoneHours{{Days: []int{1}, Open: "00:00", Close: "01:00"}}, time.Date(2025, 6, 1, 22, 30, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z := testZone(t, "Europe/Berlin", tt.hours, square)
+			if got := z.openAt(tt.t); got != tt.want { t.Errorf("openAt(%v) = %v, want %v", tt.t, got, tt.want) }
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// bundleErrStatus maps bundle errors to HTTP status codes.
+func bundleErrStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidBundle), errors.Is(err, service.ErrOverCapacity),
+		errors.Is(err, service.ErrActiveLimitReached), errors.Is(err, service.ErrOutsideCourierZones), errors.Is(err, service.ErrNotReleased):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrBundleUnavailable):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GET /deliveries/bundles
+// suggests posted deliveries the calling courier could ride together, around (lat,lng).
+func (h *Handler) ListDeliveryBundles(c *gin.Context, params ListDeliveryBundlesParams) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	courier, err := h.userSvc.GetCourierInfo(c, courierUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	status := service.StatusPosted
+	room := h.deliverySvc.RemainingCapacity(courier)
+	flt := service.ListFilter{Role: "courier", CourierID: courierUID, Status: &status, Room: &room}
+	if courier.ZoneIds != nil { flt.ZoneIds = *courier.ZoneIds }
+	if params.Lat != nil && params.Lng != nil {
+		flt.CenterLat = params.Lat
+		flt.CenterLng = params.Lng
+		flt.RadiusKm = params.R
+	}
+
+	bundles, err := h.deliverySvc.SuggestBundles(c, flt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	for _, b := range bundles {
+		for i := range b.Deliveries {
+			b.Deliveries[i] = service.RedactForCourier(b.Deliveries[i], courierUID)
+		}
+	}
+	c.JSON(http.StatusOK, bundles)
+}
+
+// POST /deliveries/bundles/accept
+// lets the calling courier accept a whole bundle; either every delivery is theirs or none.
+func (h *Handler) AcceptDeliveryBundle(c *gin.Context) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	var req BundleAccept
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	accepted, err := h.deliverySvc.AcceptBundle(c, req.DeliveryIds, courierUID)
+	if err != nil {
+		c.JSON(bundleErrStatus(err), errBody(err))
+		return
+	}
+	for _, d := range accepted {
+		*d = service.RedactForCourier(*d, courierUID)
+	}
+	c.JSON(http.StatusOK, accepted)
+}
@@ -0,0 +1,65 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// earningsErrStatus maps earnings errors to HTTP status codes.
+func earningsErrStatus(err error) int {
+	if errors.Is(err, service.ErrInvalidRange) || errors.Is(err, service.ErrUnsupportedFormat) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GET /couriers/me/earnings
+// from/to are whole UTC days, both included; default is the last 30 days grouped by day.
+func (h *Handler) GetMyEarnings(c *gin.Context, params GetMyEarningsParams) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if params.To != nil {
+		d := params.To.Time
+		to = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	from := to.AddDate(0, 0, -30)
+	if params.From != nil {
+		d := params.From.Time
+		from = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	groupBy := "day"
+	if params.GroupBy != nil { groupBy = string(*params.GroupBy) }
+
+	// the service works on [from, to), so the last day is included by moving to one day on
+	report, err := h.earningsSvc.GetEarnings(c, courierUID, from, to.AddDate(0, 0, 1), groupBy)
+	if err != nil {
+		c.JSON(earningsErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GET /couriers/me/statements/{month}
+// downloads one month (YYYY-MM) as PDF (default) or CSV.
+func (h *Handler) GetMyStatement(c *gin.Context, month string, params GetMyStatementParams) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	format := ""
+	if params.Format != nil { format = string(*params.Format) }
+
+	data, contentType, fileName, err := h.earningsSvc.Statement(c, courierUID, month, format)
+	if err != nil {
+		c.JSON(earningsErrStatus(err), errBody(err))
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -14,15 +14,32 @@ import (
 //
 // userSvc: identity/role lookups and user data (who is calling? what role? fetch business/courier info)
 // deliverySvc: delivery domain logic (create/list/accept/update with transactions)
+// payoutSvc: courier withdrawals, admin review and payout batches
+// earningsSvc: courier earnings reports and monthly statements
+// invoiceSvc: monthly business invoices
+// locationHub: live courier positions for the WebSocket maps
+// webhookSvc: business webhook registrations and their call log
+// notificationSvc: users' email/SMS notification preferences
+// pushSvc: couriers' web push subscriptions
+// zoneSvc: service zones and which couriers work in them
 // Splitting responsibilities keeps HTTP concerns thin and enforces separation between user/authorization data and delivery workflow logic.
 type Handler struct {
 	deliverySvc *service.DeliveryService
 	userSvc *service.UserService
+	payoutSvc *service.PayoutService
+	earningsSvc *service.EarningsService
+	invoiceSvc *service.InvoiceService
+	locationHub *service.LocationHub
+	webhookSvc *service.WebhookService
+	notificationSvc *service.NotificationService
+	pushSvc *service.PushService
+	zoneSvc *service.ZoneService
+	templateSvc *service.TemplateService
 }
 
-// NewHandler wires the HTTP layer to the delivery and user services.
-func NewHandler(d *service.DeliveryService, u *service.UserService) *Handler {
-	return &Handler{deliverySvc: d, userSvc: u}
+// NewHandler wires the HTTP layer to the domain services and the location hub.
+func NewHandler(d *service.DeliveryService, u *service.UserService, p *service.PayoutService, e *service.EarningsService, i *service.InvoiceService, l *service.LocationHub, w *service.WebhookService, n *service.NotificationService, ps *service.PushService, z *service.ZoneService, t *service.TemplateService) *Handler {
+	return &Handler{deliverySvc: d, userSvc: u, payoutSvc: p, earningsSvc: e, invoiceSvc: i, locationHub: l, webhookSvc: w, notificationSvc: n, pushSvc: ps, zoneSvc: z, templateSvc: t}
 }
 
 // POST /deliveries 
@@ -61,10 +78,29 @@ func (h *Handler) CreateDelivery(c *gin.Context) {
         DestinationLocation: api.GeoPoint{Lat: req.DestinationLocation.Lat, Lng: req.DestinationLocation.Lng},
         Item:                req.Item,
 		Payment:			 req.Payment,
+		RecipientName:       req.RecipientName,
+		RecipientPhone:      req.RecipientPhone,
+		RecipientEmail:      req.RecipientEmail,
+		Size:                (*api.DeliverySize)(req.Size),
+		WeightKg:            req.WeightKg,
+		PickupAfter:         req.PickupAfter,
+		DeliverBefore:       req.DeliverBefore,
     }
+	if req.Parcels != nil {
+		parcels := make([]api.ParcelCreate, len(*req.Parcels))
+		for i, p := range *req.Parcels {
+			parcels[i] = api.ParcelCreate(p)
+		}
+		apiReq.Parcels = &parcels
+	}
 
 
 	response, err := h.deliverySvc.CreateDelivery(ctx, &apiReq, creatorUID)
+	if errors.Is(err, service.ErrInvalidRecipient) || errors.Is(err, service.ErrInvalidParcel) || errors.Is(err, service.ErrInvalidParcels) || errors.Is(err, service.ErrInvalidWindow) ||
+		errors.Is(err, service.ErrOutsideCoverage) || errors.Is(err, service.ErrZoneClosed) || errors.Is(err, service.ErrBelowZoneMinimum) {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errBody(err))
 		return
@@ -95,26 +131,24 @@ func (h *Handler) ListDeliveries(c *gin.Context, params ListDeliveriesParams) {
 		flt.CenterLng = params.Lng
 		flt.RadiusKm  = params.R
 	}
+	if params.Sort != nil && *params.Sort == Distance {            // ?sort=distance
+		if params.Lat == nil || params.Lng == nil {
+			c.JSON(http.StatusBadRequest, errBody(errors.New("sort=distance needs lat and lng")))
+			return
+		}
+		flt.CenterLat = params.Lat
+		flt.CenterLng = params.Lng
+		flt.SortByDistance = true
+	}
 	// get user's role
 	userUID := c.GetString("uid") // set by (future) auth middleware
-	ctx := context.Background()
-	role, err := h.userSvc.GetUserRole(ctx, userUID)
-	if err != nil {
+	if err := h.applyRole(context.Background(), &flt, userUID); err != nil {
 		c.JSON(500, errBody(err))
 		return
 	}
-	// else the role is fine and defined
-	flt.Role = role
-	if role == "business"{
-		info, err := h.userSvc.GetBusinessInfo(ctx, userUID)
-		if err != nil {
-			c.JSON(500, errBody(err))
-			return
-		} 
-		flt.BusinessName = info.BusinessName
-	}
-	if role == "courier"{
-		flt.CourierID = userUID
+	// ?sort=urgency, and what couriers get unless they asked otherwise
+	if (params.Sort != nil && *params.Sort == Urgency) || (params.Sort == nil && flt.Role == "courier") {
+		flt.SortByUrgency = true
 	}
 
 	list, nextCursor, err := h.deliverySvc.ListDeliveries(c, flt)
@@ -122,10 +156,39 @@ func (h *Handler) ListDeliveries(c *gin.Context, params ListDeliveriesParams) {
 		c.JSON(500, errBody(err))
 		return
 	}
+	if flt.Role == "courier" {
+		for _, d := range list {
+			*d = service.RedactForCourier(*d, userUID)
+		}
+	}
 	c.Header("X-Next-Page-Token", nextCursor)
 	c.JSON(200, list)
 }
 
+// applyRole fills the role part of a delivery filter for the caller:
+// businesses see only their own deliveries, couriers nearby posted + their assigned ones.
+// Shared by ListDeliveries and StreamDeliveries so both apply the same visibility.
+func (h *Handler) applyRole(ctx context.Context, flt *service.ListFilter, userUID string) error {
+	role, err := h.userSvc.GetUserRole(ctx, userUID)
+	if err != nil { return err }
+
+	// else the role is fine and defined
+	flt.Role = role
+	if role == "business"{
+		info, err := h.userSvc.GetBusinessInfo(ctx, userUID)
+		if err != nil { return err }
+		flt.BusinessName = info.BusinessName
+	}
+	if role == "courier"{
+		flt.CourierID = userUID
+		info, err := h.userSvc.GetCourierInfo(ctx, userUID)
+		if err != nil { return err }
+		room := h.deliverySvc.RemainingCapacity(info)
+		flt.Room = &room
+		if info.ZoneIds != nil { flt.ZoneIds = *info.ZoneIds }
+	}
+	return nil
+}
 
 // POST / deliveries/id/accept
 // lets an authenticated courier accept a posted delivery.
@@ -150,12 +213,16 @@ func (h *Handler) AcceptDelivery(c *gin.Context, deliveryID string) {
 	}
 	// here it's only a courier
 	updated, err := h.deliverySvc.AcceptDelivery(c, deliveryID, courierUID.(string))
+	if err == nil { *updated = service.RedactForCourier(*updated, courierUID.(string)) }
 
 	switch {
 	case err == nil:
 		c.JSON(http.StatusOK, updated)
 	// case errors.Is(err, service.ErrAlreadyAssigned):
 	// 	c.JSON(http.StatusConflict ,errBody(errors.New("delivery already taken")))
+	case errors.Is(err, service.ErrOverCapacity), errors.Is(err, service.ErrActiveLimitReached), errors.Is(err, service.ErrOutsideCourierZones),
+		errors.Is(err, service.ErrNotReleased):
+		c.JSON(http.StatusBadRequest, errBody(err))
 	default:
 		var bad service.ErrInvalidTransition
 		if errors.As(err, &bad) {
@@ -167,8 +234,9 @@ func (h *Handler) AcceptDelivery(c *gin.Context, deliveryID string) {
 }
 
 // PATCH / deliveries/id/
-// updates delivery status for the assigned courier.
-// Flow: bind patch - ensure role=courier via userSvc - delegate to deliverySvc.UpdateDeliveryStatus - map invalid transition/update to 400.
+// updates delivery status for the assigned courier, or lets the owning business edit a posted delivery.
+// Flow: bind patch - resolve role via userSvc - courier: delegate to deliverySvc.UpdateDeliveryStatus - map invalid transition/update to 400;
+// business: delegate to editDelivery.
 func (h *Handler) UpdateDelivery(c *gin.Context, deliveryID string) {
 
 	// parse & validate JSON body
@@ -179,11 +247,6 @@ func (h *Handler) UpdateDelivery(c *gin.Context, deliveryID string) {
 		return
 	}
 
-	if patch.Status == nil { // the minimal status is accepted != nil
-		c.JSON(http.StatusBadRequest, errBody(errors.New("status field is required")))
-		return
-	}
-
 	// authenticated courier UID from Gin context
 	courierUID, ok := c.Get("uid")                // set by auth middleware
 	if !ok || courierUID == "" {
@@ -198,18 +261,33 @@ func (h *Handler) UpdateDelivery(c *gin.Context, deliveryID string) {
 		return
 	}
 
+	if role == "business" {
+		h.editDelivery(c, deliveryID, courierUID.(string), &patch)
+		return
+	}
 	if role != "courier"{
 		c.JSON(http.StatusBadRequest, errBody(errors.New("Only courier can update a delivery")))
 		return
 	}
+	if patch.Item != nil || patch.DestinationAddress != nil || patch.DestinationLocation != nil || patch.Payment != nil {
+		c.JSON(http.StatusBadRequest, errBody(errors.New("couriers can only change the status")))
+		return
+	}
+	if patch.Status == nil { // the minimal status is accepted != nil
+		c.JSON(http.StatusBadRequest, errBody(errors.New("status field is required")))
+		return
+	}
 	// here its only a courier
 	updated, err := h.deliverySvc.UpdateDeliveryStatus(c, deliveryID, string(*patch.Status), courierUID.(string))
+	if err == nil { *updated = service.RedactForCourier(*updated, courierUID.(string)) }
 
 
 	// map service-level errors to HTTP responses
 	var InvalidTransition service.ErrInvalidTransition
 	//var InvalidUpdate service.ErrInvalidUpdate
-	if errors.As(err, &InvalidTransition) || errors.Is(err,service.ErrInvalidUpdate) {
+	if errors.As(err, &InvalidTransition) || errors.Is(err,service.ErrInvalidUpdate) || errors.Is(err, service.ErrBeforePickupWindow) ||
+		errors.Is(err, service.ErrUnscannedParcels) || errors.Is(err, service.ErrNotFailable) ||
+		errors.Is(err, service.ErrNothingDelivered) {
 		c.JSON(http.StatusBadRequest, errBody(err))
 	} else if err == nil {
 		c.JSON(http.StatusOK, updated)
@@ -218,6 +296,108 @@ func (h *Handler) UpdateDelivery(c *gin.Context, deliveryID string) {
 	}
 }
 
+// editDelivery is the business side of PATCH /deliveries/{id}: item, destination and payment
+// of a delivery no courier accepted yet.
+func (h *Handler) editDelivery(c *gin.Context, deliveryID, businessUID string, patch *DeliveryPatch) {
+	if patch.Status != nil || patch.AssignedTo != nil {
+		c.JSON(http.StatusBadRequest, errBody(errors.New("only couriers change the status")))
+		return
+	}
+	edit := service.DeliveryEdit{
+		Item:               patch.Item,
+		DestinationAddress: patch.DestinationAddress,
+		Payment:            patch.Payment,
+	}
+	if patch.DestinationLocation != nil {
+		edit.DestinationLocation = &api.GeoPoint{Lat: patch.DestinationLocation.Lat, Lng: patch.DestinationLocation.Lng}
+	}
+
+	updated, err := h.deliverySvc.EditDelivery(c, deliveryID, businessUID, edit)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, updated)
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, errBody(err))
+	case errors.Is(err, service.ErrNotEditable):
+		c.JSON(http.StatusConflict, errBody(err))
+	case errors.Is(err, service.ErrInvalidEdit), errors.Is(err, service.ErrNotDeliveryOwner),
+		errors.Is(err, service.ErrOutsideCoverage), errors.Is(err, service.ErrZoneClosed),
+		errors.Is(err, service.ErrBelowZoneMinimum):
+		c.JSON(http.StatusBadRequest, errBody(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errBody(err))
+	}
+}
+
+// POST /deliveries/{id}/tip
+// lets the owning business tip the courier that delivered.
+// Flow: bind tip - ensure role=business via userSvc - delegate to deliverySvc.TipDelivery - map rule violations to 400.
+func (h *Handler) TipDelivery(c *gin.Context, deliveryID string) {
+	var req TipCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	businessUID := c.GetString("uid")
+	ctx := context.Background()
+	role, err := h.userSvc.GetUserRole(ctx, businessUID)
+	if err != nil {
+		c.JSON(500, errBody(err))
+		return
+	}
+	if role != "business" {
+		c.JSON(http.StatusBadRequest, errBody(errors.New("Only business can tip a delivery")))
+		return
+	}
+
+	updated, err := h.deliverySvc.TipDelivery(c, deliveryID, businessUID, req.Amount)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, updated)
+	case errors.Is(err, service.ErrInvalidAmount),
+		errors.Is(err, service.ErrNotDeliveryOwner),
+		errors.Is(err, service.ErrNotDelivered),
+		errors.Is(err, service.ErrAlreadyTipped),
+		errors.Is(err, service.ErrTipWindowClosed):
+		c.JSON(http.StatusBadRequest, errBody(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errBody(err))
+	}
+}
+
+// POST /deliveries/{id}/rating
+// the delivery's business rates its courier, or the courier rates the business; once each.
+func (h *Handler) RateDelivery(c *gin.Context, deliveryID string) {
+	var req RatingCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	raterUID := c.GetString("uid")
+	if raterUID == "" {
+		c.JSON(http.StatusUnauthorized, errBody(errors.New("missing auth UID")))
+		return
+	}
+
+	updated, err := h.deliverySvc.RateDelivery(c, deliveryID, raterUID, req.Score, req.Comment)
+	if err == nil && (updated.BusinessId == nil || *updated.BusinessId != raterUID) {
+		*updated = service.RedactForCourier(*updated, raterUID)
+	}
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, updated)
+	case errors.Is(err, service.ErrInvalidScore),
+		errors.Is(err, service.ErrNotDeliveryParty),
+		errors.Is(err, service.ErrNotDelivered),
+		errors.Is(err, service.ErrAlreadyRated):
+		c.JSON(http.StatusBadRequest, errBody(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errBody(err))
+	}
+}
+
 func errBody(e error) Error {
 	msg := e.Error()
 	return Error{Message: &msg}
@@ -301,6 +481,23 @@ func (h *Handler) ListCouriers(c *gin.Context) {
 	c.JSON(http.StatusOK, couriers)
 }
 
+// GET couriers/low-rated
+// returns couriers rated below a threshold (default 3.5, at least 3 ratings); restricted to role=admin.
+func (h *Handler) ListLowRatedCouriers(c *gin.Context, params ListLowRatedCouriersParams) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	below, minRatings := 3.5, 3
+	if params.Below != nil { below = *params.Below }
+	if params.MinRatings != nil { minRatings = *params.MinRatings }
+
+	couriers, err := h.userSvc.GetLowRatedCouriers(c, below, minRatings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, couriers)
+}
+
 // GET businesses/
 // returns all businesses; restricted to role=admin (checked via userSvc).
 func (h *Handler) ListBusinesses(c *gin.Context) {
@@ -0,0 +1,82 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// invoiceErrStatus maps invoice errors to HTTP status codes.
+func invoiceErrStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvoiceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidRange),
+		errors.Is(err, service.ErrMonthNotSettled),
+		errors.Is(err, service.ErrUnsupportedFormat):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GET /businesses/me/invoices
+// lists the caller's own invoices.
+func (h *Handler) ListMyInvoices(c *gin.Context) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	invoices, err := h.invoiceSvc.ListInvoices(c, businessUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, invoices)
+}
+
+// GET /businesses/me/invoices/{id}
+// downloads one invoice as PDF (default) or JSON.
+func (h *Handler) GetMyInvoice(c *gin.Context, id string, params GetMyInvoiceParams) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	format := ""
+	if params.Format != nil { format = string(*params.Format) }
+
+	data, contentType, fileName, err := h.invoiceSvc.RenderInvoice(c, id, businessUID, format)
+	if err != nil {
+		c.JSON(invoiceErrStatus(err), errBody(err))
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// POST /invoices/generate
+// runs invoicing for one month (YYYY-MM, default previous month) right away; admin only.
+// The background scheduler does the same, this is for re-runs and backfills. Like the
+// scheduler it refuses a month whose tip window hasn't closed yet.
+func (h *Handler) GenerateInvoices(c *gin.Context, params GenerateInvoicesParams) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	if params.Month != nil {
+		m, err := time.Parse("2006-01", *params.Month)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errBody(service.ErrInvalidRange))
+			return
+		}
+		month = m
+	}
+
+	created, err := h.invoiceSvc.GenerateInvoices(c, month)
+	if err != nil {
+		c.JSON(invoiceErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
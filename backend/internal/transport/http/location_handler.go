@@ -0,0 +1,95 @@
+package httptransport
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/internal/auth"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// activeCourierRefresh is how often a business's set of followable couriers is re-read,
+// so couriers appear when they accept its delivery and disappear once they deliver.
+const activeCourierRefresh = 15 * time.Second
+
+// locationMessage is the only message the server sends on the location socket.
+type locationMessage struct {
+	Type      string                    `json:"type"`
+	Locations []service.CourierLocation `json:"locations"`
+}
+
+// GET /couriers/locations/ws
+// WebSocket of live courier positions. Admins get every courier, a business only the
+// couriers carrying its accepted/picked-up deliveries. Authenticated by auth.Middleware,
+// which also accepts the token offered as a subprotocol on the handshake.
+func (h *Handler) StreamCourierLocations(c *gin.Context) {
+	uid := c.GetString("uid")
+	role, err := h.userSvc.GetUserRole(context.Background(), uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	if role != "admin" && role != "business" {
+		c.JSON(http.StatusBadRequest, errBody(errors.New("only admins and businesses can follow couriers")))
+		return
+	}
+
+	sub := h.locationHub.Subscribe(role == "admin")
+	defer h.locationHub.Unsubscribe(sub)
+
+	websocket.Server{Handshake: selectTokenProtocol, Handler: func(ws *websocket.Conn) {
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+
+		// clients don't send anything; reading only tells us when they go away
+		go func() {
+			var ignored []byte
+			for websocket.Message.Receive(ws, &ignored) == nil {}
+			cancel()
+		}()
+
+		refresh := func() {
+			if role != "business" { return }
+			ids, err := h.locationHub.ActiveCourierIDs(ctx, uid)
+			if err != nil {
+				log.Printf("active couriers for %s: %v", uid, err)
+				return
+			}
+			h.locationHub.SetCouriers(sub, ids)
+		}
+		refresh()
+
+		ticker := time.NewTicker(activeCourierRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refresh()
+			case <-sub.Notify:
+				msg := locationMessage{Type: "locations", Locations: sub.Drain()}
+				if err := websocket.JSON.Send(ws, msg); err != nil { return }
+			}
+		}
+	}}.ServeHTTP(c.Writer, c.Request)
+}
+
+// selectTokenProtocol answers a handshake that authenticated with auth.WebSocketProtocol
+// by selecting that subprotocol (never the token itself); browsers drop the connection
+// otherwise. Clients that offered no subprotocols get none.
+func selectTokenProtocol(cfg *websocket.Config, r *http.Request) error {
+	for _, p := range cfg.Protocol {
+		if p == auth.WebSocketProtocol {
+			cfg.Protocol = []string{auth.WebSocketProtocol}
+			return nil
+		}
+	}
+	cfg.Protocol = nil
+	return nil
+}
@@ -0,0 +1,58 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// notificationErrStatus maps notification errors to HTTP status codes.
+func notificationErrStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidPhone),
+		errors.Is(err, service.ErrInvalidNotificationEvent):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GET /me/notification-preferences
+// returns the caller's channels and muted events, or the defaults (email only).
+func (h *Handler) GetMyNotificationPreferences(c *gin.Context) {
+	prefs, err := h.notificationSvc.GetPreferences(c, c.GetString("uid"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// PUT /me/notification-preferences
+// replaces the caller's preferences; SMS needs a phone number.
+func (h *Handler) UpdateMyNotificationPreferences(c *gin.Context) {
+	var req NotificationPreferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+	muted := make([]api.NotificationEvent, len(req.MutedEvents))
+	for i, e := range req.MutedEvents {
+		muted[i] = api.NotificationEvent(e)
+	}
+
+	prefs, err := h.notificationSvc.UpdatePreferences(c, c.GetString("uid"), &api.NotificationPreferences{
+		Email:       req.Email,
+		Sms:         req.Sms,
+		Phone:       req.Phone,
+		MutedEvents: muted,
+	})
+	if err != nil {
+		c.JSON(notificationErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
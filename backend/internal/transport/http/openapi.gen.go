@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 const (
@@ -22,6 +23,12 @@ const (
 	Business BusinessUserRole = "business"
 )
 
+// Defines values for CourierRouteStopType.
+const (
+	Dropoff CourierRouteStopType = "dropoff"
+	Pickup  CourierRouteStopType = "pickup"
+)
+
 // Defines values for CourierUserRole.
 const (
 	Courier CourierUserRole = "courier"
@@ -31,92 +38,615 @@ const (
 const (
 	DeliveryStatusAccepted  DeliveryStatus = "accepted"
 	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusExpired   DeliveryStatus = "expired"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
 	DeliveryStatusPickedUp  DeliveryStatus = "picked_up"
 	DeliveryStatusPosted    DeliveryStatus = "posted"
 )
 
+// Defines values for DeliveryAutoActionKind.
+const (
+	DeliveryAutoActionKindExpired  DeliveryAutoActionKind = "expired"
+	DeliveryAutoActionKindReminded DeliveryAutoActionKind = "reminded"
+	DeliveryAutoActionKindRepriced DeliveryAutoActionKind = "repriced"
+)
+
 // Defines values for DeliveryPatchStatus.
 const (
 	DeliveryPatchStatusAccepted  DeliveryPatchStatus = "accepted"
 	DeliveryPatchStatusDelivered DeliveryPatchStatus = "delivered"
+	DeliveryPatchStatusFailed    DeliveryPatchStatus = "failed"
 	DeliveryPatchStatusPickedUp  DeliveryPatchStatus = "picked_up"
 )
 
+// Defines values for DeliverySize.
+const (
+	Large  DeliverySize = "large"
+	Medium DeliverySize = "medium"
+	Small  DeliverySize = "small"
+	Xlarge DeliverySize = "xlarge"
+)
+
+// Defines values for EarningsReportGroupBy.
+const (
+	EarningsReportGroupByDay   EarningsReportGroupBy = "day"
+	EarningsReportGroupByMonth EarningsReportGroupBy = "month"
+	EarningsReportGroupByWeek  EarningsReportGroupBy = "week"
+)
+
+// Defines values for NotificationEvent.
+const (
+	NotificationEventDeliveryAccepted      NotificationEvent = "delivery.accepted"
+	NotificationEventDeliveryDelivered     NotificationEvent = "delivery.delivered"
+	NotificationEventDeliveryExpired       NotificationEvent = "delivery.expired"
+	NotificationEventDeliveryFailed        NotificationEvent = "delivery.failed"
+	NotificationEventDeliveryParcelMissing NotificationEvent = "delivery.parcel_missing"
+	NotificationEventDeliveryPickedUp      NotificationEvent = "delivery.picked_up"
+	NotificationEventDeliveryRepriced      NotificationEvent = "delivery.repriced"
+	NotificationEventDeliveryUnaccepted    NotificationEvent = "delivery.unaccepted"
+	NotificationEventJobPosted             NotificationEvent = "job.posted"
+)
+
+// Defines values for ParcelStatus.
+const (
+	ParcelStatusDelivered ParcelStatus = "delivered"
+	ParcelStatusMissing   ParcelStatus = "missing"
+	ParcelStatusPending   ParcelStatus = "pending"
+	ParcelStatusPickedUp  ParcelStatus = "picked_up"
+)
+
+// Defines values for ParcelScanEvent.
+const (
+	ParcelScanEventDelivered ParcelScanEvent = "delivered"
+	ParcelScanEventMissing   ParcelScanEvent = "missing"
+	ParcelScanEventPickedUp  ParcelScanEvent = "picked_up"
+)
+
+// Defines values for PayoutStatus.
+const (
+	PayoutStatusApproved PayoutStatus = "approved"
+	PayoutStatusPaid     PayoutStatus = "paid"
+	PayoutStatusPending  PayoutStatus = "pending"
+	PayoutStatusRejected PayoutStatus = "rejected"
+)
+
+// Defines values for TrackingStatus.
+const (
+	TrackingStatusAccepted  TrackingStatus = "accepted"
+	TrackingStatusDelivered TrackingStatus = "delivered"
+	TrackingStatusExpired   TrackingStatus = "expired"
+	TrackingStatusFailed    TrackingStatus = "failed"
+	TrackingStatusPickedUp  TrackingStatus = "picked_up"
+	TrackingStatusPosted    TrackingStatus = "posted"
+)
+
+// Defines values for VehicleType.
+const (
+	Bicycle   VehicleType = "bicycle"
+	Car       VehicleType = "car"
+	CargoBike VehicleType = "cargo_bike"
+	Scooter   VehicleType = "scooter"
+	Van       VehicleType = "van"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "dead"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+)
+
+// Defines values for WebhookEvent.
+const (
+	WebhookEventDeliveryAccepted  WebhookEvent = "delivery.accepted"
+	WebhookEventDeliveryDelivered WebhookEvent = "delivery.delivered"
+	WebhookEventDeliveryPickedUp  WebhookEvent = "delivery.picked_up"
+)
+
+// Defines values for ZonePolygonType.
+const (
+	Polygon ZonePolygonType = "Polygon"
+)
+
+// Defines values for GetMyInvoiceParamsFormat.
+const (
+	GetMyInvoiceParamsFormatJson GetMyInvoiceParamsFormat = "json"
+	GetMyInvoiceParamsFormatPdf  GetMyInvoiceParamsFormat = "pdf"
+)
+
+// Defines values for GetMyEarningsParamsGroupBy.
+const (
+	GetMyEarningsParamsGroupByDay   GetMyEarningsParamsGroupBy = "day"
+	GetMyEarningsParamsGroupByMonth GetMyEarningsParamsGroupBy = "month"
+	GetMyEarningsParamsGroupByWeek  GetMyEarningsParamsGroupBy = "week"
+)
+
+// Defines values for GetMyStatementParamsFormat.
+const (
+	GetMyStatementParamsFormatCsv GetMyStatementParamsFormat = "csv"
+	GetMyStatementParamsFormatPdf GetMyStatementParamsFormat = "pdf"
+)
+
 // Defines values for ListDeliveriesParamsStatus.
 const (
 	Accepted  ListDeliveriesParamsStatus = "accepted"
 	Delivered ListDeliveriesParamsStatus = "delivered"
+	Expired   ListDeliveriesParamsStatus = "expired"
+	Failed    ListDeliveriesParamsStatus = "failed"
 	PickedUp  ListDeliveriesParamsStatus = "picked_up"
 	Posted    ListDeliveriesParamsStatus = "posted"
 )
 
+// Defines values for ListDeliveriesParamsSort.
+const (
+	CreatedAt ListDeliveriesParamsSort = "createdAt"
+	Distance  ListDeliveriesParamsSort = "distance"
+	Urgency   ListDeliveriesParamsSort = "urgency"
+)
+
+// Defines values for ListPayoutsParamsStatus.
+const (
+	Approved ListPayoutsParamsStatus = "approved"
+	Paid     ListPayoutsParamsStatus = "paid"
+	Pending  ListPayoutsParamsStatus = "pending"
+	Rejected ListPayoutsParamsStatus = "rejected"
+)
+
+// Defines values for ExportPayoutBatchParamsFormat.
+const (
+	Csv  ExportPayoutBatchParamsFormat = "csv"
+	Sepa ExportPayoutBatchParamsFormat = "sepa"
+)
+
+// BundleAccept defines model for BundleAccept.
+type BundleAccept struct {
+	DeliveryIds []string `firestore:"deliveryIds"`
+}
+
+// BusinessRepricing While enabled, a posted delivery nobody accepted for EXPIRY_AFTER has its payment
+// raised by step, again after each further EXPIRY_AFTER, as long as it stays at or
+// below maxPayment. After that the business is reminded, then the delivery expires.
+type BusinessRepricing struct {
+	Enabled    bool     `firestore:"enabled"`
+	MaxPayment *float64 `firestore:"maxPayment,omitempty"`
+	Step       *float64 `firestore:"step,omitempty"`
+}
+
 // BusinessUser defines model for BusinessUser.
 type BusinessUser struct {
-	BusinessAddress string           `firestore:"businessAddress"`
-	BusinessName    string           `firestore:"businessName"`
-	Email           string           `firestore:"email"`
-	Id              string           `firestore:"id"`
-	Location        GeoPoint         `firestore:"location"`
-	PlaceId         *string          `firestore:"placeId,omitempty"`
-	Role            BusinessUserRole `firestore:"role"`
+	BusinessAddress string   `firestore:"businessAddress"`
+	BusinessName    string   `firestore:"businessName"`
+	Email           string   `firestore:"email"`
+	Id              string   `firestore:"id"`
+	Location        GeoPoint `firestore:"location"`
+	PlaceId         *string  `firestore:"placeId,omitempty"`
+
+	// RatingAverage Average score of all ratings received (0 when unrated)
+	RatingAverage float64 `firestore:"ratingAverage"`
+	RatingCount   int     `firestore:"ratingCount"`
+
+	// Repricing Consent to raise the payment of deliveries no courier accepts
+	Repricing *BusinessRepricing `firestore:"repricing,omitempty"`
+	Role      BusinessUserRole   `firestore:"role"`
 }
 
 // BusinessUserRole defines model for BusinessUser.Role.
 type BusinessUserRole string
 
+// CourierActiveLimit defines model for CourierActiveLimit.
+type CourierActiveLimit struct {
+	// MaxActiveDeliveries Accepted and picked-up deliveries at once; null falls back to MAX_ACTIVE_DELIVERIES
+	MaxActiveDeliveries *int `firestore:"maxActiveDeliveries"`
+}
+
+// CourierLoad defines model for CourierLoad.
+type CourierLoad struct {
+	Jobs     int     `firestore:"jobs"`
+	VolumeL  float64 `firestore:"volumeL"`
+	WeightKg float64 `firestore:"weightKg"`
+}
+
+// CourierRoute defines model for CourierRoute.
+type CourierRoute struct {
+	DistanceKm      float64 `firestore:"distanceKm"`
+	DurationMinutes float64 `firestore:"durationMinutes"`
+
+	// Polyline Route geometry, encoded polyline (precision 5)
+	Polyline string `firestore:"polyline"`
+
+	// Provider Router that measured the route (haversine or osrm)
+	Provider string `firestore:"provider"`
+
+	// Start The courier's last known position; null when it never reported one
+	Start *GeoPoint          `firestore:"start"`
+	Stops []CourierRouteStop `firestore:"stops"`
+}
+
+// CourierRouteStop defines model for CourierRouteStop.
+type CourierRouteStop struct {
+	Address    string `firestore:"address"`
+	DeliveryId string `firestore:"deliveryId"`
+	Item       string `firestore:"item"`
+
+	// LegDistanceKm From the previous stop (or the start) to this one
+	LegDistanceKm      float64              `firestore:"legDistanceKm"`
+	LegDurationMinutes float64              `firestore:"legDurationMinutes"`
+	Location           GeoPoint             `firestore:"location"`
+	Type               CourierRouteStopType `firestore:"type"`
+}
+
+// CourierRouteStopType defines model for CourierRouteStop.Type.
+type CourierRouteStopType string
+
 // CourierUser defines model for CourierUser.
 type CourierUser struct {
-	CourierName string          `firestore:"courierName"`
-	Email       string          `firestore:"email"`
-	Id          string          `firestore:"id"`
-	Role        CourierUserRole `firestore:"role"`
-	Balance     float64         `firestore:"balance"`
+	// ActiveLoad What the courier carries on their accepted and picked-up deliveries
+	ActiveLoad  *CourierLoad `firestore:"activeLoad,omitempty"`
+	Balance     float64      `firestore:"balance"`
+	CourierName string       `firestore:"courierName"`
+	Email       string       `firestore:"email"`
+	Id          string       `firestore:"id"`
+
+	// MaxActiveDeliveries Admin override of MAX_ACTIVE_DELIVERIES for this courier; the vehicle's maxJobs
+	// still applies on top
+	MaxActiveDeliveries *int `firestore:"maxActiveDeliveries,omitempty"`
+
+	// RatingAverage Average score of all ratings received (0 when unrated)
+	RatingAverage float64 `firestore:"ratingAverage"`
+	RatingCount   int     `firestore:"ratingCount"`
+
+	// ReservedBalance Part of balance held by pending/approved payouts
+	ReservedBalance float64         `firestore:"reservedBalance"`
+	Role            CourierUserRole `firestore:"role"`
+
+	// TipsTotal Sum of all tips received, already included in balance
+	TipsTotal float64 `firestore:"tipsTotal"`
+
+	// Vehicle The courier's vehicle and what it carries at once. Capacities left out take the
+	// vehicle type's defaults, and can only be set lower than those.
+	Vehicle *CourierVehicle `firestore:"vehicle,omitempty"`
+
+	// ZoneIds Service zones the courier works in; none means everywhere
+	ZoneIds *[]string `firestore:"zoneIds,omitempty"`
 }
 
 // CourierUserRole defines model for CourierUser.Role.
 type CourierUserRole string
 
+// CourierVehicle The courier's vehicle and what it carries at once. Capacities left out take the
+// vehicle type's defaults, and can only be set lower than those.
+type CourierVehicle struct {
+	// MaxJobs Deliveries carried at the same time
+	MaxJobs     *int        `firestore:"maxJobs,omitempty"`
+	MaxVolumeL  *float64    `firestore:"maxVolumeL,omitempty"`
+	MaxWeightKg *float64    `firestore:"maxWeightKg,omitempty"`
+	Type        VehicleType `firestore:"type"`
+}
+
+// CourierZones defines model for CourierZones.
+type CourierZones struct {
+	ZoneIds []string `firestore:"zoneIds"`
+}
+
 // Delivery defines model for Delivery.
 type Delivery struct {
-	AssignedTo          *string        `firestore:"assignedTo"`
-	BusinessAddress     string         `firestore:"businessAddress"`
-	BusinessId          *string        `firestore:"businessId,omitempty"`
-	BusinessLocation    GeoPoint       `firestore:"businessLocation"`
-	BusinessName        string         `firestore:"businessName"`
-	CreatedAt           *time.Time     `firestore:"createdAt,omitempty"`
-	CreatedBy           *string        `firestore:"createdBy,omitempty"`
-	DeliveredBy         *string        `firestore:"deliveredBy"`
-	DestinationAddress  string         `firestore:"destinationAddress"`
-	DestinationLocation GeoPoint       `firestore:"destinationLocation"`
-	Id                  *string        `firestore:"id,omitempty"`
-	Item                string         `firestore:"item"`
-	Payment             float64        `firestore:"payment"`
-	Status              DeliveryStatus `firestore:"status"`
-}
-
-// DeliveryStatus defines model for Delivery.Status.
+	AcceptedAt *time.Time `firestore:"acceptedAt"`
+	AssignedTo *string    `firestore:"assignedTo"`
+
+	// AutoActions What was done automatically while no courier accepted it, oldest first
+	AutoActions      *[]DeliveryAutoAction `firestore:"autoActions,omitempty"`
+	BusinessAddress  string                `firestore:"businessAddress"`
+	BusinessId       *string               `firestore:"businessId,omitempty"`
+	BusinessLocation GeoPoint              `firestore:"businessLocation"`
+	BusinessName     string                `firestore:"businessName"`
+
+	// BusinessRating The courier's rating of the business
+	BusinessRating *Rating `firestore:"businessRating"`
+
+	// CourierRating The business's rating of the courier
+	CourierRating *Rating    `firestore:"courierRating"`
+	CreatedAt     *time.Time `firestore:"createdAt,omitempty"`
+	CreatedBy     *string    `firestore:"createdBy,omitempty"`
+
+	// DeliverBefore Drop-off deadline
+	DeliverBefore       *time.Time `firestore:"deliverBefore"`
+	DeliveredAt         *time.Time `firestore:"deliveredAt"`
+	DeliveredBy         *string    `firestore:"deliveredBy"`
+	DestinationAddress  string     `firestore:"destinationAddress"`
+	DestinationLocation GeoPoint   `firestore:"destinationLocation"`
+
+	// Eta Estimated pickup and drop-off while a courier is on it
+	Eta       *DeliveryEta `firestore:"eta"`
+	ExpiredAt *time.Time   `firestore:"expiredAt"`
+	FailedAt  *time.Time   `firestore:"failedAt"`
+	Id        *string      `firestore:"id,omitempty"`
+	Item      string       `firestore:"item"`
+
+	// Late Set on delivery; true when delivered after deliverBefore
+	Late *bool `firestore:"late,omitempty"`
+
+	// Parcels The parcels making up the delivery, when the business listed them
+	Parcels    *[]Parcel  `firestore:"parcels,omitempty"`
+	Payment    float64    `firestore:"payment"`
+	PickedUpAt *time.Time `firestore:"pickedUpAt"`
+
+	// PickupAfter Parcel is ready from then on
+	PickupAfter    *time.Time `firestore:"pickupAfter"`
+	RecipientEmail *string    `firestore:"recipientEmail,omitempty"`
+
+	// RecipientName Who receives the parcel at the destination
+	RecipientName *string `firestore:"recipientName,omitempty"`
+
+	// RecipientPhone E.164 number the courier can call on arrival
+	RecipientPhone *string `firestore:"recipientPhone,omitempty"`
+
+	// ReleaseAt When couriers start seeing a scheduled delivery (pickupAfter minus the lead time)
+	ReleaseAt *time.Time `firestore:"releaseAt"`
+
+	// ReleasedAt When a scheduled delivery was announced to couriers, at or shortly after releaseAt
+	ReleasedAt *time.Time `firestore:"releasedAt"`
+
+	// Size Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+	// (60 L), xlarge needs a car (200 L). Omitted means small.
+	Size *DeliverySize `firestore:"size,omitempty"`
+
+	// Status expired means no courier accepted it in time; it can't be accepted any more.
+	// failed means every parcel was reported missing, at pickup or on the way, so the
+	// courier gave it up unpaid.
+	Status DeliveryStatus `firestore:"status"`
+
+	// Tip Tip added by the business after delivery, paid on top of payment
+	Tip      float64    `firestore:"tip"`
+	TippedAt *time.Time `firestore:"tippedAt"`
+
+	// TrackingToken Unguessable token of the public tracking page (GET /track/{token})
+	TrackingToken *string `firestore:"trackingToken,omitempty"`
+
+	// WeightKg Parcel weight in kg
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+
+	// ZoneId Service zone containing the destination, when zones are configured
+	ZoneId *string `firestore:"zoneId,omitempty"`
+}
+
+// DeliveryStatus expired means no courier accepted it in time; it can't be accepted any more.
+// failed means every parcel was reported missing, at pickup or on the way, so the
+// courier gave it up unpaid.
 type DeliveryStatus string
 
+// DeliveryAutoAction One step taken on a posted delivery nobody accepted: repriced (payment raised by
+// the business's repricing step), reminded (the business was told) or expired.
+type DeliveryAutoAction struct {
+	At   time.Time              `firestore:"at"`
+	Kind DeliveryAutoActionKind `firestore:"kind"`
+
+	// Payment Payment after the action
+	Payment float64 `firestore:"payment"`
+}
+
+// DeliveryAutoActionKind defines model for DeliveryAutoAction.Kind.
+type DeliveryAutoActionKind string
+
+// DeliveryBundle defines model for DeliveryBundle.
+type DeliveryBundle struct {
+	Deliveries      []Delivery `firestore:"deliveries"`
+	DistanceKm      float64    `firestore:"distanceKm"`
+	DurationMinutes float64    `firestore:"durationMinutes"`
+
+	// Payment Sum of the deliveries' payments
+	Payment float64 `firestore:"payment"`
+
+	// SavedMinutes Time saved over riding the deliveries one after the other
+	SavedMinutes float64 `firestore:"savedMinutes"`
+
+	// Stops Suggested order of the bundle's pickups and drop-offs
+	Stops []CourierRouteStop `firestore:"stops"`
+}
+
 // DeliveryCreate defines model for DeliveryCreate.
 type DeliveryCreate struct {
-	BusinessAddress     string   `firestore:"businessAddress"`
-	BusinessLocation    GeoPoint `firestore:"businessLocation"`
-	BusinessName        string   `firestore:"businessName"`
-	DestinationAddress  string   `firestore:"destinationAddress"`
-	DestinationLocation GeoPoint `firestore:"destinationLocation"`
-	Item                string   `firestore:"item"`
-	Payment             float64  `firestore:"payment"`
+	BusinessAddress  string   `firestore:"businessAddress"`
+	BusinessLocation GeoPoint `firestore:"businessLocation"`
+	BusinessName     string   `firestore:"businessName"`
+
+	// DeliverBefore Drop-off deadline; must be in the future and after pickupAfter
+	DeliverBefore       *time.Time `firestore:"deliverBefore,omitempty"`
+	DestinationAddress  string     `firestore:"destinationAddress"`
+	DestinationLocation GeoPoint   `firestore:"destinationLocation"`
+	Item                string     `firestore:"item"`
+
+	// Parcels Parcels to scan one by one at pickup and drop-off; labels must be unique.
+	// Their weights add up to weightKg when that is left out.
+	Parcels *[]ParcelCreate `firestore:"parcels,omitempty"`
+	Payment float64         `firestore:"payment"`
+
+	// PickupAfter Schedule the pickup; couriers see the delivery from a lead time before
+	PickupAfter    *time.Time `firestore:"pickupAfter,omitempty"`
+	RecipientEmail *string    `firestore:"recipientEmail,omitempty"`
+	RecipientName  *string    `firestore:"recipientName,omitempty"`
+
+	// RecipientPhone E.164, e.g. +4915112345678
+	RecipientPhone *string `firestore:"recipientPhone,omitempty"`
+
+	// Size Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+	// (60 L), xlarge needs a car (200 L). Omitted means small.
+	Size *DeliverySize `firestore:"size,omitempty"`
+
+	// WeightKg Parcel weight in kg
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+}
+
+// DeliveryEta defines model for DeliveryEta.
+type DeliveryEta struct {
+	DropoffAt time.Time `firestore:"dropoffAt"`
+
+	// PickupAt Null once the parcel is picked up
+	PickupAt  *time.Time `firestore:"pickupAt"`
+	UpdatedAt time.Time  `firestore:"updatedAt"`
 }
 
-// DeliveryPatch defines model for DeliveryPatch.
+// DeliveryPatch Couriers send status; failed gives up an accepted or picked up delivery whose parcels
+// were all reported missing. The owning business may instead change item, destination
+// and payment while the delivery is posted and no courier accepted it.
 type DeliveryPatch struct {
-	AssignedTo *string              `firestore:"assignedTo"`
-	Status     *DeliveryPatchStatus `firestore:"status,omitempty"`
+	AssignedTo          *string              `firestore:"assignedTo"`
+	DestinationAddress  *string              `firestore:"destinationAddress,omitempty"`
+	DestinationLocation *GeoPoint            `firestore:"destinationLocation,omitempty"`
+	Item                *string              `firestore:"item,omitempty"`
+	Payment             *float64             `firestore:"payment,omitempty"`
+	Status              *DeliveryPatchStatus `firestore:"status,omitempty"`
 }
 
 // DeliveryPatchStatus defines model for DeliveryPatch.Status.
 type DeliveryPatchStatus string
 
+// DeliveryQuote defines model for DeliveryQuote.
+type DeliveryQuote struct {
+	// DistanceKm Travel distance pickup → destination
+	DistanceKm      float64 `firestore:"distanceKm"`
+	DurationMinutes float64 `firestore:"durationMinutes"`
+
+	// Payment Suggested payment for the courier
+	Payment float64 `firestore:"payment"`
+
+	// Polyline Route geometry, encoded polyline (precision 5)
+	Polyline string `firestore:"polyline"`
+
+	// Provider Router that measured the route (haversine or osrm)
+	Provider string `firestore:"provider"`
+
+	// ZoneId Service zone whose pricing applied
+	ZoneId *string `firestore:"zoneId,omitempty"`
+}
+
+// DeliveryQuoteRequest defines model for DeliveryQuoteRequest.
+type DeliveryQuoteRequest struct {
+	BusinessLocation    GeoPoint `firestore:"businessLocation"`
+	DestinationLocation GeoPoint `firestore:"destinationLocation"`
+}
+
+// DeliverySize Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+// (60 L), xlarge needs a car (200 L). Omitted means small.
+type DeliverySize string
+
+// DeliveryTemplate A delivery the business sends on a schedule. Each occurrence is posted as a
+// scheduled delivery (pickupAfter = the occurrence) from the business's address.
+type DeliveryTemplate struct {
+	BusinessId string    `firestore:"businessId"`
+	CreatedAt  time.Time `firestore:"createdAt"`
+
+	// DeliverWithinMinutes Each delivery's deliverBefore, in minutes after its pickup time
+	DeliverWithinMinutes *int     `firestore:"deliverWithinMinutes,omitempty"`
+	DestinationAddress   string   `firestore:"destinationAddress"`
+	DestinationLocation  GeoPoint `firestore:"destinationLocation"`
+	Id                   string   `firestore:"id"`
+	Item                 string   `firestore:"item"`
+	LastDeliveryId       *string  `firestore:"lastDeliveryId"`
+
+	// LastError Why the last occurrence couldn't be posted, if it couldn't
+	LastError *string    `firestore:"lastError"`
+	LastRunAt *time.Time `firestore:"lastRunAt"`
+	Name      string     `firestore:"name"`
+
+	// NextRunAt Pickup time of the next delivery to be created
+	NextRunAt      *time.Time `firestore:"nextRunAt"`
+	Paused         bool       `firestore:"paused"`
+	Payment        float64    `firestore:"payment"`
+	RecipientEmail *string    `firestore:"recipientEmail,omitempty"`
+	RecipientName  *string    `firestore:"recipientName,omitempty"`
+
+	// RecipientPhone E.164, e.g. +4915112345678
+	RecipientPhone *string `firestore:"recipientPhone,omitempty"`
+
+	// Recurrence Cron expression "minute hour day-of-month month day-of-week" in the template's
+	// time zone, e.g. "30 7 * * 1-5" for weekdays at 07:30. Fields take *, lists,
+	// ranges and steps; day-of-week 0 and 7 are Sunday.
+	Recurrence string `firestore:"recurrence"`
+
+	// Size Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+	// (60 L), xlarge needs a car (200 L). Omitted means small.
+	Size *DeliverySize `firestore:"size,omitempty"`
+
+	// Timezone IANA time zone of the recurrence (default UTC)
+	Timezone  string    `firestore:"timezone"`
+	UpdatedAt time.Time `firestore:"updatedAt"`
+
+	// WeightKg Parcel weight in kg
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+}
+
+// DeliveryTemplateCreate defines model for DeliveryTemplateCreate.
+type DeliveryTemplateCreate struct {
+	// DeliverWithinMinutes Each delivery's deliverBefore, in minutes after its pickup time
+	DeliverWithinMinutes *int     `firestore:"deliverWithinMinutes,omitempty"`
+	DestinationAddress   string   `firestore:"destinationAddress"`
+	DestinationLocation  GeoPoint `firestore:"destinationLocation"`
+	Item                 string   `firestore:"item"`
+	Name                 string   `firestore:"name"`
+	Payment              float64  `firestore:"payment"`
+	RecipientEmail       *string  `firestore:"recipientEmail,omitempty"`
+	RecipientName        *string  `firestore:"recipientName,omitempty"`
+
+	// RecipientPhone E.164, e.g. +4915112345678
+	RecipientPhone *string `firestore:"recipientPhone,omitempty"`
+
+	// Recurrence Cron expression "minute hour day-of-month month day-of-week" in the template's
+	// time zone, e.g. "30 7 * * 1-5" for weekdays at 07:30. Fields take *, lists,
+	// ranges and steps; day-of-week 0 and 7 are Sunday.
+	Recurrence string `firestore:"recurrence"`
+
+	// Size Parcel size: small fits a bag (5 L), medium a backpack (20 L), large a box
+	// (60 L), xlarge needs a car (200 L). Omitted means small.
+	Size *DeliverySize `firestore:"size,omitempty"`
+
+	// Timezone IANA time zone of the recurrence (default UTC)
+	Timezone *string `firestore:"timezone,omitempty"`
+
+	// WeightKg Parcel weight in kg
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+}
+
+// DeliveryTemplateOccurrence defines model for DeliveryTemplateOccurrence.
+type DeliveryTemplateOccurrence struct {
+	DeliverBefore *time.Time `firestore:"deliverBefore,omitempty"`
+	PickupAfter   time.Time  `firestore:"pickupAfter"`
+}
+
+// EarningsPeriod defines model for EarningsPeriod.
+type EarningsPeriod struct {
+	Adjustments float64 `firestore:"adjustments"`
+
+	// Deliveries Number of delivered jobs
+	Deliveries       int     `firestore:"deliveries"`
+	DeliveryEarnings float64 `firestore:"deliveryEarnings"`
+
+	// End Exclusive
+	End time.Time `firestore:"end"`
+
+	// Net deliveryEarnings + tips + adjustments - payouts
+	Net float64 `firestore:"net"`
+
+	// Payouts Paid out, as a positive amount
+	Payouts float64   `firestore:"payouts"`
+	Start   time.Time `firestore:"start"`
+	Tips    float64   `firestore:"tips"`
+}
+
+// EarningsReport defines model for EarningsReport.
+type EarningsReport struct {
+	From    time.Time             `firestore:"from"`
+	GroupBy EarningsReportGroupBy `firestore:"groupBy"`
+	Periods []EarningsPeriod      `firestore:"periods"`
+
+	// To Exclusive
+	To     time.Time      `firestore:"to"`
+	Totals EarningsPeriod `firestore:"totals"`
+}
+
+// EarningsReportGroupBy defines model for EarningsReport.GroupBy.
+type EarningsReportGroupBy string
+
 // Error defines model for Error.
 type Error struct {
 	Message *string `firestore:"message,omitempty"`
@@ -128,11 +658,357 @@ type GeoPoint struct {
 	Lng float64 `firestore:"lng"`
 }
 
+// Invoice defines model for Invoice.
+type Invoice struct {
+	BusinessId   string     `firestore:"businessId"`
+	BusinessName string     `firestore:"businessName"`
+	CreatedAt    *time.Time `firestore:"createdAt,omitempty"`
+	Currency     string     `firestore:"currency"`
+
+	// FeeRate Platform fee as a fraction of payment
+	FeeRate float64       `firestore:"feeRate"`
+	Fees    float64       `firestore:"fees"`
+	Id      *string       `firestore:"id,omitempty"`
+	Lines   []InvoiceLine `firestore:"lines"`
+
+	// Number Sequential per year, e.g. INV-2026-000042
+	Number string `firestore:"number"`
+
+	// PeriodEnd Exclusive
+	PeriodEnd   time.Time `firestore:"periodEnd"`
+	PeriodStart time.Time `firestore:"periodStart"`
+
+	// Subtotal Sum of delivery payments
+	Subtotal float64 `firestore:"subtotal"`
+	Tax      float64 `firestore:"tax"`
+
+	// TaxRate Tax rate applied to payment + fee
+	TaxRate float64 `firestore:"taxRate"`
+
+	// Tips Tips, passed on to couriers untaxed
+	Tips  float64 `firestore:"tips"`
+	Total float64 `firestore:"total"`
+}
+
+// InvoiceLine defines model for InvoiceLine.
+type InvoiceLine struct {
+	BusinessAddress    string    `firestore:"businessAddress"`
+	DeliveredAt        time.Time `firestore:"deliveredAt"`
+	DeliveryId         string    `firestore:"deliveryId"`
+	DestinationAddress string    `firestore:"destinationAddress"`
+	Fee                float64   `firestore:"fee"`
+	Item               string    `firestore:"item"`
+	Payment            float64   `firestore:"payment"`
+	Tax                float64   `firestore:"tax"`
+	Tip                float64   `firestore:"tip"`
+	Total              float64   `firestore:"total"`
+}
+
+// NotificationEvent defines model for NotificationEvent.
+type NotificationEvent string
+
+// NotificationPreferences defines model for NotificationPreferences.
+type NotificationPreferences struct {
+	// Email Send notifications to the account email
+	Email       bool                `firestore:"email"`
+	MutedEvents []NotificationEvent `firestore:"mutedEvents"`
+
+	// Phone E.164 number, e.g. +4915112345678
+	Phone *string `firestore:"phone"`
+
+	// Sms Send notifications as SMS to phone
+	Sms bool `firestore:"sms"`
+}
+
 // OneOfUser defines model for OneOfUser.
 type OneOfUser struct {
 	union json.RawMessage
 }
 
+// Parcel defines model for Parcel.
+type Parcel struct {
+	Description string `firestore:"description"`
+
+	// Label Barcode or label id, unique within the delivery
+	Label string `firestore:"label"`
+
+	// Scans Every scan of the parcel, oldest first
+	Scans []ParcelScan `firestore:"scans"`
+
+	// Status Result of the parcel's latest scan; pending until the first one
+	Status   ParcelStatus `firestore:"status"`
+	WeightKg *float64     `firestore:"weightKg,omitempty"`
+}
+
+// ParcelStatus Result of the parcel's latest scan; pending until the first one
+type ParcelStatus string
+
+// ParcelCreate defines model for ParcelCreate.
+type ParcelCreate struct {
+	Description string `firestore:"description"`
+
+	// Label Barcode or label id
+	Label    string   `firestore:"label"`
+	WeightKg *float64 `firestore:"weightKg,omitempty"`
+}
+
+// ParcelScan defines model for ParcelScan.
+type ParcelScan struct {
+	At        time.Time       `firestore:"at"`
+	CourierId string          `firestore:"courierId"`
+	Event     ParcelScanEvent `firestore:"event"`
+	Note      *string         `firestore:"note,omitempty"`
+}
+
+// ParcelScanCreate defines model for ParcelScanCreate.
+type ParcelScanCreate struct {
+	Event ParcelScanEvent `firestore:"event"`
+
+	// Note E.g. what the business said about a missing parcel
+	Note *string `firestore:"note,omitempty"`
+}
+
+// ParcelScanEvent defines model for ParcelScanEvent.
+type ParcelScanEvent string
+
+// Payout defines model for Payout.
+type Payout struct {
+	AccountHolder string       `firestore:"accountHolder"`
+	Amount        float64      `firestore:"amount"`
+	BatchId       *string      `firestore:"batchId"`
+	CourierId     string       `firestore:"courierId"`
+	CourierName   string       `firestore:"courierName"`
+	CreatedAt     *time.Time   `firestore:"createdAt,omitempty"`
+	Iban          string       `firestore:"iban"`
+	Id            *string      `firestore:"id,omitempty"`
+	Reason        *string      `firestore:"reason"`
+	ReviewedAt    *time.Time   `firestore:"reviewedAt"`
+	ReviewedBy    *string      `firestore:"reviewedBy"`
+	Status        PayoutStatus `firestore:"status"`
+}
+
+// PayoutStatus defines model for Payout.Status.
+type PayoutStatus string
+
+// PayoutBatch defines model for PayoutBatch.
+type PayoutBatch struct {
+	Count     int        `firestore:"count"`
+	CreatedAt *time.Time `firestore:"createdAt,omitempty"`
+	CreatedBy string     `firestore:"createdBy"`
+	Id        *string    `firestore:"id,omitempty"`
+	PayoutIds []string   `firestore:"payoutIds"`
+	Total     float64    `firestore:"total"`
+}
+
+// PayoutCreate defines model for PayoutCreate.
+type PayoutCreate struct {
+	AccountHolder string  `firestore:"accountHolder"`
+	Amount        float64 `firestore:"amount"`
+	Iban          string  `firestore:"iban"`
+}
+
+// PayoutReject defines model for PayoutReject.
+type PayoutReject struct {
+	Reason *string `firestore:"reason,omitempty"`
+}
+
+// PushSubscription defines model for PushSubscription.
+type PushSubscription struct {
+	CreatedAt *time.Time           `firestore:"createdAt,omitempty"`
+	Endpoint  string               `firestore:"endpoint"`
+	Id        *string              `firestore:"id,omitempty"`
+	Keys      PushSubscriptionKeys `firestore:"keys"`
+
+	// RadiusKm Push about new deliveries picked up this close to the courier's last known position
+	RadiusKm float64 `firestore:"radiusKm"`
+}
+
+// PushSubscriptionCreate defines model for PushSubscriptionCreate.
+type PushSubscriptionCreate struct {
+	Endpoint string               `firestore:"endpoint"`
+	Keys     PushSubscriptionKeys `firestore:"keys"`
+
+	// RadiusKm Defaults to PUSH_DEFAULT_RADIUS_KM
+	RadiusKm *float64 `firestore:"radiusKm,omitempty"`
+}
+
+// PushSubscriptionKeys defines model for PushSubscriptionKeys.
+type PushSubscriptionKeys struct {
+	// Auth Browser auth secret, base64url
+	Auth string `firestore:"auth"`
+
+	// P256dh Browser P-256 public key, base64url
+	P256dh string `firestore:"p256dh"`
+}
+
+// Rating defines model for Rating.
+type Rating struct {
+	Comment   *string   `firestore:"comment"`
+	CreatedAt time.Time `firestore:"createdAt"`
+	RatedBy   string    `firestore:"ratedBy"`
+	Score     int       `firestore:"score"`
+}
+
+// RatingCreate defines model for RatingCreate.
+type RatingCreate struct {
+	Comment *string `firestore:"comment,omitempty"`
+	Score   int     `firestore:"score"`
+}
+
+// TipCreate defines model for TipCreate.
+type TipCreate struct {
+	Amount float64 `firestore:"amount"`
+}
+
+// Tracking What the public tracking page may show; no addresses or contacts
+type Tracking struct {
+	BusinessName string `firestore:"businessName"`
+
+	// CourierPosition Courier position rounded to about 500 m, only while accepted or picked up
+	CourierPosition   *GeoPoint  `firestore:"courierPosition"`
+	CourierPositionAt *time.Time `firestore:"courierPositionAt"`
+	CreatedAt         time.Time  `firestore:"createdAt"`
+	DeliveredAt       *time.Time `firestore:"deliveredAt"`
+
+	// EstimatedArrival Expected arrival while the delivery is on its way
+	EstimatedArrival *time.Time     `firestore:"estimatedArrival"`
+	Item             string         `firestore:"item"`
+	Status           TrackingStatus `firestore:"status"`
+}
+
+// TrackingStatus defines model for Tracking.Status.
+type TrackingStatus string
+
+// VapidPublicKey defines model for VapidPublicKey.
+type VapidPublicKey struct {
+	// PublicKey Uncompressed P-256 public key, base64url
+	PublicKey string `firestore:"publicKey"`
+}
+
+// VehicleType defines model for VehicleType.
+type VehicleType string
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	BusinessId string         `firestore:"businessId"`
+	CreatedAt  *time.Time     `firestore:"createdAt,omitempty"`
+	Events     []WebhookEvent `firestore:"events"`
+	Id         *string        `firestore:"id,omitempty"`
+
+	// Secret HMAC-SHA256 key, only returned by createWebhook; X-Webhook-Signature is
+	// t=<unix>,v1=<hex hmac of "<unix>.<body>">. The body is
+	// {"id","type","createdAt","delivery"}, delivery using the camelCase field names
+	// of the Delivery schema.
+	Secret *string `firestore:"secret,omitempty"`
+	Url    string  `firestore:"url"`
+}
+
+// WebhookCreate defines model for WebhookCreate.
+type WebhookCreate struct {
+	Events []WebhookEvent `firestore:"events"`
+
+	// Secret Generated when omitted
+	Secret *string `firestore:"secret,omitempty"`
+	Url    string  `firestore:"url"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempts       int        `firestore:"attempts"`
+	BusinessId     string     `firestore:"businessId"`
+	CreatedAt      *time.Time `firestore:"createdAt,omitempty"`
+	DeliveredAt    *time.Time `firestore:"deliveredAt"`
+	DeliveryId     string     `firestore:"deliveryId"`
+	Event          string     `firestore:"event"`
+	Id             *string    `firestore:"id,omitempty"`
+	LastError      *string    `firestore:"lastError"`
+	LastStatusCode *int       `firestore:"lastStatusCode"`
+	NextAttemptAt  *time.Time `firestore:"nextAttemptAt"`
+
+	// Payload Exact JSON body sent on every attempt
+	Payload   string                `firestore:"payload"`
+	Status    WebhookDeliveryStatus `firestore:"status"`
+	WebhookId string                `firestore:"webhookId"`
+}
+
+// WebhookDeliveryStatus defines model for WebhookDelivery.Status.
+type WebhookDeliveryStatus string
+
+// WebhookEvent defines model for WebhookEvent.
+type WebhookEvent string
+
+// Zone defines model for Zone.
+type Zone struct {
+	// Active Inactive zones give no coverage
+	Active    bool      `firestore:"active"`
+	CreatedAt time.Time `firestore:"createdAt"`
+
+	// Hours When deliveries may be posted; none means always
+	Hours []ZoneHours `firestore:"hours"`
+	Id    string      `firestore:"id"`
+
+	// MinPayment Lowest payment a delivery may offer
+	MinPayment *float64 `firestore:"minPayment,omitempty"`
+	Name       string   `firestore:"name"`
+
+	// Polygon GeoJSON Polygon (RFC 7946): an outer ring then optional holes, each a closed
+	// list of [longitude, latitude] positions.
+	Polygon ZonePolygon `firestore:"polygon"`
+
+	// Pricing Quote rates in the zone; any left out use the PRICING_* defaults
+	Pricing *ZonePricing `firestore:"pricing,omitempty"`
+
+	// Timezone IANA time zone of the operating hours (default UTC)
+	Timezone  string    `firestore:"timezone"`
+	UpdatedAt time.Time `firestore:"updatedAt"`
+}
+
+// ZoneCreate defines model for ZoneCreate.
+type ZoneCreate struct {
+	// Active Defaults to true
+	Active     *bool        `firestore:"active,omitempty"`
+	Hours      *[]ZoneHours `firestore:"hours,omitempty"`
+	MinPayment *float64     `firestore:"minPayment,omitempty"`
+	Name       string       `firestore:"name"`
+
+	// Polygon GeoJSON Polygon (RFC 7946): an outer ring then optional holes, each a closed
+	// list of [longitude, latitude] positions.
+	Polygon ZonePolygon `firestore:"polygon"`
+
+	// Pricing Quote rates in the zone; any left out use the PRICING_* defaults
+	Pricing  *ZonePricing `firestore:"pricing,omitempty"`
+	Timezone *string      `firestore:"timezone,omitempty"`
+}
+
+// ZoneHours Opening window on the given ISO weekdays (1 = Monday). A close at or before the
+// open runs past midnight into the next day.
+type ZoneHours struct {
+	// Close HH:MM, 24:00 for end of day
+	Close string `firestore:"close"`
+	Days  []int  `firestore:"days"`
+
+	// Open HH:MM
+	Open string `firestore:"open"`
+}
+
+// ZonePolygon GeoJSON Polygon (RFC 7946): an outer ring then optional holes, each a closed
+// list of [longitude, latitude] positions.
+type ZonePolygon struct {
+	Coordinates [][][]float64   `firestore:"coordinates"`
+	Type        ZonePolygonType `firestore:"type"`
+}
+
+// ZonePolygonType defines model for ZonePolygon.Type.
+type ZonePolygonType string
+
+// ZonePricing Quote rates in the zone; any left out use the PRICING_* defaults
+type ZonePricing struct {
+	Base      *float64 `firestore:"base,omitempty"`
+	Min       *float64 `firestore:"min,omitempty"`
+	PerKm     *float64 `firestore:"perKm,omitempty"`
+	PerMinute *float64 `firestore:"perMinute,omitempty"`
+}
+
 // PageSize defines model for PageSize.
 type PageSize = int
 
@@ -145,6 +1021,46 @@ type NotFound = Error
 // Unauthorized defines model for Unauthorized.
 type Unauthorized = Error
 
+// GetMyInvoiceParams defines parameters for GetMyInvoice.
+type GetMyInvoiceParams struct {
+	// Format pdf (default) or json
+	Format *GetMyInvoiceParamsFormat `form:"format,omitempty" firestore:"format,omitempty"`
+}
+
+// GetMyInvoiceParamsFormat defines parameters for GetMyInvoice.
+type GetMyInvoiceParamsFormat string
+
+// ListLowRatedCouriersParams defines parameters for ListLowRatedCouriers.
+type ListLowRatedCouriersParams struct {
+	// Below Average rating threshold (default 3.5)
+	Below *float64 `form:"below,omitempty" firestore:"below,omitempty"`
+
+	// MinRatings Only couriers with at least this many ratings (default 3)
+	MinRatings *int `form:"minRatings,omitempty" firestore:"minRatings,omitempty"`
+}
+
+// GetMyEarningsParams defines parameters for GetMyEarnings.
+type GetMyEarningsParams struct {
+	// From First day included (UTC); defaults to 30 days before `to`
+	From *openapi_types.Date `form:"from,omitempty" firestore:"from,omitempty"`
+
+	// To Last day included (UTC); defaults to today
+	To      *openapi_types.Date         `form:"to,omitempty" firestore:"to,omitempty"`
+	GroupBy *GetMyEarningsParamsGroupBy `form:"groupBy,omitempty" firestore:"groupBy,omitempty"`
+}
+
+// GetMyEarningsParamsGroupBy defines parameters for GetMyEarnings.
+type GetMyEarningsParamsGroupBy string
+
+// GetMyStatementParams defines parameters for GetMyStatement.
+type GetMyStatementParams struct {
+	// Format pdf (default) or csv
+	Format *GetMyStatementParamsFormat `form:"format,omitempty" firestore:"format,omitempty"`
+}
+
+// GetMyStatementParamsFormat defines parameters for GetMyStatement.
+type GetMyStatementParamsFormat string
+
 // ListDeliveriesParams defines parameters for ListDeliveries.
 type ListDeliveriesParams struct {
 	Status *ListDeliveriesParamsStatus `form:"status,omitempty" firestore:"status,omitempty"`
@@ -152,20 +1068,135 @@ type ListDeliveriesParams struct {
 	Lng    *float64                    `form:"lng,omitempty" firestore:"lng,omitempty"`
 
 	// R Radius in kilometres from (lat,lng)
-	R         *float64   `form:"r,omitempty" firestore:"r,omitempty"`
-	PageSize  *PageSize  `form:"pageSize,omitempty" firestore:"pageSize,omitempty"`
-	PageToken *PageToken `form:"pageToken,omitempty" firestore:"pageToken,omitempty"`
+	R *float64 `form:"r,omitempty" firestore:"r,omitempty"`
+
+	// Sort createdAt (default) lists newest first; distance lists the pickups nearest to
+	// (lat,lng) by travel time first, within each page. distance needs lat and lng.
+	// urgency lists the earliest deliverBefore first, within each page; it is the
+	// default for couriers.
+	Sort      *ListDeliveriesParamsSort `form:"sort,omitempty" firestore:"sort,omitempty"`
+	PageSize  *PageSize                 `form:"pageSize,omitempty" firestore:"pageSize,omitempty"`
+	PageToken *PageToken                `form:"pageToken,omitempty" firestore:"pageToken,omitempty"`
 }
 
 // ListDeliveriesParamsStatus defines parameters for ListDeliveries.
 type ListDeliveriesParamsStatus string
 
+// ListDeliveriesParamsSort defines parameters for ListDeliveries.
+type ListDeliveriesParamsSort string
+
+// ListDeliveryBundlesParams defines parameters for ListDeliveryBundles.
+type ListDeliveryBundlesParams struct {
+	// Lat Courier position; bundles are planned from here when given with lng
+	Lat *float64 `form:"lat,omitempty" firestore:"lat,omitempty"`
+	Lng *float64 `form:"lng,omitempty" firestore:"lng,omitempty"`
+
+	// R Only pickups within this many kilometres of (lat,lng)
+	R *float64 `form:"r,omitempty" firestore:"r,omitempty"`
+}
+
+// StreamDeliveriesParams defines parameters for StreamDeliveries.
+type StreamDeliveriesParams struct {
+	Lat *float64 `form:"lat,omitempty" firestore:"lat,omitempty"`
+	Lng *float64 `form:"lng,omitempty" firestore:"lng,omitempty"`
+
+	// R Radius in km around lat/lng (couriers)
+	R *float64 `form:"r,omitempty" firestore:"r,omitempty"`
+
+	// LastEventID Id of the last event received; events after it are replayed first, going back
+	// an hour at most
+	LastEventID *string `firestore:"Last-Event-ID,omitempty"`
+}
+
+// ListDeliveryTemplateOccurrencesParams defines parameters for ListDeliveryTemplateOccurrences.
+type ListDeliveryTemplateOccurrencesParams struct {
+	// Count How many occurrences to list (default 10, at most 100)
+	Count *int `form:"count,omitempty" firestore:"count,omitempty"`
+}
+
+// GenerateInvoicesParams defines parameters for GenerateInvoices.
+type GenerateInvoicesParams struct {
+	// Month Invoiced month as YYYY-MM; defaults to the previous month
+	Month *string `form:"month,omitempty" firestore:"month,omitempty"`
+}
+
+// ListPayoutsParams defines parameters for ListPayouts.
+type ListPayoutsParams struct {
+	Status *ListPayoutsParamsStatus `form:"status,omitempty" firestore:"status,omitempty"`
+}
+
+// ListPayoutsParamsStatus defines parameters for ListPayouts.
+type ListPayoutsParamsStatus string
+
+// ExportPayoutBatchParams defines parameters for ExportPayoutBatch.
+type ExportPayoutBatchParams struct {
+	// Format csv (default) or sepa (pain.001 credit transfer XML)
+	Format *ExportPayoutBatchParamsFormat `form:"format,omitempty" firestore:"format,omitempty"`
+}
+
+// ExportPayoutBatchParamsFormat defines parameters for ExportPayoutBatch.
+type ExportPayoutBatchParamsFormat string
+
+// UpdateMyRepricingJSONRequestBody defines body for UpdateMyRepricing for application/json ContentType.
+type UpdateMyRepricingJSONRequestBody = BusinessRepricing
+
+// RequestPayoutJSONRequestBody defines body for RequestPayout for application/json ContentType.
+type RequestPayoutJSONRequestBody = PayoutCreate
+
+// CreatePushSubscriptionJSONRequestBody defines body for CreatePushSubscription for application/json ContentType.
+type CreatePushSubscriptionJSONRequestBody = PushSubscriptionCreate
+
+// UpdateMyVehicleJSONRequestBody defines body for UpdateMyVehicle for application/json ContentType.
+type UpdateMyVehicleJSONRequestBody = CourierVehicle
+
+// SetCourierActiveLimitJSONRequestBody defines body for SetCourierActiveLimit for application/json ContentType.
+type SetCourierActiveLimitJSONRequestBody = CourierActiveLimit
+
+// SetCourierZonesJSONRequestBody defines body for SetCourierZones for application/json ContentType.
+type SetCourierZonesJSONRequestBody = CourierZones
+
 // CreateDeliveryJSONRequestBody defines body for CreateDelivery for application/json ContentType.
 type CreateDeliveryJSONRequestBody = DeliveryCreate
 
+// AcceptDeliveryBundleJSONRequestBody defines body for AcceptDeliveryBundle for application/json ContentType.
+type AcceptDeliveryBundleJSONRequestBody = BundleAccept
+
+// QuoteDeliveryJSONRequestBody defines body for QuoteDelivery for application/json ContentType.
+type QuoteDeliveryJSONRequestBody = DeliveryQuoteRequest
+
 // UpdateDeliveryJSONRequestBody defines body for UpdateDelivery for application/json ContentType.
 type UpdateDeliveryJSONRequestBody = DeliveryPatch
 
+// ScanParcelJSONRequestBody defines body for ScanParcel for application/json ContentType.
+type ScanParcelJSONRequestBody = ParcelScanCreate
+
+// RateDeliveryJSONRequestBody defines body for RateDelivery for application/json ContentType.
+type RateDeliveryJSONRequestBody = RatingCreate
+
+// TipDeliveryJSONRequestBody defines body for TipDelivery for application/json ContentType.
+type TipDeliveryJSONRequestBody = TipCreate
+
+// CreateDeliveryTemplateJSONRequestBody defines body for CreateDeliveryTemplate for application/json ContentType.
+type CreateDeliveryTemplateJSONRequestBody = DeliveryTemplateCreate
+
+// UpdateDeliveryTemplateJSONRequestBody defines body for UpdateDeliveryTemplate for application/json ContentType.
+type UpdateDeliveryTemplateJSONRequestBody = DeliveryTemplateCreate
+
+// UpdateMyNotificationPreferencesJSONRequestBody defines body for UpdateMyNotificationPreferences for application/json ContentType.
+type UpdateMyNotificationPreferencesJSONRequestBody = NotificationPreferences
+
+// RejectPayoutJSONRequestBody defines body for RejectPayout for application/json ContentType.
+type RejectPayoutJSONRequestBody = PayoutReject
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = WebhookCreate
+
+// CreateZoneJSONRequestBody defines body for CreateZone for application/json ContentType.
+type CreateZoneJSONRequestBody = ZoneCreate
+
+// UpdateZoneJSONRequestBody defines body for UpdateZone for application/json ContentType.
+type UpdateZoneJSONRequestBody = ZoneCreate
+
 // AsBusinessUser returns the union data inside the OneOfUser as a BusinessUser
 func (t OneOfUser) AsBusinessUser() (BusinessUser, error) {
 	var body BusinessUser
@@ -233,24 +1264,183 @@ type ServerInterface interface {
 	// List all businesses
 	// (GET /businesses)
 	ListBusinesses(c *gin.Context)
+	// List the calling business's invoices, newest first
+	// (GET /businesses/me/invoices)
+	ListMyInvoices(c *gin.Context)
+	// Download one of the calling business's invoices
+	// (GET /businesses/me/invoices/{id})
+	GetMyInvoice(c *gin.Context, id string, params GetMyInvoiceParams)
+	// The calling business's consent to automatic repricing
+	// (GET /businesses/me/repricing)
+	GetMyRepricing(c *gin.Context)
+	// Allow or stop raising the payment of deliveries no courier accepts
+	// (PUT /businesses/me/repricing)
+	UpdateMyRepricing(c *gin.Context)
 	// List all couriers
 	// (GET /couriers)
 	ListCouriers(c *gin.Context)
+	// WebSocket of live courier positions (admin sees all, business its active couriers)
+	// (GET /couriers/locations/ws)
+	StreamCourierLocations(c *gin.Context)
+	// Couriers whose average rating is below a threshold, lowest first (admin)
+	// (GET /couriers/low-rated)
+	ListLowRatedCouriers(c *gin.Context, params ListLowRatedCouriersParams)
+	// Earnings of the calling courier aggregated by day, week or month
+	// (GET /couriers/me/earnings)
+	GetMyEarnings(c *gin.Context, params GetMyEarningsParams)
+	// List the calling courier's payout requests
+	// (GET /couriers/me/payouts)
+	ListMyPayouts(c *gin.Context)
+	// Courier requests a withdrawal against available balance
+	// (POST /couriers/me/payouts)
+	RequestPayout(c *gin.Context)
+	// The calling courier's web push subscriptions
+	// (GET /couriers/me/push-subscriptions)
+	ListMyPushSubscriptions(c *gin.Context)
+	// Subscribe a browser to pushes about new deliveries near the courier
+	// (POST /couriers/me/push-subscriptions)
+	CreatePushSubscription(c *gin.Context)
+	// Unsubscribe a browser
+	// (DELETE /couriers/me/push-subscriptions/{id})
+	DeletePushSubscription(c *gin.Context, id string)
+	// Suggested order of the calling courier's pickups and drop-offs
+	// (GET /couriers/me/route)
+	GetMyRoute(c *gin.Context)
+	// Download the calling courier's monthly earnings statement
+	// (GET /couriers/me/statements/{month})
+	GetMyStatement(c *gin.Context, month string, params GetMyStatementParams)
+	// The calling courier's vehicle and capacities
+	// (GET /couriers/me/vehicle)
+	GetMyVehicle(c *gin.Context)
+	// Set the calling courier's vehicle and capacities
+	// (PUT /couriers/me/vehicle)
+	UpdateMyVehicle(c *gin.Context)
+	// Set or clear a courier's limit of active deliveries (admin)
+	// (PUT /couriers/{id}/active-limit)
+	SetCourierActiveLimit(c *gin.Context, id string)
+	// Assign a courier to service zones (admin)
+	// (PUT /couriers/{id}/zones)
+	SetCourierZones(c *gin.Context, id string)
 	// List deliveries (optional geo-filter)
 	// (GET /deliveries)
 	ListDeliveries(c *gin.Context, params ListDeliveriesParams)
 	// Create a new delivery (business role)
 	// (POST /deliveries)
 	CreateDelivery(c *gin.Context)
-	// Update delivery status / assignment (courier)
+	// Suggested bundles of posted deliveries a courier can ride together (courier role)
+	// (GET /deliveries/bundles)
+	ListDeliveryBundles(c *gin.Context, params ListDeliveryBundlesParams)
+	// Accept several posted deliveries at once, all or none (courier role)
+	// (POST /deliveries/bundles/accept)
+	AcceptDeliveryBundle(c *gin.Context)
+	// Quote a delivery's route and suggested payment before posting it (business role)
+	// (POST /deliveries/quote)
+	QuoteDelivery(c *gin.Context)
+	// Server-Sent Events stream of delivery changes visible to the caller
+	// (GET /deliveries/stream)
+	StreamDeliveries(c *gin.Context, params StreamDeliveriesParams)
+	// Update delivery status (courier) or edit a posted delivery (owning business)
 	// (PATCH /deliveries/{id})
 	UpdateDelivery(c *gin.Context, id string)
 	// Courier attempts to claim a delivery
 	// (POST /deliveries/{id}/accept)
 	AcceptDelivery(c *gin.Context, id string)
+	// Assigned courier scans a parcel at pickup or drop-off, or reports it missing
+	// (POST /deliveries/{id}/parcels/{label}/scans)
+	ScanParcel(c *gin.Context, id string, label string)
+	// Rate the other party of a delivered delivery (business rates courier, courier rates business)
+	// (POST /deliveries/{id}/rating)
+	RateDelivery(c *gin.Context, id string)
+	// Business tips the courier of one of its delivered deliveries
+	// (POST /deliveries/{id}/tip)
+	TipDelivery(c *gin.Context, id string)
+	// List the calling business's recurring delivery templates
+	// (GET /delivery-templates)
+	ListDeliveryTemplates(c *gin.Context)
+	// Create a recurring delivery template
+	// (POST /delivery-templates)
+	CreateDeliveryTemplate(c *gin.Context)
+	// Remove a recurring delivery template
+	// (DELETE /delivery-templates/{id})
+	DeleteDeliveryTemplate(c *gin.Context, id string)
+	// One recurring delivery template
+	// (GET /delivery-templates/{id})
+	GetDeliveryTemplate(c *gin.Context, id string)
+	// Edit a recurring delivery template
+	// (PUT /delivery-templates/{id})
+	UpdateDeliveryTemplate(c *gin.Context, id string)
+	// Upcoming deliveries of a template
+	// (GET /delivery-templates/{id}/occurrences)
+	ListDeliveryTemplateOccurrences(c *gin.Context, id string, params ListDeliveryTemplateOccurrencesParams)
+	// Stop creating deliveries from a template
+	// (POST /delivery-templates/{id}/pause)
+	PauseDeliveryTemplate(c *gin.Context, id string)
+	// Create deliveries from a paused template again, from its next occurrence on
+	// (POST /delivery-templates/{id}/resume)
+	ResumeDeliveryTemplate(c *gin.Context, id string)
+	// Generate the monthly invoices of every business (admin)
+	// (POST /invoices/generate)
+	GenerateInvoices(c *gin.Context, params GenerateInvoicesParams)
 	// Dummy route to generate user schemas
 	// (GET /me)
 	GetMe(c *gin.Context)
+	// The caller's notification channels and muted events
+	// (GET /me/notification-preferences)
+	GetMyNotificationPreferences(c *gin.Context)
+	// Replace the caller's notification preferences
+	// (PUT /me/notification-preferences)
+	UpdateMyNotificationPreferences(c *gin.Context)
+	// List payout requests (admin)
+	// (GET /payouts)
+	ListPayouts(c *gin.Context, params ListPayoutsParams)
+	// Collect all approved payouts into a batch and mark them paid (admin)
+	// (POST /payouts/batches)
+	CreatePayoutBatch(c *gin.Context)
+	// Download a payout batch for the finance team (admin)
+	// (GET /payouts/batches/{id}/export)
+	ExportPayoutBatch(c *gin.Context, id string, params ExportPayoutBatchParams)
+	// Approve a pending payout (admin)
+	// (POST /payouts/{id}/approve)
+	ApprovePayout(c *gin.Context, id string)
+	// Reject a pending or approved payout and release the reservation (admin)
+	// (POST /payouts/{id}/reject)
+	RejectPayout(c *gin.Context, id string)
+	// VAPID application server key for PushManager.subscribe
+	// (GET /push/vapid-public-key)
+	GetVapidPublicKey(c *gin.Context)
+	// Public tracking of one delivery, for the recipient; no login needed
+	// (GET /track/{token})
+	TrackDelivery(c *gin.Context, token string)
+	// List the calling business's webhook registrations
+	// (GET /webhooks)
+	ListWebhooks(c *gin.Context)
+	// Register a webhook for delivery lifecycle events
+	// (POST /webhooks)
+	CreateWebhook(c *gin.Context)
+	// Webhook calls that failed every retry, newest first
+	// (GET /webhooks/dead-letters)
+	ListWebhookDeadLetters(c *gin.Context)
+	// Remove a webhook registration
+	// (DELETE /webhooks/{id})
+	DeleteWebhook(c *gin.Context, id string)
+	// Send a signed webhook.test event to the webhook once and report the outcome
+	// (POST /webhooks/{id}/test)
+	TestWebhook(c *gin.Context, id string)
+	// List service zones
+	// (GET /zones)
+	ListZones(c *gin.Context)
+	// Create a service zone (admin)
+	// (POST /zones)
+	CreateZone(c *gin.Context)
+	// Remove a service zone (admin)
+	// (DELETE /zones/{id})
+	DeleteZone(c *gin.Context, id string)
+	// One service zone
+	// (GET /zones/{id})
+	GetZone(c *gin.Context, id string)
+	// Replace a service zone (admin)
+	// (PUT /zones/{id})
+	UpdateZone(c *gin.Context, id string)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -277,8 +1467,8 @@ func (siw *ServerInterfaceWrapper) ListBusinesses(c *gin.Context) {
 	siw.Handler.ListBusinesses(c)
 }
 
-// ListCouriers operation middleware
-func (siw *ServerInterfaceWrapper) ListCouriers(c *gin.Context) {
+// ListMyInvoices operation middleware
+func (siw *ServerInterfaceWrapper) ListMyInvoices(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{})
 
@@ -289,7 +1479,404 @@ func (siw *ServerInterfaceWrapper) ListCouriers(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.ListCouriers(c)
+	siw.Handler.ListMyInvoices(c)
+}
+
+// GetMyInvoice operation middleware
+func (siw *ServerInterfaceWrapper) GetMyInvoice(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMyInvoiceParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", c.Request.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter format: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMyInvoice(c, id, params)
+}
+
+// GetMyRepricing operation middleware
+func (siw *ServerInterfaceWrapper) GetMyRepricing(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMyRepricing(c)
+}
+
+// UpdateMyRepricing operation middleware
+func (siw *ServerInterfaceWrapper) UpdateMyRepricing(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateMyRepricing(c)
+}
+
+// ListCouriers operation middleware
+func (siw *ServerInterfaceWrapper) ListCouriers(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListCouriers(c)
+}
+
+// StreamCourierLocations operation middleware
+func (siw *ServerInterfaceWrapper) StreamCourierLocations(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StreamCourierLocations(c)
+}
+
+// ListLowRatedCouriers operation middleware
+func (siw *ServerInterfaceWrapper) ListLowRatedCouriers(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListLowRatedCouriersParams
+
+	// ------------- Optional query parameter "below" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "below", c.Request.URL.Query(), &params.Below)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter below: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "minRatings" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minRatings", c.Request.URL.Query(), &params.MinRatings)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter minRatings: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListLowRatedCouriers(c, params)
+}
+
+// GetMyEarnings operation middleware
+func (siw *ServerInterfaceWrapper) GetMyEarnings(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMyEarningsParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", c.Request.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter from: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "groupBy" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "groupBy", c.Request.URL.Query(), &params.GroupBy)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter groupBy: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMyEarnings(c, params)
+}
+
+// ListMyPayouts operation middleware
+func (siw *ServerInterfaceWrapper) ListMyPayouts(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListMyPayouts(c)
+}
+
+// RequestPayout operation middleware
+func (siw *ServerInterfaceWrapper) RequestPayout(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RequestPayout(c)
+}
+
+// ListMyPushSubscriptions operation middleware
+func (siw *ServerInterfaceWrapper) ListMyPushSubscriptions(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListMyPushSubscriptions(c)
+}
+
+// CreatePushSubscription operation middleware
+func (siw *ServerInterfaceWrapper) CreatePushSubscription(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreatePushSubscription(c)
+}
+
+// DeletePushSubscription operation middleware
+func (siw *ServerInterfaceWrapper) DeletePushSubscription(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeletePushSubscription(c, id)
+}
+
+// GetMyRoute operation middleware
+func (siw *ServerInterfaceWrapper) GetMyRoute(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMyRoute(c)
+}
+
+// GetMyStatement operation middleware
+func (siw *ServerInterfaceWrapper) GetMyStatement(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "month" -------------
+	var month string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "month", c.Param("month"), &month, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter month: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMyStatementParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", c.Request.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter format: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMyStatement(c, month, params)
+}
+
+// GetMyVehicle operation middleware
+func (siw *ServerInterfaceWrapper) GetMyVehicle(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMyVehicle(c)
+}
+
+// UpdateMyVehicle operation middleware
+func (siw *ServerInterfaceWrapper) UpdateMyVehicle(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateMyVehicle(c)
+}
+
+// SetCourierActiveLimit operation middleware
+func (siw *ServerInterfaceWrapper) SetCourierActiveLimit(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetCourierActiveLimit(c, id)
+}
+
+// SetCourierZones operation middleware
+func (siw *ServerInterfaceWrapper) SetCourierZones(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetCourierZones(c, id)
 }
 
 // ListDeliveries operation middleware
@@ -300,7 +1887,646 @@ func (siw *ServerInterfaceWrapper) ListDeliveries(c *gin.Context) {
 	c.Set(BearerAuthScopes, []string{})
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params ListDeliveriesParams
+	var params ListDeliveriesParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", c.Request.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter status: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "lat" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lat", c.Request.URL.Query(), &params.Lat)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lat: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "lng" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lng", c.Request.URL.Query(), &params.Lng)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lng: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "r" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "r", c.Request.URL.Query(), &params.R)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter r: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", c.Request.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter sort: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", c.Request.URL.Query(), &params.PageSize)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter pageSize: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "pageToken" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageToken", c.Request.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter pageToken: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListDeliveries(c, params)
+}
+
+// CreateDelivery operation middleware
+func (siw *ServerInterfaceWrapper) CreateDelivery(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateDelivery(c)
+}
+
+// ListDeliveryBundles operation middleware
+func (siw *ServerInterfaceWrapper) ListDeliveryBundles(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListDeliveryBundlesParams
+
+	// ------------- Optional query parameter "lat" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lat", c.Request.URL.Query(), &params.Lat)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lat: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "lng" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lng", c.Request.URL.Query(), &params.Lng)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lng: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "r" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "r", c.Request.URL.Query(), &params.R)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter r: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListDeliveryBundles(c, params)
+}
+
+// AcceptDeliveryBundle operation middleware
+func (siw *ServerInterfaceWrapper) AcceptDeliveryBundle(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.AcceptDeliveryBundle(c)
+}
+
+// QuoteDelivery operation middleware
+func (siw *ServerInterfaceWrapper) QuoteDelivery(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.QuoteDelivery(c)
+}
+
+// StreamDeliveries operation middleware
+func (siw *ServerInterfaceWrapper) StreamDeliveries(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamDeliveriesParams
+
+	// ------------- Optional query parameter "lat" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lat", c.Request.URL.Query(), &params.Lat)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lat: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "lng" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lng", c.Request.URL.Query(), &params.Lng)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lng: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "r" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "r", c.Request.URL.Query(), &params.R)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter r: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for Last-Event-ID, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Last-Event-ID", valueList[0], &LastEventID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter Last-Event-ID: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StreamDeliveries(c, params)
+}
+
+// UpdateDelivery operation middleware
+func (siw *ServerInterfaceWrapper) UpdateDelivery(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateDelivery(c, id)
+}
+
+// AcceptDelivery operation middleware
+func (siw *ServerInterfaceWrapper) AcceptDelivery(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.AcceptDelivery(c, id)
+}
+
+// ScanParcel operation middleware
+func (siw *ServerInterfaceWrapper) ScanParcel(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "label" -------------
+	var label string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "label", c.Param("label"), &label, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter label: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ScanParcel(c, id, label)
+}
+
+// RateDelivery operation middleware
+func (siw *ServerInterfaceWrapper) RateDelivery(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RateDelivery(c, id)
+}
+
+// TipDelivery operation middleware
+func (siw *ServerInterfaceWrapper) TipDelivery(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.TipDelivery(c, id)
+}
+
+// ListDeliveryTemplates operation middleware
+func (siw *ServerInterfaceWrapper) ListDeliveryTemplates(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListDeliveryTemplates(c)
+}
+
+// CreateDeliveryTemplate operation middleware
+func (siw *ServerInterfaceWrapper) CreateDeliveryTemplate(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateDeliveryTemplate(c)
+}
+
+// DeleteDeliveryTemplate operation middleware
+func (siw *ServerInterfaceWrapper) DeleteDeliveryTemplate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteDeliveryTemplate(c, id)
+}
+
+// GetDeliveryTemplate operation middleware
+func (siw *ServerInterfaceWrapper) GetDeliveryTemplate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetDeliveryTemplate(c, id)
+}
+
+// UpdateDeliveryTemplate operation middleware
+func (siw *ServerInterfaceWrapper) UpdateDeliveryTemplate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateDeliveryTemplate(c, id)
+}
+
+// ListDeliveryTemplateOccurrences operation middleware
+func (siw *ServerInterfaceWrapper) ListDeliveryTemplateOccurrences(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListDeliveryTemplateOccurrencesParams
+
+	// ------------- Optional query parameter "count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "count", c.Request.URL.Query(), &params.Count)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter count: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListDeliveryTemplateOccurrences(c, id, params)
+}
+
+// PauseDeliveryTemplate operation middleware
+func (siw *ServerInterfaceWrapper) PauseDeliveryTemplate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PauseDeliveryTemplate(c, id)
+}
+
+// ResumeDeliveryTemplate operation middleware
+func (siw *ServerInterfaceWrapper) ResumeDeliveryTemplate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ResumeDeliveryTemplate(c, id)
+}
+
+// GenerateInvoices operation middleware
+func (siw *ServerInterfaceWrapper) GenerateInvoices(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GenerateInvoicesParams
+
+	// ------------- Optional query parameter "month" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "month", c.Request.URL.Query(), &params.Month)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter month: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GenerateInvoices(c, params)
+}
+
+// GetMe operation middleware
+func (siw *ServerInterfaceWrapper) GetMe(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMe(c)
+}
+
+// GetMyNotificationPreferences operation middleware
+func (siw *ServerInterfaceWrapper) GetMyNotificationPreferences(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMyNotificationPreferences(c)
+}
+
+// UpdateMyNotificationPreferences operation middleware
+func (siw *ServerInterfaceWrapper) UpdateMyNotificationPreferences(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateMyNotificationPreferences(c)
+}
+
+// ListPayouts operation middleware
+func (siw *ServerInterfaceWrapper) ListPayouts(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListPayoutsParams
 
 	// ------------- Optional query parameter "status" -------------
 
@@ -310,43 +2536,146 @@ func (siw *ServerInterfaceWrapper) ListDeliveries(c *gin.Context) {
 		return
 	}
 
-	// ------------- Optional query parameter "lat" -------------
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
 
-	err = runtime.BindQueryParameter("form", true, false, "lat", c.Request.URL.Query(), &params.Lat)
+	siw.Handler.ListPayouts(c, params)
+}
+
+// CreatePayoutBatch operation middleware
+func (siw *ServerInterfaceWrapper) CreatePayoutBatch(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreatePayoutBatch(c)
+}
+
+// ExportPayoutBatch operation middleware
+func (siw *ServerInterfaceWrapper) ExportPayoutBatch(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lat: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "lng" -------------
+	c.Set(BearerAuthScopes, []string{})
 
-	err = runtime.BindQueryParameter("form", true, false, "lng", c.Request.URL.Query(), &params.Lng)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportPayoutBatchParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", c.Request.URL.Query(), &params.Format)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter lng: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter format: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "r" -------------
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
 
-	err = runtime.BindQueryParameter("form", true, false, "r", c.Request.URL.Query(), &params.R)
+	siw.Handler.ExportPayoutBatch(c, id, params)
+}
+
+// ApprovePayout operation middleware
+func (siw *ServerInterfaceWrapper) ApprovePayout(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter r: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "pageSize" -------------
+	c.Set(BearerAuthScopes, []string{})
 
-	err = runtime.BindQueryParameter("form", true, false, "pageSize", c.Request.URL.Query(), &params.PageSize)
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ApprovePayout(c, id)
+}
+
+// RejectPayout operation middleware
+func (siw *ServerInterfaceWrapper) RejectPayout(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter pageSize: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "pageToken" -------------
+	c.Set(BearerAuthScopes, []string{})
 
-	err = runtime.BindQueryParameter("form", true, false, "pageToken", c.Request.URL.Query(), &params.PageToken)
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RejectPayout(c, id)
+}
+
+// GetVapidPublicKey operation middleware
+func (siw *ServerInterfaceWrapper) GetVapidPublicKey(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetVapidPublicKey(c)
+}
+
+// TrackDelivery operation middleware
+func (siw *ServerInterfaceWrapper) TrackDelivery(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", c.Param("token"), &token, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter pageToken: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter token: %w", err), http.StatusBadRequest)
 		return
 	}
 
@@ -357,11 +2686,11 @@ func (siw *ServerInterfaceWrapper) ListDeliveries(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.ListDeliveries(c, params)
+	siw.Handler.TrackDelivery(c, token)
 }
 
-// CreateDelivery operation middleware
-func (siw *ServerInterfaceWrapper) CreateDelivery(c *gin.Context) {
+// ListWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) ListWebhooks(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{})
 
@@ -372,11 +2701,41 @@ func (siw *ServerInterfaceWrapper) CreateDelivery(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.CreateDelivery(c)
+	siw.Handler.ListWebhooks(c)
 }
 
-// UpdateDelivery operation middleware
-func (siw *ServerInterfaceWrapper) UpdateDelivery(c *gin.Context) {
+// CreateWebhook operation middleware
+func (siw *ServerInterfaceWrapper) CreateWebhook(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateWebhook(c)
+}
+
+// ListWebhookDeadLetters operation middleware
+func (siw *ServerInterfaceWrapper) ListWebhookDeadLetters(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListWebhookDeadLetters(c)
+}
+
+// DeleteWebhook operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWebhook(c *gin.Context) {
 
 	var err error
 
@@ -398,11 +2757,11 @@ func (siw *ServerInterfaceWrapper) UpdateDelivery(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.UpdateDelivery(c, id)
+	siw.Handler.DeleteWebhook(c, id)
 }
 
-// AcceptDelivery operation middleware
-func (siw *ServerInterfaceWrapper) AcceptDelivery(c *gin.Context) {
+// TestWebhook operation middleware
+func (siw *ServerInterfaceWrapper) TestWebhook(c *gin.Context) {
 
 	var err error
 
@@ -424,11 +2783,11 @@ func (siw *ServerInterfaceWrapper) AcceptDelivery(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.AcceptDelivery(c, id)
+	siw.Handler.TestWebhook(c, id)
 }
 
-// GetMe operation middleware
-func (siw *ServerInterfaceWrapper) GetMe(c *gin.Context) {
+// ListZones operation middleware
+func (siw *ServerInterfaceWrapper) ListZones(c *gin.Context) {
 
 	c.Set(BearerAuthScopes, []string{})
 
@@ -439,7 +2798,100 @@ func (siw *ServerInterfaceWrapper) GetMe(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetMe(c)
+	siw.Handler.ListZones(c)
+}
+
+// CreateZone operation middleware
+func (siw *ServerInterfaceWrapper) CreateZone(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateZone(c)
+}
+
+// DeleteZone operation middleware
+func (siw *ServerInterfaceWrapper) DeleteZone(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteZone(c, id)
+}
+
+// GetZone operation middleware
+func (siw *ServerInterfaceWrapper) GetZone(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetZone(c, id)
+}
+
+// UpdateZone operation middleware
+func (siw *ServerInterfaceWrapper) UpdateZone(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateZone(c, id)
 }
 
 // GinServerOptions provides options for the Gin server.
@@ -470,10 +2922,63 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 	}
 
 	router.GET(options.BaseURL+"/businesses", wrapper.ListBusinesses)
+	router.GET(options.BaseURL+"/businesses/me/invoices", wrapper.ListMyInvoices)
+	router.GET(options.BaseURL+"/businesses/me/invoices/:id", wrapper.GetMyInvoice)
+	router.GET(options.BaseURL+"/businesses/me/repricing", wrapper.GetMyRepricing)
+	router.PUT(options.BaseURL+"/businesses/me/repricing", wrapper.UpdateMyRepricing)
 	router.GET(options.BaseURL+"/couriers", wrapper.ListCouriers)
+	router.GET(options.BaseURL+"/couriers/locations/ws", wrapper.StreamCourierLocations)
+	router.GET(options.BaseURL+"/couriers/low-rated", wrapper.ListLowRatedCouriers)
+	router.GET(options.BaseURL+"/couriers/me/earnings", wrapper.GetMyEarnings)
+	router.GET(options.BaseURL+"/couriers/me/payouts", wrapper.ListMyPayouts)
+	router.POST(options.BaseURL+"/couriers/me/payouts", wrapper.RequestPayout)
+	router.GET(options.BaseURL+"/couriers/me/push-subscriptions", wrapper.ListMyPushSubscriptions)
+	router.POST(options.BaseURL+"/couriers/me/push-subscriptions", wrapper.CreatePushSubscription)
+	router.DELETE(options.BaseURL+"/couriers/me/push-subscriptions/:id", wrapper.DeletePushSubscription)
+	router.GET(options.BaseURL+"/couriers/me/route", wrapper.GetMyRoute)
+	router.GET(options.BaseURL+"/couriers/me/statements/:month", wrapper.GetMyStatement)
+	router.GET(options.BaseURL+"/couriers/me/vehicle", wrapper.GetMyVehicle)
+	router.PUT(options.BaseURL+"/couriers/me/vehicle", wrapper.UpdateMyVehicle)
+	router.PUT(options.BaseURL+"/couriers/:id/active-limit", wrapper.SetCourierActiveLimit)
+	router.PUT(options.BaseURL+"/couriers/:id/zones", wrapper.SetCourierZones)
 	router.GET(options.BaseURL+"/deliveries", wrapper.ListDeliveries)
 	router.POST(options.BaseURL+"/deliveries", wrapper.CreateDelivery)
+	router.GET(options.BaseURL+"/deliveries/bundles", wrapper.ListDeliveryBundles)
+	router.POST(options.BaseURL+"/deliveries/bundles/accept", wrapper.AcceptDeliveryBundle)
+	router.POST(options.BaseURL+"/deliveries/quote", wrapper.QuoteDelivery)
+	router.GET(options.BaseURL+"/deliveries/stream", wrapper.StreamDeliveries)
 	router.PATCH(options.BaseURL+"/deliveries/:id", wrapper.UpdateDelivery)
 	router.POST(options.BaseURL+"/deliveries/:id/accept", wrapper.AcceptDelivery)
+	router.POST(options.BaseURL+"/deliveries/:id/parcels/:label/scans", wrapper.ScanParcel)
+	router.POST(options.BaseURL+"/deliveries/:id/rating", wrapper.RateDelivery)
+	router.POST(options.BaseURL+"/deliveries/:id/tip", wrapper.TipDelivery)
+	router.GET(options.BaseURL+"/delivery-templates", wrapper.ListDeliveryTemplates)
+	router.POST(options.BaseURL+"/delivery-templates", wrapper.CreateDeliveryTemplate)
+	router.DELETE(options.BaseURL+"/delivery-templates/:id", wrapper.DeleteDeliveryTemplate)
+	router.GET(options.BaseURL+"/delivery-templates/:id", wrapper.GetDeliveryTemplate)
+	router.PUT(options.BaseURL+"/delivery-templates/:id", wrapper.UpdateDeliveryTemplate)
+	router.GET(options.BaseURL+"/delivery-templates/:id/occurrences", wrapper.ListDeliveryTemplateOccurrences)
+	router.POST(options.BaseURL+"/delivery-templates/:id/pause", wrapper.PauseDeliveryTemplate)
+	router.POST(options.BaseURL+"/delivery-templates/:id/resume", wrapper.ResumeDeliveryTemplate)
+	router.POST(options.BaseURL+"/invoices/generate", wrapper.GenerateInvoices)
 	router.GET(options.BaseURL+"/me", wrapper.GetMe)
+	router.GET(options.BaseURL+"/me/notification-preferences", wrapper.GetMyNotificationPreferences)
+	router.PUT(options.BaseURL+"/me/notification-preferences", wrapper.UpdateMyNotificationPreferences)
+	router.GET(options.BaseURL+"/payouts", wrapper.ListPayouts)
+	router.POST(options.BaseURL+"/payouts/batches", wrapper.CreatePayoutBatch)
+	router.GET(options.BaseURL+"/payouts/batches/:id/export", wrapper.ExportPayoutBatch)
+	router.POST(options.BaseURL+"/payouts/:id/approve", wrapper.ApprovePayout)
+	router.POST(options.BaseURL+"/payouts/:id/reject", wrapper.RejectPayout)
+	router.GET(options.BaseURL+"/push/vapid-public-key", wrapper.GetVapidPublicKey)
+	router.GET(options.BaseURL+"/track/:token", wrapper.TrackDelivery)
+	router.GET(options.BaseURL+"/webhooks", wrapper.ListWebhooks)
+	router.POST(options.BaseURL+"/webhooks", wrapper.CreateWebhook)
+	router.GET(options.BaseURL+"/webhooks/dead-letters", wrapper.ListWebhookDeadLetters)
+	router.DELETE(options.BaseURL+"/webhooks/:id", wrapper.DeleteWebhook)
+	router.POST(options.BaseURL+"/webhooks/:id/test", wrapper.TestWebhook)
+	router.GET(options.BaseURL+"/zones", wrapper.ListZones)
+	router.POST(options.BaseURL+"/zones", wrapper.CreateZone)
+	router.DELETE(options.BaseURL+"/zones/:id", wrapper.DeleteZone)
+	router.GET(options.BaseURL+"/zones/:id", wrapper.GetZone)
+	router.PUT(options.BaseURL+"/zones/:id", wrapper.UpdateZone)
 }
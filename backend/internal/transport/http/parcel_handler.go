@@ -0,0 +1,43 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// POST /deliveries/{id}/parcels/{label}/scans
+// the assigned courier scans a parcel at pickup or drop-off, or reports it missing.
+func (h *Handler) ScanParcel(c *gin.Context, deliveryID string, label string) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	var req ScanParcelJSONRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	scan := api.ParcelScanCreate{Event: api.ParcelScanEvent(req.Event), Note: req.Note}
+	switch scan.Event {
+	case api.ParcelScanEventPickedUp, api.ParcelScanEventDelivered, api.ParcelScanEventMissing:
+	default:
+		c.JSON(http.StatusBadRequest, errBody(errors.New("event must be picked_up, delivered or missing")))
+		return
+	}
+
+	updated, err := h.deliverySvc.ScanParcel(c, deliveryID, label, courierUID, scan)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, service.RedactForCourier(*updated, courierUID))
+	case errors.Is(err, service.ErrDeliveryNotFound), errors.Is(err, service.ErrParcelNotFound):
+		c.JSON(http.StatusNotFound, errBody(err))
+	case errors.Is(err, service.ErrInvalidUpdate), errors.Is(err, service.ErrInvalidScan):
+		c.JSON(http.StatusBadRequest, errBody(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errBody(err))
+	}
+}
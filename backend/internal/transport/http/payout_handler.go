@@ -0,0 +1,199 @@
+package httptransport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// payoutErrStatus maps payout domain errors to HTTP status codes.
+func payoutErrStatus(err error) int {
+	var bad service.ErrInvalidTransition
+	switch {
+	case errors.As(err, &bad),
+		errors.Is(err, service.ErrInvalidAmount),
+		errors.Is(err, service.ErrInsufficientBalance),
+		errors.Is(err, service.ErrInvalidIBAN),
+		errors.Is(err, service.ErrNoAccountHolder),
+		errors.Is(err, service.ErrNoApprovedPayouts),
+		errors.Is(err, service.ErrUnsupportedFormat):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// requireAdmin resolves the caller and writes 401 unless it is an admin.
+func (h *Handler) requireAdmin(c *gin.Context) (string, bool) {
+	uid := c.GetString("uid")
+	role, err := h.userSvc.GetUserRole(context.Background(), uid)
+	if err != nil || role != "admin" {
+		c.JSON(http.StatusUnauthorized, errBody(errors.New("unauthorized")))
+		return "", false
+	}
+	return uid, true
+}
+
+// requireCourier resolves the caller and rejects anyone who isn't a courier.
+func (h *Handler) requireCourier(c *gin.Context) (string, bool) {
+	uid := c.GetString("uid")
+	if uid == "" {
+		c.JSON(http.StatusUnauthorized, errBody(errors.New("missing auth UID")))
+		return "", false
+	}
+	role, err := h.userSvc.GetUserRole(context.Background(), uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return "", false
+	}
+	if role != "courier" {
+		c.JSON(http.StatusBadRequest, errBody(errors.New("only a courier can do this")))
+		return "", false
+	}
+	return uid, true
+}
+
+// requireBusiness resolves the caller and rejects anyone who isn't a business.
+func (h *Handler) requireBusiness(c *gin.Context) (string, bool) {
+	uid := c.GetString("uid")
+	if uid == "" {
+		c.JSON(http.StatusUnauthorized, errBody(errors.New("missing auth UID")))
+		return "", false
+	}
+	role, err := h.userSvc.GetUserRole(context.Background(), uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return "", false
+	}
+	if role != "business" {
+		c.JSON(http.StatusBadRequest, errBody(errors.New("only a business can do this")))
+		return "", false
+	}
+	return uid, true
+}
+
+// POST /couriers/me/payouts
+// courier asks to withdraw part of its available balance (balance - reservedBalance).
+func (h *Handler) RequestPayout(c *gin.Context) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	var req PayoutCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	payout, err := h.payoutSvc.RequestPayout(c, courierUID, &api.PayoutCreate{
+		Amount:        req.Amount,
+		Iban:          req.Iban,
+		AccountHolder: req.AccountHolder,
+	})
+	if err != nil {
+		c.JSON(payoutErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, payout)
+}
+
+// GET /couriers/me/payouts
+// lists the caller's own payout requests.
+func (h *Handler) ListMyPayouts(c *gin.Context) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	payouts, err := h.payoutSvc.ListPayouts(c, courierUID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, payouts)
+}
+
+// GET /payouts
+// lists all payout requests, optionally by status; admin only.
+func (h *Handler) ListPayouts(c *gin.Context, params ListPayoutsParams) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	var status *string
+	if params.Status != nil {
+		s := string(*params.Status)
+		status = &s
+	}
+	payouts, err := h.payoutSvc.ListPayouts(c, "", status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, payouts)
+}
+
+// POST /payouts/{id}/approve
+func (h *Handler) ApprovePayout(c *gin.Context, id string) {
+	adminUID, ok := h.requireAdmin(c)
+	if !ok { return }
+
+	payout, err := h.payoutSvc.ApprovePayout(c, id, adminUID)
+	if err != nil {
+		c.JSON(payoutErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, payout)
+}
+
+// POST /payouts/{id}/reject
+// body is optional; a reason is stored on the payout when given.
+func (h *Handler) RejectPayout(c *gin.Context, id string) {
+	adminUID, ok := h.requireAdmin(c)
+	if !ok { return }
+
+	var req PayoutReject
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errBody(err))
+			return
+		}
+	}
+
+	payout, err := h.payoutSvc.RejectPayout(c, id, adminUID, req.Reason)
+	if err != nil {
+		c.JSON(payoutErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, payout)
+}
+
+// POST /payouts/batches
+// settles every approved payout in one batch.
+func (h *Handler) CreatePayoutBatch(c *gin.Context) {
+	adminUID, ok := h.requireAdmin(c)
+	if !ok { return }
+
+	batch, err := h.payoutSvc.CreatePayoutBatch(c, adminUID)
+	if err != nil {
+		c.JSON(payoutErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, batch)
+}
+
+// GET /payouts/batches/{id}/export
+// downloads the batch as CSV (default) or SEPA XML.
+func (h *Handler) ExportPayoutBatch(c *gin.Context, id string, params ExportPayoutBatchParams) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	format := ""
+	if params.Format != nil { format = string(*params.Format) }
+
+	data, contentType, fileName, err := h.payoutSvc.ExportPayoutBatch(c, id, format)
+	if err != nil {
+		c.JSON(payoutErrStatus(err), errBody(err))
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Data(http.StatusOK, contentType, data)
+}
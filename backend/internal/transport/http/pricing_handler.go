@@ -0,0 +1,35 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// POST /deliveries/quote
+// routes and prices a trip for the calling business before it posts the delivery.
+func (h *Handler) QuoteDelivery(c *gin.Context) {
+	if _, ok := h.requireBusiness(c); !ok { return }
+
+	var req DeliveryQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	quote, err := h.deliverySvc.QuoteDelivery(c, &api.DeliveryQuoteRequest{
+		BusinessLocation:    api.GeoPoint{Lat: req.BusinessLocation.Lat, Lng: req.BusinessLocation.Lng},
+		DestinationLocation: api.GeoPoint{Lat: req.DestinationLocation.Lat, Lng: req.DestinationLocation.Lng},
+	})
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, quote)
+	case errors.Is(err, service.ErrInvalidLocation), errors.Is(err, service.ErrOutsideCoverage):
+		c.JSON(http.StatusBadRequest, errBody(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errBody(err))
+	}
+}
@@ -0,0 +1,87 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// pushErrStatus maps web push errors to HTTP status codes.
+func pushErrStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrPushDisabled):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, service.ErrPushSubscriptionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidPushSubscription),
+		errors.Is(err, service.ErrInvalidPushRadius):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GET /push/vapid-public-key
+// returns the applicationServerKey the courier app subscribes with.
+func (h *Handler) GetVapidPublicKey(c *gin.Context) {
+	key, err := h.pushSvc.VAPIDPublicKey()
+	if err != nil {
+		c.JSON(pushErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, VapidPublicKey{PublicKey: key})
+}
+
+// GET /couriers/me/push-subscriptions
+// lists the caller's subscribed browsers.
+func (h *Handler) ListMyPushSubscriptions(c *gin.Context) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	subs, err := h.pushSvc.ListSubscriptions(c, courierUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+// POST /couriers/me/push-subscriptions
+// stores the PushSubscription of the caller's browser and the radius they want jobs from.
+func (h *Handler) CreatePushSubscription(c *gin.Context) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	var req PushSubscriptionCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	sub, err := h.pushSvc.CreateSubscription(c, courierUID, &api.PushSubscriptionCreate{
+		Endpoint: req.Endpoint,
+		Keys:     api.PushSubscriptionKeys{P256dh: req.Keys.P256dh, Auth: req.Keys.Auth},
+		RadiusKm: req.RadiusKm,
+	})
+	if err != nil {
+		c.JSON(pushErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, sub)
+}
+
+// DELETE /couriers/me/push-subscriptions/{id}
+// unsubscribes one of the caller's browsers.
+func (h *Handler) DeletePushSubscription(c *gin.Context, id string) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	if err := h.pushSvc.DeleteSubscription(c, courierUID, id); err != nil {
+		c.JSON(pushErrStatus(err), errBody(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
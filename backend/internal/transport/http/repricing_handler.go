@@ -0,0 +1,48 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GET /businesses/me/repricing
+// returns whether the calling business lets unaccepted deliveries be repriced, and how far.
+func (h *Handler) GetMyRepricing(c *gin.Context) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	r, err := h.userSvc.GetRepricing(c, businessUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+// PUT /businesses/me/repricing
+// gives or withdraws consent to raising the payment of deliveries no courier accepts.
+func (h *Handler) UpdateMyRepricing(c *gin.Context) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	var req UpdateMyRepricingJSONRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	r, err := h.userSvc.SetRepricing(c, businessUID, (*api.BusinessRepricing)(&req))
+	if errors.Is(err, service.ErrInvalidRepricing) {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
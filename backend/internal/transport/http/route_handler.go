@@ -0,0 +1,27 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GET /couriers/me/route
+// suggests the order in which the calling courier rides their pickups and drop-offs.
+func (h *Handler) GetMyRoute(c *gin.Context) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	route, err := h.deliverySvc.PlanCourierRoute(c, courierUID)
+	if errors.Is(err, service.ErrUnreachableStops) {
+		c.JSON(http.StatusUnprocessableEntity, errBody(err))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, route)
+}
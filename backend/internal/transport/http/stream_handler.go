@@ -0,0 +1,85 @@
+package httptransport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// sseHeartbeat keeps idle streams alive through proxies that drop silent connections.
+const sseHeartbeat = 25 * time.Second
+
+// GET /deliveries/stream
+// Server-Sent Events of delivery changes the caller may see, with the same role rules
+// as ListDeliveries. A reconnecting client sends Last-Event-ID and gets what it missed first.
+func (h *Handler) StreamDeliveries(c *gin.Context, params StreamDeliveriesParams) {
+	flt := service.ListFilter{}
+	if params.Lat != nil && params.Lng != nil && params.R != nil {
+		flt.CenterLat = params.Lat
+		flt.CenterLng = params.Lng
+		flt.RadiusKm  = params.R
+	}
+	userUID := c.GetString("uid")
+	if err := h.applyRole(context.Background(), &flt, userUID); err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	lastEventID := ""
+	if params.LastEventID != nil { lastEventID = *params.LastEventID }
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx: don't buffer the stream
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	c.Writer.Flush()
+
+	// the Firestore listener runs in its own goroutine; this one owns the response writer
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	events := make(chan *service.DeliveryEvent, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- h.deliverySvc.StreamDeliveryEvents(ctx, flt, lastEventID, func(e *service.DeliveryEvent) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case err := <-done:
+			// the client reconnects on its own and resumes from its last event id
+			if err != nil { log.Printf("delivery stream for %s: %v", userUID, err) }
+			return
+
+		case e := <-events:
+			d := e.Delivery
+			if flt.Role == "courier" { d = service.RedactForCourier(d, userUID) }
+			data, err := json.Marshal(d)
+			if err != nil { continue }
+			fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", e.Id, e.Type, data)
+			c.Writer.Flush()
+
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
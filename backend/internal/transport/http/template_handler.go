@@ -0,0 +1,157 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// templateErrStatus maps delivery template errors to HTTP status codes.
+func templateErrStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidTemplate), errors.Is(err, service.ErrInvalidRecurrence),
+		errors.Is(err, service.ErrInvalidRecipient), errors.Is(err, service.ErrInvalidParcel),
+		errors.Is(err, service.ErrOutsideCoverage):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// templateCreate converts the request body to the domain type.
+func templateCreate(req *DeliveryTemplateCreate) *api.DeliveryTemplateCreate {
+	return &api.DeliveryTemplateCreate{
+		Name:                 req.Name,
+		Recurrence:           req.Recurrence,
+		Timezone:             req.Timezone,
+		DeliverWithinMinutes: req.DeliverWithinMinutes,
+		DestinationAddress:   req.DestinationAddress,
+		DestinationLocation:  api.GeoPoint{Lat: req.DestinationLocation.Lat, Lng: req.DestinationLocation.Lng},
+		Item:                 req.Item,
+		Payment:              req.Payment,
+		RecipientName:        req.RecipientName,
+		RecipientPhone:       req.RecipientPhone,
+		RecipientEmail:       req.RecipientEmail,
+		Size:                 (*api.DeliverySize)(req.Size),
+		WeightKg:             req.WeightKg,
+	}
+}
+
+// GET /delivery-templates
+// lists the caller's recurring delivery templates.
+func (h *Handler) ListDeliveryTemplates(c *gin.Context) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	list, err := h.templateSvc.ListTemplates(c, businessUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// POST /delivery-templates
+// stores a delivery the business sends on a schedule; the scheduler posts each occurrence.
+func (h *Handler) CreateDeliveryTemplate(c *gin.Context) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	var req DeliveryTemplateCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+	t, err := h.templateSvc.CreateTemplate(c, businessUID, templateCreate(&req))
+	if err != nil {
+		c.JSON(templateErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// GET /delivery-templates/{id}
+func (h *Handler) GetDeliveryTemplate(c *gin.Context, id string) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	t, err := h.templateSvc.GetTemplate(c, id, businessUID)
+	if err != nil {
+		c.JSON(templateErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// PUT /delivery-templates/{id}
+func (h *Handler) UpdateDeliveryTemplate(c *gin.Context, id string) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	var req DeliveryTemplateCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+	t, err := h.templateSvc.UpdateTemplate(c, id, businessUID, templateCreate(&req))
+	if err != nil {
+		c.JSON(templateErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// DELETE /delivery-templates/{id}
+func (h *Handler) DeleteDeliveryTemplate(c *gin.Context, id string) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	if err := h.templateSvc.DeleteTemplate(c, id, businessUID); err != nil {
+		c.JSON(templateErrStatus(err), errBody(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// POST /delivery-templates/{id}/pause
+func (h *Handler) PauseDeliveryTemplate(c *gin.Context, id string) {
+	h.setTemplatePaused(c, id, true)
+}
+
+// POST /delivery-templates/{id}/resume
+func (h *Handler) ResumeDeliveryTemplate(c *gin.Context, id string) {
+	h.setTemplatePaused(c, id, false)
+}
+
+func (h *Handler) setTemplatePaused(c *gin.Context, id string, paused bool) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	t, err := h.templateSvc.SetPaused(c, id, businessUID, paused)
+	if err != nil {
+		c.JSON(templateErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// GET /delivery-templates/{id}/occurrences
+// lists when the template's next deliveries will be picked up.
+func (h *Handler) ListDeliveryTemplateOccurrences(c *gin.Context, id string, params ListDeliveryTemplateOccurrencesParams) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	count := 0
+	if params.Count != nil { count = *params.Count }
+	list, err := h.templateSvc.Occurrences(c, id, businessUID, count)
+	if err != nil {
+		c.JSON(templateErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
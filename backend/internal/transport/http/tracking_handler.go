@@ -0,0 +1,24 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GET /track/{token}
+// public: the tracking token is the only credential, so no caller identity is read here.
+func (h *Handler) TrackDelivery(c *gin.Context, token string) {
+	tracking, err := h.deliverySvc.GetTracking(c, token)
+	switch {
+	case err == nil:
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, tracking)
+	case errors.Is(err, service.ErrTrackingNotFound):
+		c.JSON(http.StatusNotFound, errBody(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errBody(err))
+	}
+}
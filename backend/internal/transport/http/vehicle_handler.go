@@ -0,0 +1,81 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GET /couriers/me/vehicle
+// returns the calling courier's vehicle with its capacities.
+func (h *Handler) GetMyVehicle(c *gin.Context) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	v, err := h.userSvc.GetVehicle(c, courierUID)
+	if errors.Is(err, service.ErrNoVehicle) {
+		c.JSON(http.StatusNotFound, errBody(err))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// PUT /couriers/me/vehicle
+// sets the calling courier's vehicle; capacities may only be lowered from the type's defaults.
+func (h *Handler) UpdateMyVehicle(c *gin.Context) {
+	courierUID, ok := h.requireCourier(c)
+	if !ok { return }
+
+	var req UpdateMyVehicleJSONRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	v, err := h.userSvc.SetVehicle(c, courierUID, &api.CourierVehicle{
+		Type:        api.VehicleType(req.Type),
+		MaxJobs:     req.MaxJobs,
+		MaxWeightKg: req.MaxWeightKg,
+		MaxVolumeL:  req.MaxVolumeL,
+	})
+	if errors.Is(err, service.ErrInvalidVehicle) {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// PUT /couriers/{id}/active-limit
+// lets an admin raise or lower one courier's limit of active deliveries, or clear it (null).
+func (h *Handler) SetCourierActiveLimit(c *gin.Context, id string) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	var req SetCourierActiveLimitJSONRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	courier, err := h.userSvc.SetActiveLimit(c, id, req.MaxActiveDeliveries)
+	switch {
+	case errors.Is(err, service.ErrInvalidActiveLimit):
+		c.JSON(http.StatusBadRequest, errBody(err))
+	case errors.Is(err, service.ErrCourierNotFound):
+		c.JSON(http.StatusNotFound, errBody(err))
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, errBody(err))
+	default:
+		c.JSON(http.StatusOK, courier)
+	}
+}
@@ -0,0 +1,105 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// webhookErrStatus maps webhook errors to HTTP status codes.
+func webhookErrStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidWebhookURL),
+		errors.Is(err, service.ErrInvalidWebhookEvent):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GET /webhooks
+// lists the caller's webhook registrations.
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	hooks, err := h.webhookSvc.ListWebhooks(c, businessUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, hooks)
+}
+
+// POST /webhooks
+// registers a URL to be called on the chosen delivery lifecycle events.
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	var req WebhookCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+	events := make([]api.WebhookEvent, len(req.Events))
+	for i, e := range req.Events {
+		events[i] = api.WebhookEvent(e)
+	}
+
+	hook, err := h.webhookSvc.CreateWebhook(c, businessUID, &api.WebhookCreate{
+		Url:    req.Url,
+		Events: events,
+		Secret: req.Secret,
+	})
+	if err != nil {
+		c.JSON(webhookErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, hook)
+}
+
+// GET /webhooks/dead-letters
+// lists the caller's webhook calls that ran out of retries.
+func (h *Handler) ListWebhookDeadLetters(c *gin.Context) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	dead, err := h.webhookSvc.ListDeadLetters(c, businessUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, dead)
+}
+
+// DELETE /webhooks/{id}
+func (h *Handler) DeleteWebhook(c *gin.Context, id string) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	if err := h.webhookSvc.DeleteWebhook(c, id, businessUID); err != nil {
+		c.JSON(webhookErrStatus(err), errBody(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// POST /webhooks/{id}/test
+// sends one signed test call and returns its outcome, so integrators can check their endpoint.
+func (h *Handler) TestWebhook(c *gin.Context, id string) {
+	businessUID, ok := h.requireBusiness(c)
+	if !ok { return }
+
+	result, err := h.webhookSvc.TestWebhook(c, id, businessUID)
+	if err != nil {
+		c.JSON(webhookErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,131 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Evap1/courier-system/backend/api"
+	"github.com/Evap1/courier-system/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// zoneErrStatus maps zone errors to HTTP status codes.
+func zoneErrStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrZoneNotFound), errors.Is(err, service.ErrCourierNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidZone),
+		errors.Is(err, service.ErrInvalidZoneRules),
+		errors.Is(err, service.ErrUnknownZone):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// zoneCreate converts the transport body into the service's request type.
+func zoneCreate(req *ZoneCreate) *api.ZoneCreate {
+	out := &api.ZoneCreate{
+		Name:       req.Name,
+		Active:     req.Active,
+		Polygon:    api.ZonePolygon{Type: api.ZonePolygonType(req.Polygon.Type), Coordinates: req.Polygon.Coordinates},
+		Timezone:   req.Timezone,
+		MinPayment: req.MinPayment,
+		Pricing:    (*api.ZonePricing)(req.Pricing),
+	}
+	if req.Hours != nil {
+		hours := make([]api.ZoneHours, len(*req.Hours))
+		for i, h := range *req.Hours {
+			hours[i] = api.ZoneHours(h)
+		}
+		out.Hours = &hours
+	}
+	return out
+}
+
+// GET /zones
+// lists the service zones; every signed-in user may see where deliveries are possible.
+func (h *Handler) ListZones(c *gin.Context) {
+	zones, err := h.zoneSvc.ListZones(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, zones)
+}
+
+// POST /zones
+func (h *Handler) CreateZone(c *gin.Context) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	var req ZoneCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	zone, err := h.zoneSvc.CreateZone(c, zoneCreate(&req))
+	if err != nil {
+		c.JSON(zoneErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusCreated, zone)
+}
+
+// GET /zones/{id}
+func (h *Handler) GetZone(c *gin.Context, id string) {
+	zone, err := h.zoneSvc.GetZone(c, id)
+	if err != nil {
+		c.JSON(zoneErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, zone)
+}
+
+// PUT /zones/{id}
+func (h *Handler) UpdateZone(c *gin.Context, id string) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	var req ZoneCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	zone, err := h.zoneSvc.UpdateZone(c, id, zoneCreate(&req))
+	if err != nil {
+		c.JSON(zoneErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, zone)
+}
+
+// DELETE /zones/{id}
+func (h *Handler) DeleteZone(c *gin.Context, id string) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	if err := h.zoneSvc.DeleteZone(c, id); err != nil {
+		c.JSON(zoneErrStatus(err), errBody(err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PUT /couriers/{id}/zones
+// lets an admin restrict a courier to some zones, or lift the restriction with an empty list.
+func (h *Handler) SetCourierZones(c *gin.Context, id string) {
+	if _, ok := h.requireAdmin(c); !ok { return }
+
+	var req CourierZones
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errBody(err))
+		return
+	}
+
+	courier, err := h.zoneSvc.AssignCourier(c, id, req.ZoneIds)
+	if err != nil {
+		c.JSON(zoneErrStatus(err), errBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, courier)
+}